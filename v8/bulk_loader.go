@@ -0,0 +1,560 @@
+package gorocksdb
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// BulkLoadDuplicatePolicy specifies how a BulkLoader resolves a key
+// that was added more than once.
+type BulkLoadDuplicatePolicy int
+
+const (
+	// BulkLoadLastWriteWins keeps the value of the last Add call for the key.
+	BulkLoadLastWriteWins = BulkLoadDuplicatePolicy(0)
+	// BulkLoadMerge combines the values of the key, in the order they were
+	// added, with the FullMerge method of BulkLoaderOptions.MergeOperator.
+	// Keys added only once are written as is.
+	BulkLoadMerge = BulkLoadDuplicatePolicy(1)
+)
+
+// BulkLoadPhase is the phase a BulkLoader is in.
+type BulkLoadPhase int
+
+const (
+	// BulkLoadAdding is the phase in which key/value pairs are added and
+	// spilled to sorted runs.
+	BulkLoadAdding = BulkLoadPhase(0)
+	// BulkLoadWriting is the phase in which the sorted runs are merged and
+	// written to sst files.
+	BulkLoadWriting = BulkLoadPhase(1)
+	// BulkLoadIngesting is the phase in which the sst files are ingested.
+	BulkLoadIngesting = BulkLoadPhase(2)
+	// BulkLoadDone is reported once the sst files have been ingested.
+	BulkLoadDone = BulkLoadPhase(3)
+)
+
+// BulkLoadProgress reports the progress of a BulkLoader.
+type BulkLoadProgress struct {
+	Phase BulkLoadPhase
+	// KeysAdded is the number of key/value pairs passed to Add. It is
+	// updated whenever a sorted run is spilled.
+	KeysAdded uint64
+	// SortedRuns is the number of sorted runs spilled to temporary files.
+	SortedRuns int
+	// KeysWritten is the number of distinct keys written to sst files.
+	KeysWritten uint64
+	// FilesWritten is the number of sst files written.
+	FilesWritten int
+}
+
+// BulkLoaderOptions configure a BulkLoader. The zero value is usable.
+type BulkLoaderOptions struct {
+	// TempDir is the directory in which sorted runs and sst files are
+	// created.
+	// Default: os.TempDir()
+	TempDir string
+	// MemoryBudget is the approximate number of bytes buffered in memory
+	// before they are sorted and spilled to a temporary file.
+	// Default: 64MB
+	MemoryBudget int
+	// Parallelism is the number of sst files written concurrently.
+	// Default: 4
+	Parallelism int
+	// TargetFileSize is the approximate amount of key/value bytes
+	// written to each sst file.
+	// Default: 64MB
+	TargetFileSize int
+	// DuplicatePolicy specifies how keys added more than once are resolved.
+	// Default: BulkLoadLastWriteWins
+	DuplicatePolicy BulkLoadDuplicatePolicy
+	// MergeOperator combines duplicate keys when DuplicatePolicy is BulkLoadMerge.
+	MergeOperator MergeOperator
+	// IngestBehind ingests the files at the bottommost level, skipping keys
+	// that already exist in the database. It requires a database opened
+	// with SetAllowIngestBehind(true).
+	// Default: false
+	IngestBehind bool
+	// Progress, if set, is called whenever the progress of the load changes.
+	// Calls are serialized.
+	Progress func(BulkLoadProgress)
+}
+
+// BulkLoader loads large amounts of unsorted key/value pairs into a
+// database. Pairs are buffered and spilled to sorted runs, merged with the
+// comparator of the column family, written to non-overlapping sst files in
+// parallel and then ingested with a single IngestExternalFileCF call.
+//
+// Add may be called from many goroutines. Finish loads the data and
+// Destroy removes the temporary files.
+type BulkLoader struct {
+	db      *DB
+	cf      *ColumnFamilyHandle
+	cfOpts  *Options
+	opts    BulkLoaderOptions
+	compare func(a, b []byte) int
+	dir     string
+
+	mu       sync.Mutex
+	buf      []bulkLoadEntry
+	bufSize  int
+	seq      uint64
+	runs     []string
+	finished bool
+	err      error
+	spills   sync.WaitGroup
+
+	progressMu sync.Mutex
+	progress   BulkLoadProgress
+}
+
+type bulkLoadEntry struct {
+	key, value []byte
+	seq        uint64
+}
+
+// bulkLoadEntryOverhead is the approximate memory used by an entry besides
+// its key and value.
+const bulkLoadEntryOverhead = 64
+
+var errBulkLoaderFinished = errors.New("bulk loader is already finished")
+
+// NewBulkLoader creates a BulkLoader loading into the column family cf of db.
+// If cf is nil, the default column family is used. cfOpts must be the
+// options the column family was opened with; if nil the options of db are used.
+func NewBulkLoader(db *DB, cf *ColumnFamilyHandle, cfOpts *Options, opts BulkLoaderOptions) (*BulkLoader, error) {
+	if cfOpts == nil {
+		cfOpts = db.opts
+	}
+	compare := optionsCompare(cfOpts)
+	if compare == nil {
+		return nil, errors.New("bulk loader requires a comparator that can be evaluated from Go")
+	}
+	if opts.DuplicatePolicy == BulkLoadMerge && opts.MergeOperator == nil {
+		return nil, errors.New("bulk loader requires a merge operator to merge duplicate keys")
+	}
+	if opts.MemoryBudget <= 0 {
+		opts.MemoryBudget = 64 << 20
+	}
+	if opts.Parallelism <= 0 {
+		opts.Parallelism = 4
+	}
+	if opts.TargetFileSize <= 0 {
+		opts.TargetFileSize = 64 << 20
+	}
+	dir, err := os.MkdirTemp(opts.TempDir, "gorocksdb-bulkload-")
+	if err != nil {
+		return nil, err
+	}
+	return &BulkLoader{
+		db:      db,
+		cf:      cf,
+		cfOpts:  cfOpts,
+		opts:    opts,
+		compare: compare,
+		dir:     dir,
+	}, nil
+}
+
+// Add adds a key/value pair. The key and value are copied.
+// When the memory budget is exhausted, the calling goroutine spills
+// the buffered pairs to a sorted run.
+func (l *BulkLoader) Add(key, value []byte) error {
+	e := bulkLoadEntry{
+		key:   append([]byte(nil), key...),
+		value: append([]byte(nil), value...),
+	}
+
+	l.mu.Lock()
+	if l.finished {
+		l.mu.Unlock()
+		return errBulkLoaderFinished
+	}
+	if l.err != nil {
+		err := l.err
+		l.mu.Unlock()
+		return err
+	}
+	e.seq = l.seq
+	l.seq++
+	l.buf = append(l.buf, e)
+	l.bufSize += len(e.key) + len(e.value) + bulkLoadEntryOverhead
+	var full []bulkLoadEntry
+	if l.bufSize >= l.opts.MemoryBudget {
+		full = l.buf
+		l.buf, l.bufSize = nil, 0
+		l.spills.Add(1)
+	}
+	l.mu.Unlock()
+
+	if full != nil {
+		return l.spill(full)
+	}
+	return nil
+}
+
+// spill sorts entries and writes them to a new sorted run.
+func (l *BulkLoader) spill(entries []bulkLoadEntry) error {
+	defer l.spills.Done()
+	path, err := l.writeRun(entries)
+
+	l.mu.Lock()
+	if err != nil {
+		if l.err == nil {
+			l.err = err
+		}
+		l.mu.Unlock()
+		return err
+	}
+	l.runs = append(l.runs, path)
+	l.mu.Unlock()
+
+	l.updateProgress(func(p *BulkLoadProgress) {
+		p.KeysAdded += uint64(len(entries))
+		p.SortedRuns++
+	})
+	return nil
+}
+
+func (l *BulkLoader) writeRun(entries []bulkLoadEntry) (string, error) {
+	sort.Slice(entries, func(i, j int) bool {
+		return l.less(&entries[i], &entries[j])
+	})
+	f, err := os.CreateTemp(l.dir, "run-")
+	if err != nil {
+		return "", err
+	}
+	w := bufio.NewWriter(f)
+	var hdr [3 * binary.MaxVarintLen64]byte
+	for i := range entries {
+		e := &entries[i]
+		n := binary.PutUvarint(hdr[:], uint64(len(e.key)))
+		n += binary.PutUvarint(hdr[n:], uint64(len(e.value)))
+		n += binary.PutUvarint(hdr[n:], e.seq)
+		w.Write(hdr[:n])
+		w.Write(e.key)
+		w.Write(e.value)
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// less orders entries by key and then by the order they were added in.
+func (l *BulkLoader) less(a, b *bulkLoadEntry) bool {
+	if c := l.compare(a.key, b.key); c != 0 {
+		return c < 0
+	}
+	return a.seq < b.seq
+}
+
+func (l *BulkLoader) updateProgress(update func(p *BulkLoadProgress)) {
+	l.progressMu.Lock()
+	defer l.progressMu.Unlock()
+	update(&l.progress)
+	if l.opts.Progress != nil {
+		l.opts.Progress(l.progress)
+	}
+}
+
+// Finish merges the added pairs into sst files and ingests them.
+// No pairs can be added afterwards.
+func (l *BulkLoader) Finish() error {
+	l.mu.Lock()
+	if l.finished {
+		l.mu.Unlock()
+		return errBulkLoaderFinished
+	}
+	l.finished = true
+	rest := l.buf
+	l.buf, l.bufSize = nil, 0
+	if len(rest) > 0 {
+		l.spills.Add(1)
+	}
+	l.mu.Unlock()
+
+	if len(rest) > 0 {
+		l.spill(rest)
+	}
+	l.spills.Wait()
+	l.mu.Lock()
+	err := l.err
+	l.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	l.updateProgress(func(p *BulkLoadProgress) { p.Phase = BulkLoadWriting })
+	files, err := l.writeFiles()
+	if err != nil {
+		return err
+	}
+
+	if len(files) > 0 {
+		l.updateProgress(func(p *BulkLoadProgress) { p.Phase = BulkLoadIngesting })
+		ingestOpts := NewDefaultIngestExternalFileOptions()
+		defer ingestOpts.Destroy()
+		ingestOpts.SetMoveFiles(true)
+		ingestOpts.SetIngestionBehind(l.opts.IngestBehind)
+		if l.cf != nil {
+			err = l.db.IngestExternalFileCF(l.cf, files, ingestOpts)
+		} else {
+			err = l.db.IngestExternalFile(files, ingestOpts)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	l.updateProgress(func(p *BulkLoadProgress) { p.Phase = BulkLoadDone })
+	return nil
+}
+
+// writeFiles merges the sorted runs and writes the resulting keys to sst
+// files, using up to Parallelism writers. It returns the paths of the files.
+func (l *BulkLoader) writeFiles() ([]string, error) {
+	runs := make([]*bulkLoadRunReader, 0, len(l.runs))
+	defer func() {
+		for _, r := range runs {
+			r.f.Close()
+		}
+	}()
+	for _, path := range l.runs {
+		r, err := newBulkLoadRunReader(path)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, r)
+	}
+
+	type chunk struct {
+		path    string
+		entries []bulkLoadEntry
+	}
+	var (
+		chunks   = make(chan chunk)
+		wg       sync.WaitGroup
+		errMu    sync.Mutex
+		writeErr error
+		files    []string
+	)
+	setErr := func(err error) {
+		errMu.Lock()
+		defer errMu.Unlock()
+		if writeErr == nil {
+			writeErr = err
+		}
+	}
+	getErr := func() error {
+		errMu.Lock()
+		defer errMu.Unlock()
+		return writeErr
+	}
+
+	envOpts := NewDefaultEnvOptions()
+	defer envOpts.Destroy()
+	for i := 0; i < l.opts.Parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range chunks {
+				if getErr() != nil {
+					continue
+				}
+				if err := l.writeFile(envOpts, c.path, c.entries); err != nil {
+					setErr(err)
+					continue
+				}
+				l.updateProgress(func(p *BulkLoadProgress) {
+					p.FilesWritten++
+					p.KeysWritten += uint64(len(c.entries))
+				})
+			}
+		}()
+	}
+
+	var (
+		current     []bulkLoadEntry
+		currentSize int
+	)
+	flush := func() {
+		path := filepath.Join(l.dir, fmt.Sprintf("%06d.sst", len(files)))
+		files = append(files, path)
+		chunks <- chunk{path, current}
+		current, currentSize = nil, 0
+	}
+	err := l.mergeRuns(runs, func(e bulkLoadEntry) error {
+		if err := getErr(); err != nil {
+			return err
+		}
+		current = append(current, e)
+		currentSize += len(e.key) + len(e.value)
+		if currentSize >= l.opts.TargetFileSize {
+			flush()
+		}
+		return nil
+	})
+	if err == nil && len(current) > 0 {
+		flush()
+	}
+	close(chunks)
+	wg.Wait()
+	if err != nil {
+		return nil, err
+	}
+	if err := getErr(); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func (l *BulkLoader) writeFile(envOpts *EnvOptions, path string, entries []bulkLoadEntry) error {
+	w := NewSSTFileWriter(envOpts, l.cfOpts)
+	defer w.Destroy()
+	if err := w.Open(path); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := w.Put(e.key, e.value); err != nil {
+			return err
+		}
+	}
+	_, err := w.Finish()
+	return err
+}
+
+// mergeRuns merges the sorted runs, resolves duplicate keys and calls fn
+// for every distinct key in order.
+func (l *BulkLoader) mergeRuns(runs []*bulkLoadRunReader, fn func(e bulkLoadEntry) error) error {
+	h := &bulkLoadHeap{loader: l}
+	for _, r := range runs {
+		ok, err := r.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			h.readers = append(h.readers, r)
+		}
+	}
+	heap.Init(h)
+
+	var dups []bulkLoadEntry
+	for h.Len() > 0 {
+		r := h.readers[0]
+		e := r.cur
+		if len(dups) > 0 && l.compare(dups[0].key, e.key) != 0 {
+			if err := l.resolve(dups, fn); err != nil {
+				return err
+			}
+			dups = dups[:0]
+		}
+		dups = append(dups, e)
+
+		ok, err := r.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+	if len(dups) > 0 {
+		return l.resolve(dups, fn)
+	}
+	return nil
+}
+
+// resolve calls fn with the entry to write for entries sharing the same
+// key, ordered by the time they were added.
+func (l *BulkLoader) resolve(entries []bulkLoadEntry, fn func(e bulkLoadEntry) error) error {
+	last := entries[len(entries)-1]
+	if len(entries) == 1 || l.opts.DuplicatePolicy == BulkLoadLastWriteWins {
+		return fn(last)
+	}
+	operands := make([][]byte, len(entries))
+	for i, e := range entries {
+		operands[i] = e.value
+	}
+	value, ok := l.opts.MergeOperator.FullMerge(last.key, nil, operands)
+	if !ok {
+		return fmt.Errorf("merging the values of key %q failed", last.key)
+	}
+	return fn(bulkLoadEntry{key: last.key, value: value, seq: last.seq})
+}
+
+// Destroy removes the temporary files of the BulkLoader.
+func (l *BulkLoader) Destroy() {
+	l.spills.Wait()
+	os.RemoveAll(l.dir)
+}
+
+type bulkLoadRunReader struct {
+	f   *os.File
+	r   *bufio.Reader
+	cur bulkLoadEntry
+}
+
+func newBulkLoadRunReader(path string) (*bulkLoadRunReader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &bulkLoadRunReader{f: f, r: bufio.NewReader(f)}, nil
+}
+
+// next reads the next entry of the run into cur.
+func (r *bulkLoadRunReader) next() (bool, error) {
+	keyLen, err := binary.ReadUvarint(r.r)
+	if err == io.EOF {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	valueLen, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return false, err
+	}
+	seq, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return false, err
+	}
+	buf := make([]byte, keyLen+valueLen)
+	if _, err := io.ReadFull(r.r, buf); err != nil {
+		return false, err
+	}
+	r.cur = bulkLoadEntry{key: buf[:keyLen:keyLen], value: buf[keyLen:], seq: seq}
+	return true, nil
+}
+
+// bulkLoadHeap orders run readers by their current entry.
+type bulkLoadHeap struct {
+	loader  *BulkLoader
+	readers []*bulkLoadRunReader
+}
+
+func (h *bulkLoadHeap) Len() int { return len(h.readers) }
+func (h *bulkLoadHeap) Less(i, j int) bool {
+	return h.loader.less(&h.readers[i].cur, &h.readers[j].cur)
+}
+func (h *bulkLoadHeap) Swap(i, j int)      { h.readers[i], h.readers[j] = h.readers[j], h.readers[i] }
+func (h *bulkLoadHeap) Push(x interface{}) { h.readers = append(h.readers, x.(*bulkLoadRunReader)) }
+func (h *bulkLoadHeap) Pop() interface{} {
+	n := len(h.readers)
+	r := h.readers[n-1]
+	h.readers = h.readers[:n-1]
+	return r
+}
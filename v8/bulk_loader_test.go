@@ -0,0 +1,100 @@
+package gorocksdb
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestBulkLoader(t *testing.T) {
+	db := newTestDB(t, "TestBulkLoader", nil)
+	defer db.Close()
+
+	var last BulkLoadProgress
+	l, err := NewBulkLoader(db, nil, nil, BulkLoaderOptions{
+		MemoryBudget:   1 << 10,
+		TargetFileSize: 1 << 10,
+		Parallelism:    2,
+		Progress:       func(p BulkLoadProgress) { last = p },
+	})
+	ensure.Nil(t, err)
+	defer l.Destroy()
+
+	// every goroutine writes all keys in reverse order, the last
+	// value written for each key is from the final pass
+	const numKeys = 200
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := numKeys - 1; i >= 0; i-- {
+				if i%4 != g {
+					continue
+				}
+				ensure.Nil(t, l.Add([]byte(fmt.Sprintf("key%04d", i)), []byte("old")))
+			}
+		}(g)
+	}
+	wg.Wait()
+	for i := 0; i < numKeys; i++ {
+		ensure.Nil(t, l.Add([]byte(fmt.Sprintf("key%04d", i)), []byte(fmt.Sprintf("val%04d", i))))
+	}
+	ensure.Nil(t, l.Finish())
+	ensure.NotNil(t, l.Add([]byte("late"), []byte("late")))
+
+	ensure.DeepEqual(t, last.Phase, BulkLoadDone)
+	ensure.DeepEqual(t, last.KeysAdded, uint64(2*numKeys))
+	ensure.DeepEqual(t, last.KeysWritten, uint64(numKeys))
+	ensure.True(t, last.SortedRuns > 1)
+	ensure.True(t, last.FilesWritten > 1)
+
+	ro := NewDefaultReadOptions()
+	iter := db.NewIterator(ro)
+	defer iter.Close()
+	i := 0
+	for iter.SeekToFirst(); iter.Valid(); iter.Next() {
+		ensure.DeepEqual(t, iter.Key().Data(), []byte(fmt.Sprintf("key%04d", i)))
+		ensure.DeepEqual(t, iter.Value().Data(), []byte(fmt.Sprintf("val%04d", i)))
+		i++
+	}
+	ensure.Nil(t, iter.Err())
+	ensure.DeepEqual(t, i, numKeys)
+}
+
+func TestBulkLoaderMergeDuplicates(t *testing.T) {
+	db := newTestDB(t, "TestBulkLoaderMergeDuplicates", nil)
+	defer db.Close()
+
+	l, err := NewBulkLoader(db, nil, nil, BulkLoaderOptions{
+		MemoryBudget:    1 << 8,
+		DuplicatePolicy: BulkLoadMerge,
+		MergeOperator: &mockMergeOperator{
+			fullMerge: func(key, existingValue []byte, operands [][]byte) ([]byte, bool) {
+				var merged []byte
+				for _, op := range operands {
+					merged = append(merged, op...)
+				}
+				return merged, true
+			},
+		},
+	})
+	ensure.Nil(t, err)
+	defer l.Destroy()
+
+	for i := 0; i < 20; i++ {
+		ensure.Nil(t, l.Add([]byte("dup"), []byte{'a' + byte(i)}))
+		ensure.Nil(t, l.Add([]byte(fmt.Sprintf("single%02d", i)), []byte("x")))
+	}
+	ensure.Nil(t, l.Finish())
+
+	ro := NewDefaultReadOptions()
+	v, err := db.GetBytes(ro, []byte("dup"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v, []byte("abcdefghijklmnopqrst"))
+	v, err = db.GetBytes(ro, []byte("single07"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v, []byte("x"))
+}
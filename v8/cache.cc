@@ -0,0 +1,68 @@
+#include "gorocksdb_internal.h"
+
+using rocksdb::Cache;
+using rocksdb::CacheEntryRole;
+using rocksdb::CompressedSecondaryCacheOptions;
+using rocksdb::LRUCacheOptions;
+using rocksdb::SecondaryCache;
+using rocksdb::Slice;
+
+// gorocksdb_secondary_cache_t holds a secondary cache until it is set on the
+// LRU caches using it.
+struct gorocksdb_secondary_cache_t {
+  std::shared_ptr<SecondaryCache> rep;
+};
+
+extern "C" {
+
+rocksdb_cache_t* gorocksdb_cache_create_lru(
+    size_t capacity, int num_shard_bits, unsigned char strict_capacity_limit,
+    double high_pri_pool_ratio, double low_pri_pool_ratio,
+    gorocksdb_secondary_cache_t* secondary_cache) {
+  LRUCacheOptions opts;
+  opts.capacity = capacity;
+  opts.num_shard_bits = num_shard_bits;
+  opts.strict_capacity_limit = strict_capacity_limit;
+  opts.high_pri_pool_ratio = high_pri_pool_ratio;
+  opts.low_pri_pool_ratio = low_pri_pool_ratio;
+  if (secondary_cache != nullptr) {
+    opts.secondary_cache = secondary_cache->rep;
+  }
+  return new rocksdb_cache_t{rocksdb::NewLRUCache(opts)};
+}
+
+void gorocksdb_cache_erase_unref_entries(rocksdb_cache_t* cache) {
+  cache->rep->EraseUnRefEntries();
+}
+
+void gorocksdb_cache_get_usage_by_role(rocksdb_cache_t* cache, uint64_t* usage,
+                                       size_t num_roles) {
+  memset(usage, 0, sizeof(uint64_t) * num_roles);
+  cache->rep->ApplyToAllEntries(
+      [usage, num_roles](const Slice& /*key*/, Cache::ObjectPtr /*obj*/,
+                         size_t charge, const Cache::CacheItemHelper* helper) {
+        size_t role = static_cast<size_t>(
+            helper != nullptr ? helper->role : CacheEntryRole::kMisc);
+        if (role < num_roles) {
+          usage[role] += charge;
+        }
+      },
+      Cache::ApplyToAllEntriesOptions());
+}
+
+gorocksdb_secondary_cache_t* gorocksdb_secondary_cache_create_compressed(
+    size_t capacity, int num_shard_bits, int compression_type) {
+  CompressedSecondaryCacheOptions opts;
+  opts.capacity = capacity;
+  opts.num_shard_bits = num_shard_bits;
+  opts.compression_type =
+      static_cast<rocksdb::CompressionType>(compression_type);
+  return new gorocksdb_secondary_cache_t{
+      rocksdb::NewCompressedSecondaryCache(opts)};
+}
+
+void gorocksdb_secondary_cache_destroy(gorocksdb_secondary_cache_t* cache) {
+  delete cache;
+}
+
+}  // extern "C"
@@ -1,6 +1,7 @@
 package gorocksdb
 
 // #include "rocksdb/c.h"
+// #include "gorocksdb.h"
 import "C"
 
 // Cache is a cache used to store data read from data in memory.
@@ -8,11 +9,105 @@ type Cache struct {
 	c *C.rocksdb_cache_t
 }
 
+// CacheEntryRole is the kind of a block cache entry, in the same order as
+// rocksdb::CacheEntryRole.
+type CacheEntryRole int
+
+// Cache entry roles.
+const (
+	// DataBlockCacheEntry is a data block.
+	DataBlockCacheEntry CacheEntryRole = iota
+	// FilterBlockCacheEntry is a filter block, or a partition of one.
+	FilterBlockCacheEntry
+	// FilterMetaBlockCacheEntry is the top level index of a partitioned filter.
+	FilterMetaBlockCacheEntry
+	// DeprecatedFilterBlockCacheEntry is a block-based filter.
+	DeprecatedFilterBlockCacheEntry
+	// IndexBlockCacheEntry is an index block, or a partition of one.
+	IndexBlockCacheEntry
+	// OtherBlockCacheEntry is another kind of block.
+	OtherBlockCacheEntry
+	// WriteBufferCacheEntry is memory charged by a WriteBufferManager.
+	WriteBufferCacheEntry
+	// CompressionDictionaryBuildingBufferCacheEntry is memory used to build
+	// compression dictionaries.
+	CompressionDictionaryBuildingBufferCacheEntry
+	// FilterConstructionCacheEntry is memory used to build filters.
+	FilterConstructionCacheEntry
+	// BlockBasedTableReaderCacheEntry is memory used by table readers.
+	BlockBasedTableReaderCacheEntry
+	// FileMetadataCacheEntry is memory used by file metadata.
+	FileMetadataCacheEntry
+	// BlobValueCacheEntry is a blob value.
+	BlobValueCacheEntry
+	// BlobCacheCacheEntry is memory charged by a separate blob cache.
+	BlobCacheCacheEntry
+	// MiscCacheEntry is any other entry.
+	MiscCacheEntry
+
+	numCacheEntryRoles = int(MiscCacheEntry) + 1
+)
+
+// LRUCacheOptions are the options of an LRU cache.
+type LRUCacheOptions struct {
+	// Capacity is the size of the cache in bytes.
+	Capacity uint64
+	// NumShardBits is the number of bits of the hash used to choose the
+	// cache shard. A negative value chooses it from the capacity.
+	NumShardBits int
+	// StrictCapacityLimit makes inserts fail instead of exceeding the
+	// capacity when no entry can be evicted.
+	StrictCapacityLimit bool
+	// HighPriPoolRatio is the fraction of the capacity reserved for high
+	// priority entries, e.g. index and filter blocks when
+	// SetCacheIndexAndFilterBlocksWithHighPriority is set.
+	HighPriPoolRatio float64
+	// LowPriPoolRatio is the fraction of the capacity reserved for low
+	// priority entries.
+	LowPriPoolRatio float64
+	// SecondaryCache, if set, keeps the entries evicted from the cache.
+	SecondaryCache *SecondaryCache
+}
+
+// NewDefaultLRUCacheOptions returns the default LRU cache options with the
+// capacity given.
+func NewDefaultLRUCacheOptions(capacity uint64) LRUCacheOptions {
+	return LRUCacheOptions{
+		Capacity:         capacity,
+		NumShardBits:     -1,
+		HighPriPoolRatio: 0.5,
+	}
+}
+
 // NewLRUCache creates a new LRU Cache object with the capacity given.
 func NewLRUCache(capacity uint64) *Cache {
 	return NewNativeCache(C.rocksdb_cache_create_lru(C.size_t(capacity)))
 }
 
+// NewLRUCacheWithOptions creates a new LRU Cache object with the options given.
+func NewLRUCacheWithOptions(opts LRUCacheOptions) *Cache {
+	var secondary *C.gorocksdb_secondary_cache_t
+	if opts.SecondaryCache != nil {
+		secondary = opts.SecondaryCache.c
+	}
+	return NewNativeCache(C.gorocksdb_cache_create_lru(
+		C.size_t(opts.Capacity),
+		C.int(opts.NumShardBits),
+		boolToChar(opts.StrictCapacityLimit),
+		C.double(opts.HighPriPoolRatio),
+		C.double(opts.LowPriPoolRatio),
+		secondary,
+	))
+}
+
+// NewHyperClockCache creates a new HyperClockCache, a lock-free cache
+// scaling better than the LRU cache with many concurrent readers.
+// estimatedEntryCharge is the expected size of an entry, usually the
+// block size; 0 lets the cache estimate it automatically.
+func NewHyperClockCache(capacity, estimatedEntryCharge uint64) *Cache {
+	return NewNativeCache(C.rocksdb_cache_create_hyper_clock(C.size_t(capacity), C.size_t(estimatedEntryCharge)))
+}
+
 // NewNativeCache creates a Cache object.
 func NewNativeCache(c *C.rocksdb_cache_t) *Cache {
 	return &Cache{c}
@@ -28,8 +123,60 @@ func (c *Cache) GetPinnedUsage() uint64 {
 	return uint64(C.rocksdb_cache_get_pinned_usage(c.c))
 }
 
+// GetUsageByRole returns the Cache memory usage of each kind of entry.
+// Roles without any entry are omitted. It walks the whole cache, so it should
+// not be called too often on large caches.
+func (c *Cache) GetUsageByRole() map[CacheEntryRole]uint64 {
+	var usage [numCacheEntryRoles]C.uint64_t
+	C.gorocksdb_cache_get_usage_by_role(c.c, &usage[0], C.size_t(numCacheEntryRoles))
+	result := make(map[CacheEntryRole]uint64)
+	for role, n := range usage {
+		if n > 0 {
+			result[CacheEntryRole(role)] = uint64(n)
+		}
+	}
+	return result
+}
+
+// SetCapacity sets the maximum size of the Cache. If the new capacity is
+// smaller than the usage, unreferenced entries are evicted.
+func (c *Cache) SetCapacity(capacity uint64) {
+	C.rocksdb_cache_set_capacity(c.c, C.size_t(capacity))
+}
+
+// GetCapacity returns the maximum size of the Cache.
+func (c *Cache) GetCapacity() uint64 {
+	return uint64(C.rocksdb_cache_get_capacity(c.c))
+}
+
+// EraseUnRefEntries removes all the entries not referenced by a reader.
+func (c *Cache) EraseUnRefEntries() {
+	C.gorocksdb_cache_erase_unref_entries(c.c)
+}
+
 // Destroy deallocates the Cache object.
 func (c *Cache) Destroy() {
 	C.rocksdb_cache_destroy(c.c)
 	c.c = nil
 }
+
+// SecondaryCache is a cache tier keeping the entries evicted from a Cache,
+// see LRUCacheOptions.SecondaryCache.
+type SecondaryCache struct {
+	c *C.gorocksdb_secondary_cache_t
+}
+
+// NewCompressedSecondaryCache creates a SecondaryCache keeping the evicted
+// blocks compressed in memory. A negative numShardBits chooses it from the
+// capacity.
+func NewCompressedSecondaryCache(capacity uint64, numShardBits int, compression CompressionType) *SecondaryCache {
+	return &SecondaryCache{C.gorocksdb_secondary_cache_create_compressed(
+		C.size_t(capacity), C.int(numShardBits), C.int(compression))}
+}
+
+// Destroy deallocates the SecondaryCache object. The caches using it keep
+// their own reference.
+func (c *SecondaryCache) Destroy() {
+	C.gorocksdb_secondary_cache_destroy(c.c)
+	c.c = nil
+}
@@ -0,0 +1,58 @@
+package gorocksdb
+
+import (
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestCacheCapacity(t *testing.T) {
+	for _, cache := range []*Cache{
+		NewLRUCache(1 << 20),
+		NewLRUCacheWithOptions(NewDefaultLRUCacheOptions(1 << 20)),
+		NewHyperClockCache(1<<20, 4096),
+	} {
+		ensure.DeepEqual(t, cache.GetCapacity(), uint64(1<<20))
+		cache.SetCapacity(2 << 20)
+		ensure.DeepEqual(t, cache.GetCapacity(), uint64(2<<20))
+		cache.Destroy()
+	}
+}
+
+func TestCacheUsageByRole(t *testing.T) {
+	secondary := NewCompressedSecondaryCache(1<<20, -1, NoCompression)
+	defer secondary.Destroy()
+	cacheOpts := NewDefaultLRUCacheOptions(8 << 20)
+	cacheOpts.NumShardBits = 0
+	cacheOpts.StrictCapacityLimit = true
+	cacheOpts.SecondaryCache = secondary
+	cache := NewLRUCacheWithOptions(cacheOpts)
+	defer cache.Destroy()
+
+	bbto := NewDefaultBlockBasedTableOptions()
+	defer bbto.Destroy()
+	bbto.SetBlockCache(cache)
+	bbto.SetCacheIndexAndFilterBlocks(true)
+	db := newTestDB(t, "TestCacheUsageByRole", func(opts *Options) {
+		opts.SetBlockBasedTableFactory(bbto)
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	ro := NewDefaultReadOptions()
+	defer ro.Destroy()
+	ensure.Nil(t, db.Put(wo, []byte("key"), []byte("value")))
+	ensure.Nil(t, db.Flush(NewDefaultFlushOptions()))
+	v, err := db.Get(ro, []byte("key"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v.Data(), []byte("value"))
+	v.Free()
+
+	usage := cache.GetUsageByRole()
+	ensure.True(t, usage[DataBlockCacheEntry] > 0)
+	ensure.True(t, usage[IndexBlockCacheEntry] > 0)
+
+	cache.EraseUnRefEntries()
+	ensure.DeepEqual(t, cache.GetUsageByRole()[DataBlockCacheEntry], uint64(0))
+}
@@ -0,0 +1,157 @@
+#include "gorocksdb_internal.h"
+#include "rocksdb/compaction_filter.h"
+#include "rocksdb/wide_columns.h"
+
+extern "C" {
+#include "_cgo_export.h"
+}
+
+using rocksdb::CompactionFilter;
+using rocksdb::CompactionFilterFactory;
+using rocksdb::Options;
+using rocksdb::Slice;
+using rocksdb::WideColumns;
+
+// gorocksdb_compactionfilter_v2_t holds a compaction filter set through
+// gorocksdb_options_set_compaction_filter_v2.
+struct gorocksdb_compactionfilter_v2_t {
+  CompactionFilter* rep;
+};
+
+namespace {
+
+// The decisions returned by gorocksdb_compactionfilter_handle_filter, see
+// CompactionFilterDecisionKind.
+enum {
+  kGoDecisionKeep = 0,
+  kGoDecisionRemove = 1,
+  kGoDecisionChangeValue = 2,
+  kGoDecisionRemoveAndSkipUntil = 3,
+};
+
+// GoCompactionFilter calls into a Go compaction filter registered by handle.
+// The handle is released when the filter is destroyed.
+class GoCompactionFilter : public CompactionFilter {
+ public:
+  GoCompactionFilter(uintptr_t handle, std::string name)
+      : handle_(handle), name_(std::move(name)) {}
+
+  ~GoCompactionFilter() override {
+    gorocksdb_compactionfilter_handle_release(handle_);
+  }
+
+  Decision FilterV3(
+      int level, const Slice& key, ValueType value_type,
+      const Slice* existing_value, const WideColumns* existing_columns,
+      std::string* new_value,
+      std::vector<std::pair<std::string, std::string>>* new_columns,
+      std::string* skip_until) const override {
+    // Wide-column entities are filtered by the value of their default
+    // column, like RocksDB does for filters that do not handle them.
+    const Slice* value = existing_value;
+    if (value_type == kWideColumnEntity) {
+      value = nullptr;
+      if (!existing_columns->empty() &&
+          existing_columns->front().name() == rocksdb::kDefaultWideColumnName) {
+        value = &existing_columns->front().value();
+      }
+    }
+
+    char* c_result = nullptr;
+    size_t result_len = 0;
+    int decision = gorocksdb_compactionfilter_handle_filter(
+        handle_, level, const_cast<char*>(key.data()), key.size(),
+        static_cast<int>(value_type),
+        value != nullptr ? const_cast<char*>(value->data()) : nullptr,
+        value != nullptr ? value->size() : 0, &c_result, &result_len);
+    std::string result(c_result != nullptr ? c_result : "", result_len);
+    free(c_result);
+
+    switch (decision) {
+      case kGoDecisionRemove:
+        return Decision::kRemove;
+      case kGoDecisionChangeValue:
+        if (value_type == kWideColumnEntity && value != nullptr) {
+          for (const auto& column : *existing_columns) {
+            new_columns->emplace_back(column.name().ToString(),
+                                      column.value().ToString());
+          }
+          new_columns->front().second = std::move(result);
+          return Decision::kChangeWideColumnEntity;
+        }
+        *new_value = std::move(result);
+        return Decision::kChangeValue;
+      case kGoDecisionRemoveAndSkipUntil:
+        *skip_until = std::move(result);
+        return Decision::kRemoveAndSkipUntil;
+      default:
+        return Decision::kKeep;
+    }
+  }
+
+  const char* Name() const override { return name_.c_str(); }
+
+ private:
+  uintptr_t handle_;
+  std::string name_;
+};
+
+// GoCompactionFilterFactory calls into a Go CompactionFilterFactory.
+class GoCompactionFilterFactory : public CompactionFilterFactory {
+ public:
+  explicit GoCompactionFilterFactory(uintptr_t idx) : idx_(idx) {
+    char* name = gorocksdb_compactionfilterfactory_name(idx);
+    name_ = name;
+    free(name);
+  }
+
+  std::unique_ptr<CompactionFilter> CreateCompactionFilter(
+      const CompactionFilter::Context& context) override {
+    char* name = nullptr;
+    uintptr_t handle = gorocksdb_compactionfilterfactory_create_filter(
+        idx_, context.is_full_compaction, context.is_manual_compaction,
+        context.column_family_id, static_cast<int>(context.reason), &name);
+    if (handle == 0) {
+      return nullptr;
+    }
+    std::unique_ptr<CompactionFilter> filter(
+        new GoCompactionFilter(handle, name));
+    free(name);
+    return filter;
+  }
+
+  const char* Name() const override { return name_.c_str(); }
+
+ private:
+  uintptr_t idx_;
+  std::string name_;
+};
+
+}  // namespace
+
+extern "C" {
+
+void gorocksdb_options_set_compaction_filter_factory(rocksdb_options_t* opts,
+                                                     uintptr_t idx) {
+  gorocksdb::RepValue<Options>(opts).compaction_filter_factory =
+      std::make_shared<GoCompactionFilterFactory>(idx);
+}
+
+gorocksdb_compactionfilter_v2_t* gorocksdb_compactionfilter_v2_create(
+    uintptr_t handle, const char* name) {
+  return new gorocksdb_compactionfilter_v2_t{
+      new GoCompactionFilter(handle, name)};
+}
+
+void gorocksdb_compactionfilter_v2_destroy(
+    gorocksdb_compactionfilter_v2_t* filter) {
+  delete filter->rep;
+  delete filter;
+}
+
+void gorocksdb_options_set_compaction_filter_v2(
+    rocksdb_options_t* opts, gorocksdb_compactionfilter_v2_t* filter) {
+  gorocksdb::RepValue<Options>(opts).compaction_filter = filter->rep;
+}
+
+}  // extern "C"
@@ -71,3 +71,195 @@ func gorocksdb_compactionfilter_filter(idx int, cLevel C.int, cKey *C.char, cKey
 func gorocksdb_compactionfilter_name(idx int) *C.char {
 	return compactionFilters.Get(idx).(compactionFilterWrapper).name
 }
+
+// TableFileCreationReason is the reason a table file is created.
+type TableFileCreationReason int
+
+// Table file creation reasons.
+const (
+	TableFileCreationReasonFlush      = TableFileCreationReason(0)
+	TableFileCreationReasonCompaction = TableFileCreationReason(1)
+	TableFileCreationReasonRecovery   = TableFileCreationReason(2)
+	TableFileCreationReasonMisc       = TableFileCreationReason(3)
+)
+
+// CompactionFilterContext describes the compaction a CompactionFilter is
+// created for.
+type CompactionFilterContext struct {
+	// IsFullCompaction is true if the compaction includes all the files.
+	IsFullCompaction bool
+	// IsManualCompaction is true if the compaction was requested by the
+	// application, e.g. through CompactRange.
+	IsManualCompaction bool
+	// ColumnFamilyID is the ID of the column family being compacted.
+	ColumnFamilyID uint32
+	// Reason is the reason the output files are created.
+	Reason TableFileCreationReason
+}
+
+// A CompactionFilterFactory creates a new CompactionFilter for each
+// compaction run, which allows the filter to keep state for a single run.
+//
+// If the created filter has a Destroy() method, it is called once the
+// compaction run is done with the filter. If it also implements
+// CompactionFilterV2, FilterV2 is called instead of Filter.
+type CompactionFilterFactory interface {
+	// CreateCompactionFilter returns the filter for the compaction described
+	// by ctx, or nil if no filtering is needed for it.
+	CreateCompactionFilter(ctx CompactionFilterContext) CompactionFilter
+
+	// The name of the compaction filter factory, for logging
+	Name() string
+}
+
+// Hold references to compaction filter factories.
+var compactionFilterFactories = NewCOWList()
+
+// Hold references to the compaction filters created by the factories, for
+// the duration of their compaction run, and to the CompactionFilterV2s.
+var compactionFilterHandles = newRegistry()
+
+func registerCompactionFilterFactory(factory CompactionFilterFactory) int {
+	return compactionFilterFactories.Append(factory)
+}
+
+//export gorocksdb_compactionfilterfactory_create_filter
+func gorocksdb_compactionfilterfactory_create_filter(idx int, cIsFull, cIsManual C.uchar, cCFID C.uint32_t, cReason C.int, cName **C.char) C.uintptr_t {
+	ctx := CompactionFilterContext{
+		IsFullCompaction:   cIsFull != 0,
+		IsManualCompaction: cIsManual != 0,
+		ColumnFamilyID:     uint32(cCFID),
+		Reason:             TableFileCreationReason(cReason),
+	}
+	filter := compactionFilterFactories.Get(idx).(CompactionFilterFactory).CreateCompactionFilter(ctx)
+	if filter == nil {
+		return 0
+	}
+	*cName = C.CString(filter.Name())
+	if v2, ok := filter.(CompactionFilterV2); ok {
+		return C.uintptr_t(compactionFilterHandles.register(v2))
+	}
+	return C.uintptr_t(compactionFilterHandles.register(filter))
+}
+
+//export gorocksdb_compactionfilterfactory_name
+func gorocksdb_compactionfilterfactory_name(idx int) *C.char {
+	return C.CString(compactionFilterFactories.Get(idx).(CompactionFilterFactory).Name())
+}
+
+//export gorocksdb_compactionfilter_handle_filter
+func gorocksdb_compactionfilter_handle_filter(handle C.uintptr_t, cLevel C.int, cKey *C.char, cKeyLen C.size_t, cValueType C.int, cVal *C.char, cValLen C.size_t, cResult **C.char, cResultLen *C.size_t) C.int {
+	key := charToByte(cKey, cKeyLen)
+	val := charToByte(cVal, cValLen)
+	valueType := CompactionFilterValueType(cValueType)
+
+	var decision CompactionFilterDecision
+	switch filter := compactionFilterHandles.get(uintptr(handle)).(type) {
+	case CompactionFilterV2:
+		decision = filter.FilterV2(int(cLevel), key, valueType, val)
+	case CompactionFilter:
+		// Like RocksDB, only plain values and the default column of
+		// wide-column entities are passed to a CompactionFilter.
+		if valueType == CompactionFilterValue || (valueType == CompactionFilterWideColumnEntity && val != nil) {
+			remove, newVal := filter.Filter(int(cLevel), key, val)
+			if remove {
+				decision = RemoveKey()
+			} else if newVal != nil {
+				decision = ChangeValue(newVal)
+			}
+		}
+	}
+
+	switch decision.Kind {
+	case CompactionFilterChangeValue:
+		*cResult = cByteSlice(decision.NewValue)
+		*cResultLen = C.size_t(len(decision.NewValue))
+	case CompactionFilterRemoveAndSkipUntil:
+		*cResult = cByteSlice(decision.SkipUntil)
+		*cResultLen = C.size_t(len(decision.SkipUntil))
+	}
+	return C.int(decision.Kind)
+}
+
+//export gorocksdb_compactionfilter_handle_release
+func gorocksdb_compactionfilter_handle_release(handle C.uintptr_t) {
+	filter := compactionFilterHandles.release(uintptr(handle))
+	if d, ok := filter.(interface{ Destroy() }); ok {
+		d.Destroy()
+	}
+}
+
+// CompactionFilterValueType is the type of the value of an entry seen by a
+// CompactionFilterV2.
+type CompactionFilterValueType int
+
+// Compaction filter value types.
+const (
+	// CompactionFilterValue is a plain value.
+	CompactionFilterValue = CompactionFilterValueType(0)
+	// CompactionFilterMergeOperand is a merge operand.
+	CompactionFilterMergeOperand = CompactionFilterValueType(1)
+	// CompactionFilterBlobIndex is a reference to a value stored in a blob file.
+	CompactionFilterBlobIndex = CompactionFilterValueType(2)
+	// CompactionFilterWideColumnEntity is a wide-column entity; the value is
+	// the one of its default column, or nil if it has none.
+	CompactionFilterWideColumnEntity = CompactionFilterValueType(3)
+)
+
+// CompactionFilterDecisionKind is what a CompactionFilterV2 decided to do
+// with an entry.
+type CompactionFilterDecisionKind int
+
+// Compaction filter decision kinds.
+const (
+	CompactionFilterKeep               = CompactionFilterDecisionKind(0)
+	CompactionFilterRemove             = CompactionFilterDecisionKind(1)
+	CompactionFilterChangeValue        = CompactionFilterDecisionKind(2)
+	CompactionFilterRemoveAndSkipUntil = CompactionFilterDecisionKind(3)
+)
+
+// CompactionFilterDecision is returned by CompactionFilterV2.FilterV2.
+// The zero value keeps the entry.
+type CompactionFilterDecision struct {
+	Kind CompactionFilterDecisionKind
+	// NewValue is the value replacing the existing one for CompactionFilterChangeValue.
+	NewValue []byte
+	// SkipUntil is the key to skip to for CompactionFilterRemoveAndSkipUntil.
+	SkipUntil []byte
+}
+
+// KeepKey returns a decision that keeps the entry.
+func KeepKey() CompactionFilterDecision {
+	return CompactionFilterDecision{Kind: CompactionFilterKeep}
+}
+
+// RemoveKey returns a decision that removes the entry.
+func RemoveKey() CompactionFilterDecision {
+	return CompactionFilterDecision{Kind: CompactionFilterRemove}
+}
+
+// ChangeValue returns a decision that replaces the value of the entry.
+// It is only valid for plain values and wide-column entities; for the
+// latter it replaces the value of the default column.
+func ChangeValue(newVal []byte) CompactionFilterDecision {
+	return CompactionFilterDecision{Kind: CompactionFilterChangeValue, NewValue: newVal}
+}
+
+// RemoveAndSkipUntil returns a decision that removes the entry and all the
+// entries after it up to, but excluding, key, without calling the filter
+// for them. The key must be after the current one. Older versions of the
+// skipped keys that are not part of the compaction may become visible again.
+func RemoveAndSkipUntil(key []byte) CompactionFilterDecision {
+	return CompactionFilterDecision{Kind: CompactionFilterRemoveAndSkipUntil, SkipUntil: key}
+}
+
+// A CompactionFilterV2 is a compaction filter that sees the type of every
+// entry, including merge operands, and can drop whole key ranges at once.
+type CompactionFilterV2 interface {
+	// FilterV2 is called for every entry of the compaction. The application
+	// must not keep references to key and val after the call.
+	FilterV2(level int, key []byte, valueType CompactionFilterValueType, val []byte) CompactionFilterDecision
+
+	// The name of the compaction filter, for logging
+	Name() string
+}
@@ -51,6 +51,107 @@ func TestCompactionFilter(t *testing.T) {
 	ensure.True(t, v2.Data() == nil)
 }
 
+func TestCompactionFilterFactory(t *testing.T) {
+	var (
+		deleteKey = []byte("delete")
+		keepKey   = []byte("keep")
+		contexts  []CompactionFilterContext
+		destroyed int
+	)
+	db := newTestDB(t, "TestCompactionFilterFactory", func(opts *Options) {
+		opts.SetCompactionFilterFactory(&mockCompactionFilterFactory{
+			create: func(ctx CompactionFilterContext) CompactionFilter {
+				contexts = append(contexts, ctx)
+				return &mockDestroyCompactionFilter{
+					mockCompactionFilter: mockCompactionFilter{
+						filter: func(level int, key, val []byte) (bool, []byte) {
+							return bytes.Equal(key, deleteKey), nil
+						},
+					},
+					destroy: func() { destroyed++ },
+				}
+			},
+		})
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	ensure.Nil(t, db.Put(wo, deleteKey, []byte("a")))
+	ensure.Nil(t, db.Put(wo, keepKey, []byte("b")))
+	db.CompactRange(Range{nil, nil})
+
+	ensure.True(t, len(contexts) > 0)
+	ensure.DeepEqual(t, destroyed, len(contexts))
+	last := contexts[len(contexts)-1]
+	ensure.True(t, last.IsManualCompaction)
+	ensure.DeepEqual(t, last.Reason, TableFileCreationReasonCompaction)
+
+	ro := NewDefaultReadOptions()
+	v1, err := db.Get(ro, deleteKey)
+	ensure.Nil(t, err)
+	ensure.True(t, v1.Data() == nil)
+	v2, err := db.Get(ro, keepKey)
+	defer v2.Free()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v2.Data(), []byte("b"))
+}
+
+func TestCompactionFilterV2(t *testing.T) {
+	var (
+		expiredPrefix = []byte("tenant1/")
+		nextPrefix    = []byte("tenant2/")
+		mergeKey      = []byte("merge")
+		seen          = make(map[string]CompactionFilterValueType)
+	)
+	db := newTestDB(t, "TestCompactionFilterV2", func(opts *Options) {
+		opts.SetMergeOperator(&mockMergeOperator{
+			fullMerge: func(key, existingValue []byte, operands [][]byte) ([]byte, bool) {
+				return operands[len(operands)-1], true
+			},
+		})
+		opts.SetCompactionFilterV2(&mockCompactionFilterV2{
+			filter: func(level int, key []byte, valueType CompactionFilterValueType, val []byte) CompactionFilterDecision {
+				seen[string(key)] = valueType
+				switch {
+				case bytes.HasPrefix(key, expiredPrefix):
+					return RemoveAndSkipUntil(nextPrefix)
+				case valueType == CompactionFilterMergeOperand:
+					return ChangeValue([]byte("operand"))
+				case bytes.Equal(val, []byte("old")):
+					return ChangeValue([]byte("new"))
+				}
+				return KeepKey()
+			},
+		})
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	for _, k := range []string{"tenant1/a", "tenant1/b", "tenant1/c"} {
+		ensure.Nil(t, db.Put(wo, []byte(k), []byte("v")))
+	}
+	ensure.Nil(t, db.Put(wo, []byte("tenant2/a"), []byte("old")))
+	ensure.Nil(t, db.Merge(wo, mergeKey, []byte("x")))
+	db.CompactRange(Range{nil, nil})
+
+	// the filter is not called for the skipped keys
+	ensure.DeepEqual(t, seen["tenant1/a"], CompactionFilterValue)
+	_, ok := seen["tenant1/b"]
+	ensure.False(t, ok)
+	ensure.DeepEqual(t, seen[string(mergeKey)], CompactionFilterMergeOperand)
+
+	ro := NewDefaultReadOptions()
+	for _, k := range []string{"tenant1/a", "tenant1/b", "tenant1/c"} {
+		v, err := db.Get(ro, []byte(k))
+		ensure.Nil(t, err)
+		ensure.True(t, v.Data() == nil)
+	}
+	v, err := db.Get(ro, []byte("tenant2/a"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v.Data(), []byte("new"))
+	v.Free()
+}
+
 type mockCompactionFilter struct {
 	filter func(level int, key, val []byte) (remove bool, newVal []byte)
 }
@@ -59,3 +160,28 @@ func (m *mockCompactionFilter) Name() string { return "gorocksdb.test" }
 func (m *mockCompactionFilter) Filter(level int, key, val []byte) (bool, []byte) {
 	return m.filter(level, key, val)
 }
+
+type mockDestroyCompactionFilter struct {
+	mockCompactionFilter
+	destroy func()
+}
+
+func (m *mockDestroyCompactionFilter) Destroy() { m.destroy() }
+
+type mockCompactionFilterFactory struct {
+	create func(ctx CompactionFilterContext) CompactionFilter
+}
+
+func (m *mockCompactionFilterFactory) Name() string { return "gorocksdb.test" }
+func (m *mockCompactionFilterFactory) CreateCompactionFilter(ctx CompactionFilterContext) CompactionFilter {
+	return m.create(ctx)
+}
+
+type mockCompactionFilterV2 struct {
+	filter func(level int, key []byte, valueType CompactionFilterValueType, val []byte) CompactionFilterDecision
+}
+
+func (m *mockCompactionFilterV2) Name() string { return "gorocksdb.test" }
+func (m *mockCompactionFilterV2) FilterV2(level int, key []byte, valueType CompactionFilterValueType, val []byte) CompactionFilterDecision {
+	return m.filter(level, key, valueType, val)
+}
@@ -0,0 +1,102 @@
+#include "gorocksdb_internal.h"
+
+extern "C" {
+#include "_cgo_export.h"
+}
+
+using rocksdb::CompactionService;
+using rocksdb::CompactionServiceJobInfo;
+using rocksdb::CompactionServiceJobStatus;
+using rocksdb::CompactionServiceOptionsOverride;
+using rocksdb::DB;
+using rocksdb::Options;
+
+namespace {
+
+// GoCompactionService calls into a Go CompactionService. The job statuses
+// are passed as the values of CompactionServiceJobStatus.
+class GoCompactionService : public CompactionService {
+ public:
+  explicit GoCompactionService(uintptr_t idx) : idx_(idx) {
+    char* name = gorocksdb_compactionservice_name(idx);
+    name_ = name;
+    free(name);
+  }
+
+  const char* Name() const override { return name_.c_str(); }
+
+  CompactionServiceJobStatus StartV2(const CompactionServiceJobInfo& info,
+                                     const std::string& input) override {
+    return static_cast<CompactionServiceJobStatus>(
+        gorocksdb_compactionservice_start(
+            idx_, const_cast<char*>(info.db_name.data()), info.db_name.size(),
+            const_cast<char*>(info.db_id.data()), info.db_id.size(),
+            const_cast<char*>(info.db_session_id.data()),
+            info.db_session_id.size(), info.job_id, info.priority,
+            const_cast<char*>(input.data()), input.size()));
+  }
+
+  CompactionServiceJobStatus WaitForCompleteV2(
+      const CompactionServiceJobInfo& info, std::string* result) override {
+    char* output = nullptr;
+    size_t output_len = 0;
+    int status = gorocksdb_compactionservice_wait_for_complete(
+        idx_, const_cast<char*>(info.db_name.data()), info.db_name.size(),
+        const_cast<char*>(info.db_id.data()), info.db_id.size(),
+        const_cast<char*>(info.db_session_id.data()),
+        info.db_session_id.size(), info.job_id, info.priority, &output,
+        &output_len);
+    if (output != nullptr) {
+      result->assign(output, output_len);
+      free(output);
+    }
+    return static_cast<CompactionServiceJobStatus>(status);
+  }
+
+ private:
+  uintptr_t idx_;
+  std::string name_;
+};
+
+}  // namespace
+
+extern "C" {
+
+void gorocksdb_options_set_compaction_service(rocksdb_options_t* opts,
+                                              uintptr_t idx) {
+  gorocksdb::RepValue<Options>(opts).compaction_service =
+      std::make_shared<GoCompactionService>(idx);
+}
+
+char* gorocksdb_open_and_compact(rocksdb_options_t* opts, const char* name,
+                                 const char* output_directory,
+                                 const char* input, size_t input_len,
+                                 size_t* output_len, char** errptr) {
+  const Options& options = gorocksdb::RepValue<Options>(opts);
+  CompactionServiceOptionsOverride override_options;
+  override_options.env = options.env;
+  override_options.comparator = options.comparator;
+  override_options.merge_operator = options.merge_operator;
+  override_options.compaction_filter = options.compaction_filter;
+  override_options.compaction_filter_factory =
+      options.compaction_filter_factory;
+  override_options.prefix_extractor = options.prefix_extractor;
+  override_options.table_factory = options.table_factory;
+  override_options.sst_partitioner_factory = options.sst_partitioner_factory;
+  override_options.listeners = options.listeners;
+  override_options.statistics = options.statistics;
+  override_options.table_properties_collector_factories =
+      options.table_properties_collector_factories;
+
+  std::string output;
+  rocksdb::Status s =
+      DB::OpenAndCompact(name, output_directory, std::string(input, input_len),
+                         &output, override_options);
+  if (gorocksdb::SaveError(errptr, s)) {
+    return nullptr;
+  }
+  *output_len = output.size();
+  return gorocksdb::CopyString(output);
+}
+
+}  // extern "C"
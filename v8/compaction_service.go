@@ -0,0 +1,141 @@
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+// #include "gorocksdb.h"
+import "C"
+import (
+	"errors"
+	"unsafe"
+)
+
+// ThreadPriority is the priority of a thread pool of an Env.
+type ThreadPriority int
+
+const (
+	ThreadPriorityBottom ThreadPriority = 0
+	ThreadPriorityLow    ThreadPriority = 1
+	ThreadPriorityHigh   ThreadPriority = 2
+	ThreadPriorityUser   ThreadPriority = 3
+)
+
+// CompactionServiceJobStatus is the status of a compaction job run by a
+// CompactionService.
+type CompactionServiceJobStatus int
+
+const (
+	// CompactionServiceJobSuccess means the job was scheduled or completed.
+	CompactionServiceJobSuccess CompactionServiceJobStatus = 0
+	// CompactionServiceJobFailure fails the compaction.
+	CompactionServiceJobFailure CompactionServiceJobStatus = 1
+	// CompactionServiceJobUseLocal makes the DB run the compaction itself.
+	CompactionServiceJobUseLocal CompactionServiceJobStatus = 2
+)
+
+// CompactionServiceJobInfo identifies a compaction job offloaded by a DB.
+type CompactionServiceJobInfo struct {
+	// DBName is the path of the DB the compaction belongs to.
+	DBName      string
+	DBID        string
+	DBSessionID string
+	// JobID is unique within a DB session.
+	JobID    uint64
+	Priority ThreadPriority
+}
+
+// A CompactionService runs the compactions of a DB outside of it, e.g. in
+// another process or on another machine, with RunCompactionWorker.
+//
+// For each compaction the DB calls Start, then WaitForComplete from the
+// same background thread. The output files of the compaction must be
+// written to a directory the DB can rename them from.
+type CompactionService interface {
+	// Start schedules a compaction job, described by the serialized input
+	// to pass to RunCompactionWorker.
+	Start(info CompactionServiceJobInfo, input []byte) CompactionServiceJobStatus
+
+	// WaitForComplete waits for a job started by Start and returns the
+	// serialized output returned by RunCompactionWorker.
+	WaitForComplete(info CompactionServiceJobInfo) ([]byte, CompactionServiceJobStatus)
+
+	// The name of the compaction service.
+	Name() string
+}
+
+// Hold references to compaction services.
+var compactionServices = NewCOWList()
+
+func registerCompactionService(service CompactionService) int {
+	return compactionServices.Append(service)
+}
+
+// SetCompactionService sets the service the compactions are offloaded to.
+// Default: nil, compactions run in the DB
+func (opts *Options) SetCompactionService(value CompactionService) {
+	idx := registerCompactionService(value)
+	C.gorocksdb_options_set_compaction_service(opts.c, C.uintptr_t(idx))
+}
+
+// RunCompactionWorker runs the compaction job described by input, as
+// received by CompactionService.Start, and returns its serialized output.
+// It can run in another process than the DB, which is opened as a
+// secondary instance at dbName, and writes the output files of the
+// compaction into outputDir.
+//
+// The opts must set the same comparator, merge operator, compaction
+// filter, prefix extractor, table factory, sst partitioner and table
+// properties collectors as the options of the DB; their other settings are
+// taken from the DB.
+func RunCompactionWorker(opts *Options, dbName, outputDir string, input []byte) ([]byte, error) {
+	var (
+		cErr       *C.char
+		cOutputLen C.size_t
+		cName      = C.CString(dbName)
+		cOutputDir = C.CString(outputDir)
+	)
+	defer C.free(unsafe.Pointer(cName))
+	defer C.free(unsafe.Pointer(cOutputDir))
+	cOutput := C.gorocksdb_open_and_compact(opts.c, cName, cOutputDir, byteToChar(input), C.size_t(len(input)), &cOutputLen, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return nil, errors.New(C.GoString(cErr))
+	}
+	defer C.rocksdb_free(unsafe.Pointer(cOutput))
+	return C.GoBytes(unsafe.Pointer(cOutput), C.int(cOutputLen)), nil
+}
+
+func compactionServiceJobInfo(cDBName *C.char, cDBNameLen C.size_t, cDBID *C.char, cDBIDLen C.size_t,
+	cSessionID *C.char, cSessionIDLen C.size_t, cJobID C.uint64_t, cPriority C.int) CompactionServiceJobInfo {
+	return CompactionServiceJobInfo{
+		DBName:      C.GoStringN(cDBName, C.int(cDBNameLen)),
+		DBID:        C.GoStringN(cDBID, C.int(cDBIDLen)),
+		DBSessionID: C.GoStringN(cSessionID, C.int(cSessionIDLen)),
+		JobID:       uint64(cJobID),
+		Priority:    ThreadPriority(cPriority),
+	}
+}
+
+//export gorocksdb_compactionservice_start
+func gorocksdb_compactionservice_start(idx int, cDBName *C.char, cDBNameLen C.size_t, cDBID *C.char, cDBIDLen C.size_t,
+	cSessionID *C.char, cSessionIDLen C.size_t, cJobID C.uint64_t, cPriority C.int, cInput *C.char, cInputLen C.size_t) C.int {
+	info := compactionServiceJobInfo(cDBName, cDBNameLen, cDBID, cDBIDLen, cSessionID, cSessionIDLen, cJobID, cPriority)
+	input := C.GoBytes(unsafe.Pointer(cInput), C.int(cInputLen))
+	return C.int(compactionServices.Get(idx).(CompactionService).Start(info, input))
+}
+
+//export gorocksdb_compactionservice_wait_for_complete
+func gorocksdb_compactionservice_wait_for_complete(idx int, cDBName *C.char, cDBNameLen C.size_t, cDBID *C.char, cDBIDLen C.size_t,
+	cSessionID *C.char, cSessionIDLen C.size_t, cJobID C.uint64_t, cPriority C.int, cOutput **C.char, cOutputLen *C.size_t) C.int {
+	info := compactionServiceJobInfo(cDBName, cDBNameLen, cDBID, cDBIDLen, cSessionID, cSessionIDLen, cJobID, cPriority)
+	output, status := compactionServices.Get(idx).(CompactionService).WaitForComplete(info)
+	if len(output) > 0 {
+		*cOutput = (*C.char)(C.CBytes(output))
+		*cOutputLen = C.size_t(len(output))
+	}
+	return C.int(status)
+}
+
+//export gorocksdb_compactionservice_name
+func gorocksdb_compactionservice_name(idx int) *C.char {
+	return C.CString(compactionServices.Get(idx).(CompactionService).Name())
+}
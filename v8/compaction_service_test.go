@@ -0,0 +1,119 @@
+package gorocksdb
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+// workerCompactionService runs the compactions with RunCompactionWorker in
+// the test process.
+type workerCompactionService struct {
+	opts      *Options
+	outputDir string
+	useLocal  bool
+
+	mu        sync.Mutex
+	inputs    map[uint64][]byte
+	completed int
+	err       error
+}
+
+func (s *workerCompactionService) Name() string { return "worker" }
+
+func (s *workerCompactionService) Start(info CompactionServiceJobInfo, input []byte) CompactionServiceJobStatus {
+	if s.useLocal {
+		return CompactionServiceJobUseLocal
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inputs[info.JobID] = input
+	return CompactionServiceJobSuccess
+}
+
+func (s *workerCompactionService) WaitForComplete(info CompactionServiceJobInfo) ([]byte, CompactionServiceJobStatus) {
+	s.mu.Lock()
+	input := s.inputs[info.JobID]
+	delete(s.inputs, info.JobID)
+	s.mu.Unlock()
+
+	outputDir := filepath.Join(s.outputDir, strconv.FormatUint(info.JobID, 10))
+	output, err := func() ([]byte, error) {
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			return nil, err
+		}
+		return RunCompactionWorker(s.opts, info.DBName, outputDir, input)
+	}()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.err = err
+		return nil, CompactionServiceJobFailure
+	}
+	s.completed++
+	return output, CompactionServiceJobSuccess
+}
+
+func testCompactionService(t *testing.T, name string, useLocal bool) *workerCompactionService {
+	outputDir, err := ioutil.TempDir("", "gorocksdb-"+name+"-output")
+	ensure.Nil(t, err)
+	defer os.RemoveAll(outputDir)
+
+	workerOpts := NewDefaultOptions()
+	defer workerOpts.Destroy()
+	service := &workerCompactionService{
+		opts:      workerOpts,
+		outputDir: outputDir,
+		useLocal:  useLocal,
+		inputs:    make(map[uint64][]byte),
+	}
+	db := newTestDB(t, name, func(opts *Options) {
+		opts.SetDisableAutoCompactions(true)
+		opts.SetCompactionService(service)
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	ro := NewDefaultReadOptions()
+	defer ro.Destroy()
+	fo := NewDefaultFlushOptions()
+	defer fo.Destroy()
+
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 10; j++ {
+			key := []byte("key" + strconv.Itoa(j))
+			ensure.Nil(t, db.Put(wo, key, []byte("value"+strconv.Itoa(i))))
+		}
+		ensure.Nil(t, db.Flush(fo))
+	}
+	ensure.DeepEqual(t, len(db.GetLiveFilesMetaData()), 3)
+
+	db.CompactRange(Range{})
+	ensure.Nil(t, service.err)
+	files := db.GetLiveFilesMetaData()
+	ensure.DeepEqual(t, len(files), 1)
+	ensure.DeepEqual(t, files[0].Entries, int64(10))
+	for j := 0; j < 10; j++ {
+		v, err := db.GetBytes(ro, []byte("key"+strconv.Itoa(j)))
+		ensure.Nil(t, err)
+		ensure.DeepEqual(t, v, []byte("value2"))
+	}
+	return service
+}
+
+func TestCompactionService(t *testing.T) {
+	service := testCompactionService(t, "TestCompactionService", false)
+	ensure.True(t, service.completed > 0)
+}
+
+func TestCompactionServiceUseLocal(t *testing.T) {
+	service := testCompactionService(t, "TestCompactionServiceUseLocal", true)
+	ensure.DeepEqual(t, service.completed, 0)
+}
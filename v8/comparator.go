@@ -1,8 +1,14 @@
 package gorocksdb
 
 // #include "rocksdb/c.h"
+// #include "gorocksdb.h"
 import "C"
 
+import (
+	"bytes"
+	"encoding/binary"
+)
+
 // A Comparator object provides a total order across slices that are
 // used as keys in an sstable or a database.
 type Comparator interface {
@@ -18,15 +24,97 @@ type Comparator interface {
 
 // NewNativeComparator creates a Comparator object.
 func NewNativeComparator(c *C.rocksdb_comparator_t) Comparator {
-	return nativeComparator{c}
+	return nativeComparator{c: c}
 }
 
 type nativeComparator struct {
 	c *C.rocksdb_comparator_t
+
+	// compare and name are only known for the built-in comparators.
+	compare func(a, b []byte) int
+	name    string
+}
+
+func (c nativeComparator) Compare(a, b []byte) int {
+	if c.compare == nil {
+		return 0
+	}
+	return c.compare(a, b)
+}
+func (c nativeComparator) Name() string { return c.name }
+
+// The built-in comparators below are implemented natively, so comparing keys
+// does not call into Go. Their Compare method gives the same results from Go.
+//
+// Ordering keys by a fixed prefix, then by the rest of the key, is the
+// byte-wise order of the default comparator: use it with SetPrefixExtractor
+// and NewFixedPrefixTransform to make prefix seeks efficient.
+
+// NewReverseBytewiseComparator creates a comparator ordering keys in reverse
+// lexicographic byte-wise order, like RocksDB's ReverseBytewiseComparator.
+func NewReverseBytewiseComparator() Comparator {
+	return nativeComparator{
+		c:       C.gorocksdb_comparator_create_reverse_bytewise(),
+		compare: func(a, b []byte) int { return -bytes.Compare(a, b) },
+		name:    "rocksdb.ReverseBytewiseComparator",
+	}
 }
 
-func (c nativeComparator) Compare(a, b []byte) int { return 0 }
-func (c nativeComparator) Name() string            { return "" }
+// NewBigEndianUint64Comparator creates a comparator for keys that are uint64
+// values encoded in 8 bytes in big-endian order, ordered numerically.
+// Keys of another size are ordered byte-wise.
+func NewBigEndianUint64Comparator() Comparator {
+	return nativeComparator{
+		c:       C.gorocksdb_comparator_create_uint64(),
+		compare: compareBigEndianUint64,
+		name:    "gorocksdb.BigEndianUint64Comparator",
+	}
+}
+
+func compareBigEndianUint64(a, b []byte) int {
+	if len(a) != 8 || len(b) != 8 {
+		return bytes.Compare(a, b)
+	}
+	x, y := binary.BigEndian.Uint64(a), binary.BigEndian.Uint64(b)
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+// NewBytewiseU64TsComparator creates a comparator for keys followed by an
+// 8 byte little-endian uint64 timestamp, like RocksDB's
+// BytewiseComparatorWithU64Ts. Keys are ordered byte-wise, then by
+// decreasing timestamp. Keys shorter than a timestamp are compared
+// byte-wise as a whole.
+func NewBytewiseU64TsComparator() Comparator {
+	return nativeComparator{
+		c:       C.gorocksdb_comparator_create_bytewise_u64ts(),
+		compare: compareBytewiseU64Ts,
+		name:    "leveldb.BytewiseComparator.u64ts",
+	}
+}
+
+func compareBytewiseU64Ts(a, b []byte) int {
+	if len(a) < 8 || len(b) < 8 {
+		return bytes.Compare(a, b)
+	}
+	ak, bk := a[:len(a)-8], b[:len(b)-8]
+	if r := bytes.Compare(ak, bk); r != 0 {
+		return r
+	}
+	x, y := binary.LittleEndian.Uint64(a[len(ak):]), binary.LittleEndian.Uint64(b[len(bk):])
+	switch {
+	case x > y:
+		return -1
+	case x < y:
+		return 1
+	}
+	return 0
+}
 
 // Hold references to comperators.
 var comperators = NewCOWList()
@@ -2,6 +2,7 @@ package gorocksdb
 
 import (
 	"bytes"
+	"encoding/binary"
 	"testing"
 
 	"github.com/facebookgo/ensure"
@@ -39,6 +40,96 @@ func TestComparator(t *testing.T) {
 	ensure.DeepEqual(t, actualKeys, givenKeys)
 }
 
+func TestNativeComparators(t *testing.T) {
+	u64 := func(v uint64) []byte {
+		b := make([]byte, 8)
+		binary.BigEndian.PutUint64(b, v)
+		return b
+	}
+	ts := func(key string, v uint64) []byte {
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint64(b, v)
+		return append([]byte(key), b...)
+	}
+	for _, c := range []struct {
+		name string
+		cmp  Comparator
+		keys [][]byte // in expected order
+	}{
+		{"ReverseBytewise", NewReverseBytewiseComparator(), [][]byte{[]byte("c"), []byte("b"), []byte("ab"), []byte("a")}},
+		{"BigEndianUint64", NewBigEndianUint64Comparator(), [][]byte{u64(1), u64(256), u64(1 << 40)}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			// the Go side agrees with the native order
+			for i := 1; i < len(c.keys); i++ {
+				ensure.True(t, c.cmp.Compare(c.keys[i-1], c.keys[i]) < 0)
+				ensure.True(t, c.cmp.Compare(c.keys[i], c.keys[i-1]) > 0)
+			}
+
+			db := newTestDB(t, "TestNativeComparators"+c.name, func(opts *Options) {
+				opts.SetComparator(c.cmp)
+			})
+			defer db.Close()
+
+			wo := NewDefaultWriteOptions()
+			for i := len(c.keys) - 1; i >= 0; i-- {
+				ensure.Nil(t, db.Put(wo, c.keys[i], []byte("val")))
+			}
+
+			iter := db.NewIterator(NewDefaultReadOptions())
+			defer iter.Close()
+			var actualKeys [][]byte
+			for iter.SeekToFirst(); iter.Valid(); iter.Next() {
+				actualKeys = append(actualKeys, append([]byte(nil), iter.Key().Data()...))
+			}
+			ensure.Nil(t, iter.Err())
+			ensure.DeepEqual(t, actualKeys, c.keys)
+		})
+	}
+
+	cmp := NewBytewiseU64TsComparator()
+	ensure.True(t, cmp.Compare(ts("a", 2), ts("a", 1)) < 0)
+	ensure.True(t, cmp.Compare(ts("a", 1), ts("b", 3)) < 0)
+	ensure.DeepEqual(t, cmp.Compare(ts("a", 1), ts("a", 1)), 0)
+	ensure.True(t, cmp.Compare([]byte("a"), []byte("b")) < 0)
+	ensure.DeepEqual(t, cmp.Name(), "leveldb.BytewiseComparator.u64ts")
+}
+
+func TestBytewiseU64TsComparatorRead(t *testing.T) {
+	db := newTestDB(t, "TestBytewiseU64TsComparatorRead", func(opts *Options) {
+		opts.SetComparator(NewBytewiseU64TsComparator())
+	})
+	defer db.Close()
+
+	u64 := func(v uint64) []byte {
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint64(b, v)
+		return b
+	}
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	ensure.Nil(t, db.PutWithTS(wo, []byte("key"), u64(10), []byte("old")))
+	ensure.Nil(t, db.PutWithTS(wo, []byte("key"), u64(20), []byte("new")))
+
+	for _, c := range []struct {
+		ts    uint64
+		value []byte
+	}{
+		{5, nil},
+		{10, []byte("old")},
+		{15, []byte("old")},
+		{20, []byte("new")},
+		{30, []byte("new")},
+	} {
+		ro := NewDefaultReadOptions()
+		ro.SetTimestamp(u64(c.ts))
+		v, err := db.GetBytes(ro, []byte("key"))
+		ro.Destroy()
+		ensure.Nil(t, err)
+		ensure.DeepEqual(t, v, c.value)
+	}
+}
+
 type bytesReverseComparator struct{}
 
 func (cmp *bytesReverseComparator) Name() string { return "gorocksdb.bytes-reverse" }
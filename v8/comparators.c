@@ -0,0 +1,117 @@
+#include <stdio.h>
+#include <string.h>
+#include "gorocksdb.h"
+
+// Built-in comparators implemented in C, so that comparing keys does not
+// need to call into Go.
+
+typedef struct {
+    char name[64];
+} gorocksdb_native_comparator_t;
+
+static void gorocksdb_native_comparator_destroy(void* state) {
+    free(state);
+}
+
+static const char* gorocksdb_native_comparator_name(void* state) {
+    return ((gorocksdb_native_comparator_t*)state)->name;
+}
+
+static gorocksdb_native_comparator_t* gorocksdb_native_comparator_state(const char* name) {
+    gorocksdb_native_comparator_t* state = malloc(sizeof(gorocksdb_native_comparator_t));
+    snprintf(state->name, sizeof(state->name), "%s", name);
+    return state;
+}
+
+static int gorocksdb_bytewise_compare(const char* a, size_t alen, const char* b, size_t blen) {
+    size_t n = alen < blen ? alen : blen;
+    int r = n > 0 ? memcmp(a, b, n) : 0;
+    if (r == 0) {
+        r = alen < blen ? -1 : (alen > blen ? 1 : 0);
+    }
+    return r;
+}
+
+static uint64_t gorocksdb_decode_fixed64(const char* p, int big_endian) {
+    const unsigned char* b = (const unsigned char*)p;
+    uint64_t v = 0;
+    for (int i = 0; i < 8; i++) {
+        v = (v << 8) | b[big_endian ? i : 7 - i];
+    }
+    return v;
+}
+
+/* reverse bytewise */
+
+static int gorocksdb_reverse_bytewise_compare(void* state, const char* a, size_t alen, const char* b, size_t blen) {
+    return -gorocksdb_bytewise_compare(a, alen, b, blen);
+}
+
+rocksdb_comparator_t* gorocksdb_comparator_create_reverse_bytewise() {
+    return rocksdb_comparator_create(
+        gorocksdb_native_comparator_state("rocksdb.ReverseBytewiseComparator"),
+        gorocksdb_native_comparator_destroy,
+        gorocksdb_reverse_bytewise_compare,
+        gorocksdb_native_comparator_name);
+}
+
+/* big-endian uint64 */
+
+static int gorocksdb_uint64_compare(void* state, const char* a, size_t alen, const char* b, size_t blen) {
+    if (alen != 8 || blen != 8) {
+        return gorocksdb_bytewise_compare(a, alen, b, blen);
+    }
+    uint64_t x = gorocksdb_decode_fixed64(a, 1);
+    uint64_t y = gorocksdb_decode_fixed64(b, 1);
+    return x < y ? -1 : (x > y ? 1 : 0);
+}
+
+rocksdb_comparator_t* gorocksdb_comparator_create_uint64() {
+    return rocksdb_comparator_create(
+        gorocksdb_native_comparator_state("gorocksdb.BigEndianUint64Comparator"),
+        gorocksdb_native_comparator_destroy,
+        gorocksdb_uint64_compare,
+        gorocksdb_native_comparator_name);
+}
+
+/* bytewise with uint64 timestamp */
+
+static int gorocksdb_u64ts_compare_ts(void* state, const char* a_ts, size_t a_tslen, const char* b_ts, size_t b_tslen) {
+    uint64_t x = gorocksdb_decode_fixed64(a_ts, 0);
+    uint64_t y = gorocksdb_decode_fixed64(b_ts, 0);
+    return x < y ? -1 : (x > y ? 1 : 0);
+}
+
+static int gorocksdb_u64ts_compare_without_ts(void* state, const char* a, size_t alen, unsigned char a_has_ts, const char* b, size_t blen, unsigned char b_has_ts) {
+    // Keys too short to hold a timestamp are compared as a whole.
+    if (a_has_ts && alen >= 8) {
+        alen -= 8;
+    }
+    if (b_has_ts && blen >= 8) {
+        blen -= 8;
+    }
+    return gorocksdb_bytewise_compare(a, alen, b, blen);
+}
+
+static int gorocksdb_u64ts_compare(void* state, const char* a, size_t alen, const char* b, size_t blen) {
+    if (alen < 8 || blen < 8) {
+        return gorocksdb_bytewise_compare(a, alen, b, blen);
+    }
+    int r = gorocksdb_u64ts_compare_without_ts(state, a, alen, 1, b, blen, 1);
+    if (r == 0) {
+        // Newer timestamps come first.
+        r = -gorocksdb_u64ts_compare_ts(state, a + alen - 8, 8, b + blen - 8, 8);
+    }
+    return r;
+}
+
+rocksdb_comparator_t* gorocksdb_comparator_create_bytewise_u64ts() {
+    return rocksdb_comparator_with_ts_create(
+        gorocksdb_native_comparator_state("leveldb.BytewiseComparator.u64ts"),
+        gorocksdb_native_comparator_destroy,
+        gorocksdb_u64ts_compare,
+        gorocksdb_u64ts_compare_ts,
+        gorocksdb_u64ts_compare_without_ts,
+        gorocksdb_native_comparator_name,
+        8);
+}
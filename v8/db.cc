@@ -0,0 +1,122 @@
+#include <vector>
+
+#include "gorocksdb_internal.h"
+
+using rocksdb::ColumnFamilyHandle;
+using rocksdb::CompactionOptions;
+using rocksdb::DB;
+using rocksdb::GetMergeOperandsOptions;
+using rocksdb::IngestExternalFileArg;
+using rocksdb::IngestExternalFileOptions;
+using rocksdb::PinnableSlice;
+using rocksdb::ReadOptions;
+using rocksdb::Range;
+using rocksdb::Slice;
+using rocksdb::TablePropertiesCollection;
+
+extern "C" {
+
+void gorocksdb_ingest_external_files(
+    rocksdb_t* db, size_t num_args, rocksdb_column_family_handle_t* const* cfs,
+    const size_t* num_files, const char* const* files,
+    rocksdb_ingestexternalfileoptions_t* const* options, char** errptr) {
+  std::vector<IngestExternalFileArg> args(num_args);
+  size_t f = 0;
+  for (size_t i = 0; i < num_args; i++) {
+    // A null column family or options stands for the default ones.
+    args[i].column_family = cfs[i] != nullptr
+                                ? gorocksdb::Rep<ColumnFamilyHandle>(cfs[i])
+                                : gorocksdb::Rep<DB>(db)->DefaultColumnFamily();
+    for (size_t j = 0; j < num_files[i]; j++) {
+      args[i].external_files.emplace_back(files[f++]);
+    }
+    if (options[i] != nullptr) {
+      args[i].options =
+          gorocksdb::RepValue<IngestExternalFileOptions>(options[i]);
+    }
+  }
+  gorocksdb::SaveError(errptr, gorocksdb::Rep<DB>(db)->IngestExternalFiles(args));
+}
+
+int gorocksdb_get_merge_operands(rocksdb_t* db, rocksdb_readoptions_t* options,
+                                 rocksdb_column_family_handle_t* cf,
+                                 const char* key, size_t key_len,
+                                 int max_operands, char** values,
+                                 size_t* values_len, char** errptr) {
+  DB* rep = gorocksdb::Rep<DB>(db);
+  ColumnFamilyHandle* handle = cf != nullptr
+                                   ? gorocksdb::Rep<ColumnFamilyHandle>(cf)
+                                   : rep->DefaultColumnFamily();
+  std::vector<PinnableSlice> operands(max_operands);
+  GetMergeOperandsOptions merge_operands_options;
+  merge_operands_options.expected_max_number_of_operands = max_operands;
+  int num_operands = 0;
+  rocksdb::Status s = rep->GetMergeOperands(
+      gorocksdb::RepValue<ReadOptions>(options), handle, Slice(key, key_len),
+      operands.data(), &merge_operands_options, &num_operands);
+  if (s.IsNotFound()) {
+    return 0;
+  }
+  if (gorocksdb::SaveError(errptr, s)) {
+    return 0;
+  }
+  for (int i = 0; i < num_operands; i++) {
+    values[i] = static_cast<char*>(malloc(operands[i].size() + 1));
+    memcpy(values[i], operands[i].data(), operands[i].size());
+    values_len[i] = operands[i].size();
+  }
+  return num_operands;
+}
+
+gorocksdb_tablepropertiescollection_t* gorocksdb_get_properties_of_all_tables(
+    rocksdb_t* db, rocksdb_column_family_handle_t* cf, char** errptr) {
+  DB* rep = gorocksdb::Rep<DB>(db);
+  ColumnFamilyHandle* handle = cf != nullptr
+                                   ? gorocksdb::Rep<ColumnFamilyHandle>(cf)
+                                   : rep->DefaultColumnFamily();
+  TablePropertiesCollection props;
+  if (gorocksdb::SaveError(errptr,
+                           rep->GetPropertiesOfAllTables(handle, &props))) {
+    return nullptr;
+  }
+  return gorocksdb::NewTablePropertiesCollection(props);
+}
+
+gorocksdb_tablepropertiescollection_t*
+gorocksdb_get_properties_of_tables_in_range(
+    rocksdb_t* db, rocksdb_column_family_handle_t* cf, size_t num_ranges,
+    const char* const* start_keys, const size_t* start_keys_len,
+    const char* const* limit_keys, const size_t* limit_keys_len,
+    char** errptr) {
+  DB* rep = gorocksdb::Rep<DB>(db);
+  ColumnFamilyHandle* handle = cf != nullptr
+                                   ? gorocksdb::Rep<ColumnFamilyHandle>(cf)
+                                   : rep->DefaultColumnFamily();
+  std::vector<Range> ranges;
+  for (size_t i = 0; i < num_ranges; i++) {
+    ranges.emplace_back(Slice(start_keys[i], start_keys_len[i]),
+                        Slice(limit_keys[i], limit_keys_len[i]));
+  }
+  TablePropertiesCollection props;
+  if (gorocksdb::SaveError(
+          errptr, rep->GetPropertiesOfTablesInRange(handle, ranges.data(),
+                                                    ranges.size(), &props))) {
+    return nullptr;
+  }
+  return gorocksdb::NewTablePropertiesCollection(props);
+}
+
+void gorocksdb_compact_files(rocksdb_t* db, rocksdb_column_family_handle_t* cf,
+                             const char* const* files, size_t num_files,
+                             int output_level, char** errptr) {
+  DB* rep = gorocksdb::Rep<DB>(db);
+  ColumnFamilyHandle* handle = cf != nullptr
+                                   ? gorocksdb::Rep<ColumnFamilyHandle>(cf)
+                                   : rep->DefaultColumnFamily();
+  std::vector<std::string> input_file_names(files, files + num_files);
+  gorocksdb::SaveError(errptr,
+                       rep->CompactFiles(CompactionOptions(), handle,
+                                         input_file_names, output_level));
+}
+
+}  // extern "C"
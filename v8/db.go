@@ -2,6 +2,7 @@ package gorocksdb
 
 // #include <stdlib.h>
 // #include "rocksdb/c.h"
+// #include "gorocksdb.h"
 import "C"
 import (
 	"errors"
@@ -22,6 +23,8 @@ type DB struct {
 	name          string
 	secondaryPath string
 	opts          *Options
+	hooks         hookList
+	errorListener *errorListener
 }
 
 // OpenDb opens a database with the specified options.
@@ -31,15 +34,19 @@ func OpenDb(opts *Options, name string) (*DB, error) {
 		cName = C.CString(name)
 	)
 	defer C.free(unsafe.Pointer(cName))
-	db := C.rocksdb_open(opts.c, cName, &cErr)
+	cDBOpts, errorListener, freeOpts := opts.openOptions()
+	defer freeOpts()
+	db := C.rocksdb_open(cDBOpts, cName, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		errorListener.release()
 		return nil, errors.New(C.GoString(cErr))
 	}
 	return &DB{
-		name: name,
-		c:    db,
-		opts: opts,
+		name:          name,
+		c:             db,
+		opts:          opts,
+		errorListener: errorListener,
 	}, nil
 }
 
@@ -50,15 +57,19 @@ func OpenDbWithTTL(opts *Options, name string, ttl int) (*DB, error) {
 		cName = C.CString(name)
 	)
 	defer C.free(unsafe.Pointer(cName))
-	db := C.rocksdb_open_with_ttl(opts.c, cName, C.int(ttl), &cErr)
+	cDBOpts, errorListener, freeOpts := opts.openOptions()
+	defer freeOpts()
+	db := C.rocksdb_open_with_ttl(cDBOpts, cName, C.int(ttl), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		errorListener.release()
 		return nil, errors.New(C.GoString(cErr))
 	}
 	return &DB{
-		name: name,
-		c:    db,
-		opts: opts,
+		name:          name,
+		c:             db,
+		opts:          opts,
+		errorListener: errorListener,
 	}, nil
 }
 
@@ -114,8 +125,10 @@ func OpenDbColumnFamilies(
 	cHandles := make([]*C.rocksdb_column_family_handle_t, numColumnFamilies)
 
 	var cErr *C.char
+	cDBOpts, errorListener, freeOpts := opts.openOptions()
+	defer freeOpts()
 	db := C.rocksdb_open_column_families(
-		opts.c,
+		cDBOpts,
 		cName,
 		C.int(numColumnFamilies),
 		&cNames[0],
@@ -125,6 +138,7 @@ func OpenDbColumnFamilies(
 	)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		errorListener.release()
 		return nil, nil, errors.New(C.GoString(cErr))
 	}
 
@@ -134,9 +148,10 @@ func OpenDbColumnFamilies(
 	}
 
 	return &DB{
-		name: name,
-		c:    db,
-		opts: opts,
+		name:          name,
+		c:             db,
+		opts:          opts,
+		errorListener: errorListener,
 	}, cfHandles, nil
 }
 
@@ -183,8 +198,10 @@ func OpenDbColumnFamiliesWithTTL(
 	}
 
 	var cErr *C.char
+	cDBOpts, errorListener, freeOpts := opts.openOptions()
+	defer freeOpts()
 	db := C.rocksdb_open_column_families_with_ttl(
-		opts.c,
+		cDBOpts,
 		cName,
 		C.int(numColumnFamilies),
 		&cNames[0],
@@ -195,6 +212,7 @@ func OpenDbColumnFamiliesWithTTL(
 	)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		errorListener.release()
 		return nil, nil, errors.New(C.GoString(cErr))
 	}
 
@@ -204,9 +222,10 @@ func OpenDbColumnFamiliesWithTTL(
 	}
 
 	return &DB{
-		name: name,
-		c:    db,
-		opts: opts,
+		name:          name,
+		c:             db,
+		opts:          opts,
+		errorListener: errorListener,
 	}, cfHandles, nil
 }
 
@@ -401,7 +420,10 @@ func (db *DB) SecondaryPath() string {
 }
 
 // Get returns the data associated with the key from the database.
-func (db *DB) Get(opts *ReadOptions, key []byte) (*Slice, error) {
+func (db *DB) Get(opts *ReadOptions, key []byte) (_ *Slice, err error) {
+	if call := beginOp(&db.hooks, OpGet, nil, len(key), 0, 1); call != nil {
+		defer call.end(&err)
+	}
 	var (
 		cErr    *C.char
 		cValLen C.size_t
@@ -416,7 +438,10 @@ func (db *DB) Get(opts *ReadOptions, key []byte) (*Slice, error) {
 }
 
 // GetBytes is like Get but returns a copy of the data.
-func (db *DB) GetBytes(opts *ReadOptions, key []byte) ([]byte, error) {
+func (db *DB) GetBytes(opts *ReadOptions, key []byte) (_ []byte, err error) {
+	if call := beginOp(&db.hooks, OpGet, nil, len(key), 0, 1); call != nil {
+		defer call.end(&err)
+	}
 	var (
 		cErr    *C.char
 		cValLen C.size_t
@@ -435,7 +460,10 @@ func (db *DB) GetBytes(opts *ReadOptions, key []byte) ([]byte, error) {
 }
 
 // GetCF returns the data associated with the key from the database and column family.
-func (db *DB) GetCF(opts *ReadOptions, cf *ColumnFamilyHandle, key []byte) (*Slice, error) {
+func (db *DB) GetCF(opts *ReadOptions, cf *ColumnFamilyHandle, key []byte) (_ *Slice, err error) {
+	if call := beginOp(&db.hooks, OpGet, cf, len(key), 0, 1); call != nil {
+		defer call.end(&err)
+	}
 	var (
 		cErr    *C.char
 		cValLen C.size_t
@@ -450,7 +478,10 @@ func (db *DB) GetCF(opts *ReadOptions, cf *ColumnFamilyHandle, key []byte) (*Sli
 }
 
 // GetPinned returns the data associated with the key from the database.
-func (db *DB) GetPinned(opts *ReadOptions, key []byte) (*PinnableSliceHandle, error) {
+func (db *DB) GetPinned(opts *ReadOptions, key []byte) (_ *PinnableSliceHandle, err error) {
+	if call := beginOp(&db.hooks, OpGet, nil, len(key), 0, 1); call != nil {
+		defer call.end(&err)
+	}
 	var (
 		cErr *C.char
 		cKey = byteToChar(key)
@@ -463,8 +494,42 @@ func (db *DB) GetPinned(opts *ReadOptions, key []byte) (*PinnableSliceHandle, er
 	return NewNativePinnableSliceHandle(cHandle), nil
 }
 
+// GetMergeOperands returns the operands merged into the key of the column
+// family, oldest first, without merging them. If the key was last written by
+// Put, its value is returned as the only operand. If cf is nil the default
+// column family is used. It fails if the key has more than maxOperands operands.
+func (db *DB) GetMergeOperands(opts *ReadOptions, cf *ColumnFamilyHandle, key []byte, maxOperands int) ([][]byte, error) {
+	if maxOperands <= 0 {
+		return nil, errors.New("maxOperands must be positive")
+	}
+	var (
+		cErr     *C.char
+		cKey     = byteToChar(key)
+		cCF      *C.rocksdb_column_family_handle_t
+		vals     = make(charsSlice, maxOperands)
+		valSizes = make(sizeTSlice, maxOperands)
+	)
+	if cf != nil {
+		cCF = cf.c
+	}
+	n := int(C.gorocksdb_get_merge_operands(db.c, opts.c, cCF, cKey, C.size_t(len(key)), C.int(maxOperands), vals.c(), valSizes.c(), &cErr))
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return nil, errors.New(C.GoString(cErr))
+	}
+	operands := make([][]byte, n)
+	for i := range operands {
+		operands[i] = C.GoBytes(unsafe.Pointer(vals[i]), C.int(valSizes[i]))
+		C.rocksdb_free(unsafe.Pointer(vals[i]))
+	}
+	return operands, nil
+}
+
 // MultiGet returns the data associated with the passed keys from the database
-func (db *DB) MultiGet(opts *ReadOptions, keys ...[]byte) (Slices, error) {
+func (db *DB) MultiGet(opts *ReadOptions, keys ...[]byte) (_ Slices, err error) {
+	if call := beginOp(&db.hooks, OpMultiGet, nil, totalSize(keys), 0, len(keys)); call != nil {
+		defer call.end(&err)
+	}
 	cKeys, cKeySizes := byteSlicesToCSlices(keys)
 	defer cKeys.Destroy()
 	vals := make(charsSlice, len(keys))
@@ -515,7 +580,10 @@ func (db *DB) MultiGetCF(opts *ReadOptions, cf *ColumnFamilyHandle, keys ...[]by
 
 // MultiGetCFMultiCF returns the data associated with the passed keys and
 // column families.
-func (db *DB) MultiGetCFMultiCF(opts *ReadOptions, cfs ColumnFamilyHandles, keys [][]byte) (Slices, error) {
+func (db *DB) MultiGetCFMultiCF(opts *ReadOptions, cfs ColumnFamilyHandles, keys [][]byte) (_ Slices, err error) {
+	if call := beginOp(&db.hooks, OpMultiGet, cfs.common(), totalSize(keys), 0, len(keys)); call != nil {
+		defer call.end(&err)
+	}
 	cKeys, cKeySizes := byteSlicesToCSlices(keys)
 	defer cKeys.Destroy()
 	vals := make(charsSlice, len(keys))
@@ -557,101 +625,145 @@ func (db *DB) MultiGetCFMultiCF(opts *ReadOptions, cfs ColumnFamilyHandles, keys
 }
 
 // Put writes data associated with a key to the database.
-func (db *DB) Put(opts *WriteOptions, key, value []byte) error {
+func (db *DB) Put(opts *WriteOptions, key, value []byte) (err error) {
+	if call := beginOp(&db.hooks, OpPut, nil, len(key), len(value), 1); call != nil {
+		defer call.end(&err)
+	}
 	var (
 		cErr   *C.char
 		cKey   = byteToChar(key)
 		cValue = byteToChar(value)
 	)
-	C.rocksdb_put(db.c, opts.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
+	noSpace := C.gorocksdb_put(db.c, opts.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newWriteError(C.GoString(cErr), noSpace)
+	}
+	return nil
+}
+
+// PutWithTS writes data associated with a key and a timestamp to the
+// database, which must use a comparator with timestamps.
+func (db *DB) PutWithTS(opts *WriteOptions, key, ts, value []byte) (err error) {
+	if call := beginOp(&db.hooks, OpPut, nil, len(key), len(value), 1); call != nil {
+		defer call.end(&err)
+	}
+	var (
+		cErr   *C.char
+		cKey   = byteToChar(key)
+		cTs    = byteToChar(ts)
+		cValue = byteToChar(value)
+	)
+	noSpace := C.gorocksdb_put_with_ts(db.c, opts.c, cKey, C.size_t(len(key)), cTs, C.size_t(len(ts)), cValue, C.size_t(len(value)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newWriteError(C.GoString(cErr), noSpace)
 	}
 	return nil
 }
 
 // PutCF writes data associated with a key to the database and column family.
-func (db *DB) PutCF(opts *WriteOptions, cf *ColumnFamilyHandle, key, value []byte) error {
+func (db *DB) PutCF(opts *WriteOptions, cf *ColumnFamilyHandle, key, value []byte) (err error) {
+	if call := beginOp(&db.hooks, OpPut, cf, len(key), len(value), 1); call != nil {
+		defer call.end(&err)
+	}
 	var (
 		cErr   *C.char
 		cKey   = byteToChar(key)
 		cValue = byteToChar(value)
 	)
-	C.rocksdb_put_cf(db.c, opts.c, cf.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
+	noSpace := C.gorocksdb_put_cf(db.c, opts.c, cf.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newWriteError(C.GoString(cErr), noSpace)
 	}
 	return nil
 }
 
 // Delete removes the data associated with the key from the database.
-func (db *DB) Delete(opts *WriteOptions, key []byte) error {
+func (db *DB) Delete(opts *WriteOptions, key []byte) (err error) {
+	if call := beginOp(&db.hooks, OpDelete, nil, len(key), 0, 1); call != nil {
+		defer call.end(&err)
+	}
 	var (
 		cErr *C.char
 		cKey = byteToChar(key)
 	)
-	C.rocksdb_delete(db.c, opts.c, cKey, C.size_t(len(key)), &cErr)
+	noSpace := C.gorocksdb_delete(db.c, opts.c, cKey, C.size_t(len(key)), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newWriteError(C.GoString(cErr), noSpace)
 	}
 	return nil
 }
 
 // DeleteCF removes the data associated with the key from the database and column family.
-func (db *DB) DeleteCF(opts *WriteOptions, cf *ColumnFamilyHandle, key []byte) error {
+func (db *DB) DeleteCF(opts *WriteOptions, cf *ColumnFamilyHandle, key []byte) (err error) {
+	if call := beginOp(&db.hooks, OpDelete, cf, len(key), 0, 1); call != nil {
+		defer call.end(&err)
+	}
 	var (
 		cErr *C.char
 		cKey = byteToChar(key)
 	)
-	C.rocksdb_delete_cf(db.c, opts.c, cf.c, cKey, C.size_t(len(key)), &cErr)
+	noSpace := C.gorocksdb_delete_cf(db.c, opts.c, cf.c, cKey, C.size_t(len(key)), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newWriteError(C.GoString(cErr), noSpace)
 	}
 	return nil
 }
 
 // Merge merges the data associated with the key with the actual data in the database.
-func (db *DB) Merge(opts *WriteOptions, key []byte, value []byte) error {
+func (db *DB) Merge(opts *WriteOptions, key []byte, value []byte) (err error) {
+	if call := beginOp(&db.hooks, OpMerge, nil, len(key), len(value), 1); call != nil {
+		defer call.end(&err)
+	}
 	var (
 		cErr   *C.char
 		cKey   = byteToChar(key)
 		cValue = byteToChar(value)
 	)
-	C.rocksdb_merge(db.c, opts.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
+	noSpace := C.gorocksdb_merge(db.c, opts.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newWriteError(C.GoString(cErr), noSpace)
 	}
 	return nil
 }
 
 // MergeCF merges the data associated with the key with the actual data in the
 // database and column family.
-func (db *DB) MergeCF(opts *WriteOptions, cf *ColumnFamilyHandle, key []byte, value []byte) error {
+func (db *DB) MergeCF(opts *WriteOptions, cf *ColumnFamilyHandle, key []byte, value []byte) (err error) {
+	if call := beginOp(&db.hooks, OpMerge, cf, len(key), len(value), 1); call != nil {
+		defer call.end(&err)
+	}
 	var (
 		cErr   *C.char
 		cKey   = byteToChar(key)
 		cValue = byteToChar(value)
 	)
-	C.rocksdb_merge_cf(db.c, opts.c, cf.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
+	noSpace := C.gorocksdb_merge_cf(db.c, opts.c, cf.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newWriteError(C.GoString(cErr), noSpace)
 	}
 	return nil
 }
 
 // Write writes a WriteBatch to the database
-func (db *DB) Write(opts *WriteOptions, batch *WriteBatch) error {
+func (db *DB) Write(opts *WriteOptions, batch *WriteBatch) (err error) {
+	if set := db.hooks.load(); set != nil {
+		// The size of the batch is only computed when hooks need it.
+		if call := set.beginOp(OpWrite, nil, 0, len(batch.Data()), batch.Count()); call != nil {
+			defer call.end(&err)
+		}
+	}
 	var cErr *C.char
-	C.rocksdb_write(db.c, opts.c, batch.c, &cErr)
+	noSpace := C.gorocksdb_write(db.c, opts.c, batch.c, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newWriteError(C.GoString(cErr), noSpace)
 	}
 	return nil
 }
@@ -660,14 +772,18 @@ func (db *DB) Write(opts *WriteOptions, batch *WriteBatch) error {
 // ReadOptions given.
 func (db *DB) NewIterator(opts *ReadOptions) *Iterator {
 	cIter := C.rocksdb_create_iterator(db.c, opts.c)
-	return NewNativeIterator(unsafe.Pointer(cIter))
+	iter := NewNativeIterator(unsafe.Pointer(cIter))
+	iter.hooks = db.hooks.load()
+	return iter
 }
 
 // NewIteratorCF returns an Iterator over the the database and column family
 // that uses the ReadOptions given.
 func (db *DB) NewIteratorCF(opts *ReadOptions, cf *ColumnFamilyHandle) *Iterator {
 	cIter := C.rocksdb_create_iterator_cf(db.c, opts.c, cf.c)
-	return NewNativeIterator(unsafe.Pointer(cIter))
+	iter := NewNativeIterator(unsafe.Pointer(cIter))
+	iter.hooks, iter.cf = db.hooks.load(), cf
+	return iter
 }
 
 func (db *DB) GetUpdatesSince(seqNumber uint64) (*WalIterator, error) {
@@ -852,6 +968,77 @@ func (db *DB) GetApproximateSizesCF(cf *ColumnFamilyHandle, ranges []Range) ([]u
 	return sizes, nil
 }
 
+// GetPropertiesOfAllTables returns the properties of all the sst files of the
+// column family, keyed by file path. If cf is nil the default column family
+// is used.
+func (db *DB) GetPropertiesOfAllTables(cf *ColumnFamilyHandle) (map[string]*TableProperties, error) {
+	var (
+		cErr *C.char
+		cCF  *C.rocksdb_column_family_handle_t
+	)
+	if cf != nil {
+		cCF = cf.c
+	}
+	cProps := C.gorocksdb_get_properties_of_all_tables(db.c, cCF, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return nil, errors.New(C.GoString(cErr))
+	}
+	defer C.gorocksdb_tablepropertiescollection_destroy(cProps)
+	return newTablePropertiesCollectionFromNative(cProps), nil
+}
+
+// GetPropertiesOfTablesInRange returns the properties of the sst files of the
+// column family that overlap the ranges, keyed by file path. If cf is nil the
+// default column family is used.
+func (db *DB) GetPropertiesOfTablesInRange(cf *ColumnFamilyHandle, ranges []Range) (map[string]*TableProperties, error) {
+	if len(ranges) == 0 {
+		return map[string]*TableProperties{}, nil
+	}
+
+	cStarts := make([]*C.char, len(ranges))
+	cLimits := make([]*C.char, len(ranges))
+	cStartLens := make([]C.size_t, len(ranges))
+	cLimitLens := make([]C.size_t, len(ranges))
+	for i, r := range ranges {
+		cStarts[i] = (*C.char)(C.CBytes(r.Start))
+		cStartLens[i] = C.size_t(len(r.Start))
+		cLimits[i] = (*C.char)(C.CBytes(r.Limit))
+		cLimitLens[i] = C.size_t(len(r.Limit))
+	}
+
+	defer func() {
+		for i := range ranges {
+			C.free(unsafe.Pointer(cStarts[i]))
+			C.free(unsafe.Pointer(cLimits[i]))
+		}
+	}()
+
+	var (
+		cErr *C.char
+		cCF  *C.rocksdb_column_family_handle_t
+	)
+	if cf != nil {
+		cCF = cf.c
+	}
+	cProps := C.gorocksdb_get_properties_of_tables_in_range(
+		db.c,
+		cCF,
+		C.size_t(len(ranges)),
+		&cStarts[0],
+		&cStartLens[0],
+		&cLimits[0],
+		&cLimitLens[0],
+		&cErr,
+	)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return nil, errors.New(C.GoString(cErr))
+	}
+	defer C.gorocksdb_tablepropertiescollection_destroy(cProps)
+	return newTablePropertiesCollectionFromNative(cProps), nil
+}
+
 // SetOptions dynamically changes options through the SetOptions API.
 func (db *DB) SetOptions(keys, values []string) error {
 	num_keys := len(keys)
@@ -969,6 +1156,14 @@ func (db *DB) CompactRange(r Range) {
 	C.rocksdb_compact_range(db.c, cStart, C.size_t(len(r.Start)), cLimit, C.size_t(len(r.Limit)))
 }
 
+// CompactRangeOpt runs a manual compaction on the Range of keys given with
+// provided options. This is not likely to be needed for typical usage.
+func (db *DB) CompactRangeOpt(r Range, opt *CompactRangeOptions) {
+	cStart := byteToChar(r.Start)
+	cLimit := byteToChar(r.Limit)
+	C.rocksdb_compact_range_opt(db.c, opt.c, cStart, C.size_t(len(r.Start)), cLimit, C.size_t(len(r.Limit)))
+}
+
 // CompactRangeCF runs a manual compaction on the Range of keys given on the
 // given column family. This is not likely to be needed for typical usage.
 func (db *DB) CompactRangeCF(cf *ColumnFamilyHandle, r Range) {
@@ -985,13 +1180,46 @@ func (db *DB) CompactRangeCFOpt(cf *ColumnFamilyHandle, r Range, opt *CompactRan
 	C.rocksdb_compact_range_cf_opt(db.c, cf.c, opt.c, cStart, C.size_t(len(r.Start)), cLimit, C.size_t(len(r.Limit)))
 }
 
+// CompactFiles compacts the given table files of the column family into
+// the output level. The file names are those returned by
+// GetLiveFilesMetaData, and must all belong to the column family. If cf is
+// nil the default column family is used. Unlike CompactRange, it leaves the
+// choice of the files to the caller, e.g. a custom compaction scheduler.
+func (db *DB) CompactFiles(cf *ColumnFamilyHandle, inputFileNames []string, outputLevel int) error {
+	if len(inputFileNames) == 0 {
+		return errors.New("no files to compact")
+	}
+	var (
+		cErr   *C.char
+		cCF    *C.rocksdb_column_family_handle_t
+		cFiles = make([]*C.char, len(inputFileNames))
+	)
+	if cf != nil {
+		cCF = cf.c
+	}
+	for i, name := range inputFileNames {
+		cFiles[i] = C.CString(name)
+	}
+	defer func() {
+		for _, s := range cFiles {
+			C.free(unsafe.Pointer(s))
+		}
+	}()
+	C.gorocksdb_compact_files(db.c, cCF, &cFiles[0], C.size_t(len(cFiles)), C.int(outputLevel), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
+
 // Flush triggers a manuel flush for the database.
 func (db *DB) Flush(opts *FlushOptions) error {
 	var cErr *C.char
-	C.rocksdb_flush(db.c, opts.c, &cErr)
+	noSpace := C.gorocksdb_flush(db.c, opts.c, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newWriteError(C.GoString(cErr), noSpace)
 	}
 	return nil
 }
@@ -999,10 +1227,10 @@ func (db *DB) Flush(opts *FlushOptions) error {
 // FlushCF triggers a manual flush for the column family.
 func (db *DB) FlushCF(cf *ColumnFamilyHandle, opts *FlushOptions) error {
 	var cErr *C.char
-	C.rocksdb_flush_cf(db.c, opts.c, cf.c, &cErr)
+	noSpace := C.gorocksdb_flush_cf(db.c, opts.c, cf.c, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newWriteError(C.GoString(cErr), noSpace)
 	}
 	return nil
 }
@@ -1141,6 +1369,68 @@ func (db *DB) IngestExternalFileCF(handle *ColumnFamilyHandle, filePaths []strin
 	return nil
 }
 
+// IngestExternalFileArg describes the external SST files to ingest into a
+// column family with IngestExternalFiles.
+type IngestExternalFileArg struct {
+	// CF is the column family to ingest into, the default one if nil.
+	CF    *ColumnFamilyHandle
+	Paths []string
+	// Options are the ingestion options, the default ones if nil.
+	Options *IngestExternalFileOptions
+}
+
+// IngestExternalFiles loads external SST files into multiple column families
+// atomically: either the files of all the column families are ingested, or
+// none of them is.
+func (db *DB) IngestExternalFiles(args []IngestExternalFileArg) error {
+	if len(args) == 0 {
+		return nil
+	}
+
+	cCFs := make([]*C.rocksdb_column_family_handle_t, len(args))
+	cOpts := make([]*C.rocksdb_ingestexternalfileoptions_t, len(args))
+	cNumFiles := make([]C.size_t, len(args))
+	var cFilePaths []*C.char
+	for i, arg := range args {
+		if arg.CF != nil {
+			cCFs[i] = arg.CF.c
+		}
+		if arg.Options != nil {
+			cOpts[i] = arg.Options.c
+		}
+		cNumFiles[i] = C.size_t(len(arg.Paths))
+		for _, s := range arg.Paths {
+			cFilePaths = append(cFilePaths, C.CString(s))
+		}
+	}
+	defer func() {
+		for _, s := range cFilePaths {
+			C.free(unsafe.Pointer(s))
+		}
+	}()
+	if len(cFilePaths) == 0 {
+		return errors.New("no external files to ingest")
+	}
+
+	var cErr *C.char
+
+	C.gorocksdb_ingest_external_files(
+		db.c,
+		C.size_t(len(args)),
+		&cCFs[0],
+		&cNumFiles[0],
+		&cFilePaths[0],
+		&cOpts[0],
+		&cErr,
+	)
+
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
+
 // NewCheckpoint creates a new Checkpoint for this db.
 func (db *DB) NewCheckpoint() (*Checkpoint, error) {
 	var (
@@ -1160,6 +1450,7 @@ func (db *DB) NewCheckpoint() (*Checkpoint, error) {
 // Close closes the database.
 func (db *DB) Close() {
 	C.rocksdb_close(db.c)
+	db.errorListener.release()
 }
 
 // TryCatchUpWithPrimary will sync a secondary db with the state of the primary
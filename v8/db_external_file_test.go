@@ -3,6 +3,7 @@ package gorocksdb
 import (
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/facebookgo/ensure"
@@ -33,8 +34,13 @@ func TestExternalFile(t *testing.T) {
 	err = w.Add([]byte("ddd"), []byte("dddValue"))
 	ensure.Nil(t, err)
 
-	err = w.Finish()
+	info, err := w.Finish()
 	ensure.Nil(t, err)
+	ensure.DeepEqual(t, info.FilePath, filePath.Name())
+	ensure.DeepEqual(t, info.SmallestKey, []byte("aaa"))
+	ensure.DeepEqual(t, info.LargestKey, []byte("ddd"))
+	ensure.DeepEqual(t, info.NumEntries, uint64(4))
+	ensure.True(t, info.FileSize > 0)
 
 	ingestOpts := NewDefaultIngestExternalFileOptions()
 	err = db.IngestExternalFile([]string{filePath.Name()}, ingestOpts)
@@ -55,3 +61,186 @@ func TestExternalFile(t *testing.T) {
 	ensure.Nil(t, err)
 	ensure.DeepEqual(t, v4.Data(), []byte("dddValue"))
 }
+
+func TestSSTFileWriterOperations(t *testing.T) {
+	db := newTestDB(t, "TestSSTFileWriterOperations", func(opts *Options) {
+		opts.SetMergeOperator(&mockMergeOperator{
+			fullMerge: func(key, existingValue []byte, operands [][]byte) ([]byte, bool) {
+				return append(existingValue, operands[0]...), true
+			},
+		})
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	ensure.Nil(t, db.Put(wo, []byte("a"), []byte("old")))
+	ensure.Nil(t, db.Put(wo, []byte("b"), []byte("old")))
+	ensure.Nil(t, db.Put(wo, []byte("c"), []byte("old")))
+	ensure.Nil(t, db.Put(wo, []byte("x"), []byte("old")))
+
+	envOpts := NewDefaultEnvOptions()
+	opts := NewDefaultOptions()
+	w := NewSSTFileWriter(envOpts, opts)
+	defer w.Destroy()
+
+	filePath, err := ioutil.TempFile("", "sst-file-test")
+	ensure.Nil(t, err)
+	defer os.Remove(filePath.Name())
+
+	ensure.Nil(t, w.Open(filePath.Name()))
+	ensure.Nil(t, w.Merge([]byte("a"), []byte("new")))
+	ensure.Nil(t, w.Delete([]byte("b")))
+	ensure.Nil(t, w.Put([]byte("c"), []byte("new")))
+	ensure.Nil(t, w.DeleteRange([]byte("w"), []byte("z")))
+
+	err = w.Put([]byte("b"), []byte("new"))
+	ensure.NotNil(t, err)
+	ensure.StringContains(t, err.Error(), `"b"`)
+
+	info, err := w.Finish()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, info.SmallestKey, []byte("a"))
+	ensure.DeepEqual(t, info.LargestKey, []byte("c"))
+	ensure.DeepEqual(t, info.SmallestRangeDelKey, []byte("w"))
+	ensure.DeepEqual(t, info.LargestRangeDelKey, []byte("z"))
+	ensure.DeepEqual(t, info.NumEntries, uint64(3))
+	ensure.DeepEqual(t, info.NumRangeDelEntries, uint64(1))
+	ensure.DeepEqual(t, info.SequenceNumber, uint64(0))
+
+	ingestOpts := NewDefaultIngestExternalFileOptions()
+	defer ingestOpts.Destroy()
+	ensure.Nil(t, db.IngestExternalFile([]string{filePath.Name()}, ingestOpts))
+
+	ro := NewDefaultReadOptions()
+	v, err := db.GetBytes(ro, []byte("a"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v, []byte("oldnew"))
+	v, err = db.GetBytes(ro, []byte("b"))
+	ensure.Nil(t, err)
+	ensure.True(t, v == nil)
+	v, err = db.GetBytes(ro, []byte("c"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v, []byte("new"))
+	v, err = db.GetBytes(ro, []byte("x"))
+	ensure.Nil(t, err)
+	ensure.True(t, v == nil)
+}
+
+func TestSSTFileWriterForColumnFamily(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorocksdb-TestSSTFileWriterForColumnFamily")
+	ensure.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	opts := NewDefaultOptions()
+	opts.SetCreateIfMissingColumnFamilies(true)
+	opts.SetCreateIfMissing(true)
+	cfOpts := NewDefaultOptions()
+	cfOpts.SetComparator(&bytesReverseComparator{})
+	db, cfh, err := OpenDbColumnFamilies(opts, dir, []string{"default", "reverse"}, []*Options{opts, cfOpts})
+	ensure.Nil(t, err)
+	defer db.Close()
+
+	envOpts := NewDefaultEnvOptions()
+	w := NewSSTFileWriter(envOpts, opts)
+	defer w.Destroy()
+
+	filePath, err := ioutil.TempFile("", "sst-file-test")
+	ensure.Nil(t, err)
+	defer os.Remove(filePath.Name())
+
+	ensure.Nil(t, w.OpenForColumnFamily(filePath.Name(), cfh[1], cfOpts))
+	ensure.Nil(t, w.Put([]byte("b"), []byte("b")))
+	ensure.Nil(t, w.Put([]byte("a"), []byte("a")))
+	ensure.NotNil(t, w.Put([]byte("c"), []byte("c")))
+
+	info, err := w.Finish()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, info.SmallestKey, []byte("b"))
+	ensure.DeepEqual(t, info.LargestKey, []byte("a"))
+
+	r := NewSSTFileReader(cfOpts)
+	defer r.Destroy()
+	ensure.Nil(t, r.Open(filePath.Name()))
+	props := r.GetTableProperties()
+	ensure.DeepEqual(t, props.ColumnFamilyID, uint64(1))
+	ensure.DeepEqual(t, props.ColumnFamilyName, "reverse")
+
+	ingestOpts := NewDefaultIngestExternalFileOptions()
+	defer ingestOpts.Destroy()
+	ensure.Nil(t, db.IngestExternalFileCF(cfh[1], []string{filePath.Name()}, ingestOpts))
+}
+
+func TestIngestExternalFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorocksdb-TestIngestExternalFiles")
+	ensure.Nil(t, err)
+
+	opts := NewDefaultOptions()
+	opts.SetCreateIfMissingColumnFamilies(true)
+	opts.SetCreateIfMissing(true)
+	db, cfh, err := OpenDbColumnFamilies(opts, dir, []string{"default", "data", "index"}, []*Options{opts, opts, opts})
+	ensure.Nil(t, err)
+	defer db.Close()
+
+	envOpts := NewDefaultEnvOptions()
+	writeFile := func(key, value string) string {
+		w := NewSSTFileWriter(envOpts, opts)
+		defer w.Destroy()
+		f, err := ioutil.TempFile("", "sst-file-test")
+		ensure.Nil(t, err)
+		ensure.Nil(t, w.Open(f.Name()))
+		ensure.Nil(t, w.Put([]byte(key), []byte(value)))
+		_, err = w.Finish()
+		ensure.Nil(t, err)
+		return f.Name()
+	}
+	dataFile := writeFile("row1", "payload")
+	defer os.Remove(dataFile)
+	indexFile := writeFile("idx1", "row1")
+	defer os.Remove(indexFile)
+
+	ingestOpts := NewDefaultIngestExternalFileOptions()
+	defer ingestOpts.Destroy()
+	ingestOpts.SetVerifyChecksumsBeforeIngest(true)
+	ingestOpts.SetWriteGlobalSeqno(false)
+	ingestOpts.SetVerifyFileChecksum(true)
+	ingestOpts.SetFailIfNotBottommostLevel(false)
+
+	err = db.IngestExternalFiles([]IngestExternalFileArg{
+		{CF: cfh[1], Paths: []string{dataFile}, Options: ingestOpts},
+		{CF: cfh[2], Paths: []string{indexFile}, Options: ingestOpts},
+	})
+	ensure.Nil(t, err)
+
+	ro := NewDefaultReadOptions()
+	v1, err := db.GetCF(ro, cfh[1], []byte("row1"))
+	ensure.Nil(t, err)
+	defer v1.Free()
+	ensure.DeepEqual(t, v1.Data(), []byte("payload"))
+	v2, err := db.GetCF(ro, cfh[2], []byte("idx1"))
+	ensure.Nil(t, err)
+	defer v2.Free()
+	ensure.DeepEqual(t, v2.Data(), []byte("row1"))
+
+	// a missing file must fail the ingestion of both column families
+	otherDataFile := writeFile("row2", "payload")
+	defer os.Remove(otherDataFile)
+	err = db.IngestExternalFiles([]IngestExternalFileArg{
+		{CF: cfh[1], Paths: []string{otherDataFile}, Options: ingestOpts},
+		{CF: cfh[2], Paths: []string{filepath.Join(dir, "missing.sst")}, Options: ingestOpts},
+	})
+	ensure.NotNil(t, err)
+	v3, err := db.GetCF(ro, cfh[1], []byte("row2"))
+	ensure.Nil(t, err)
+	defer v3.Free()
+	ensure.False(t, v3.Exists())
+
+	// a nil column family and options stand for the default ones
+	defaultFile := writeFile("key1", "value1")
+	defer os.Remove(defaultFile)
+	err = db.IngestExternalFiles([]IngestExternalFileArg{{Paths: []string{defaultFile}}})
+	ensure.Nil(t, err)
+	v4, err := db.Get(ro, []byte("key1"))
+	ensure.Nil(t, err)
+	defer v4.Free()
+	ensure.DeepEqual(t, v4.Data(), []byte("value1"))
+}
@@ -455,3 +455,49 @@ func TestSecondaryDBColumnFamilies(t *testing.T) {
 	ensure.NotNil(t, s)
 	ensure.DeepEqual(t, s.Data(), []byte("world"))
 }
+
+func TestDBCompactFiles(t *testing.T) {
+	db := newTestDB(t, "TestDBCompactFiles", func(opts *Options) {
+		opts.SetDisableAutoCompactions(true)
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	ro := NewDefaultReadOptions()
+	defer ro.Destroy()
+	fo := NewDefaultFlushOptions()
+	defer fo.Destroy()
+
+	for i := 0; i < 3; i++ {
+		ensure.Nil(t, db.Put(wo, []byte("key"+strconv.Itoa(i)), []byte("value")))
+		ensure.Nil(t, db.Flush(fo))
+	}
+	files := db.GetLiveFilesMetaData()
+	ensure.DeepEqual(t, len(files), 3)
+	var names []string
+	for _, f := range files {
+		ensure.DeepEqual(t, f.Level, 0)
+		names = append(names, f.Name)
+	}
+
+	ensure.Nil(t, db.CompactFiles(nil, names, 2))
+	files = db.GetLiveFilesMetaData()
+	ensure.DeepEqual(t, len(files), 1)
+	ensure.DeepEqual(t, files[0].Level, 2)
+	ensure.DeepEqual(t, files[0].Entries, int64(3))
+
+	ensure.NotNil(t, db.CompactFiles(nil, []string{"/999999.sst"}, 2))
+	ensure.NotNil(t, db.CompactFiles(nil, nil, 2))
+
+	opts := NewCompactRangeOptions()
+	defer opts.Destroy()
+	opts.SetAllowWriteStall(true)
+	opts.SetBottommostLevelCompaction(KForce)
+	db.CompactRangeOpt(Range{}, opts)
+	for i := 0; i < 3; i++ {
+		v, err := db.GetBytes(ro, []byte("key"+strconv.Itoa(i)))
+		ensure.Nil(t, err)
+		ensure.DeepEqual(t, v, []byte("value"))
+	}
+}
@@ -3,5 +3,6 @@
 
 package gorocksdb
 
+// #cgo CXXFLAGS: -std=c++17
 // #cgo LDFLAGS: -lrocksdb -lstdc++ -lm -lz -lbz2 -lsnappy -llz4 -lzstd -ldl
 import "C"
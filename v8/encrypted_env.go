@@ -0,0 +1,326 @@
+package gorocksdb
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// KeyProvider supplies the keys of an encrypted Env. Keys are identified by
+// an ID stored in the header of every file, so that the current key can be
+// rotated while the files encrypted with the previous keys remain readable.
+type KeyProvider interface {
+	// CurrentKey returns the key encrypting the new files and its ID, which
+	// must not be longer than 64 bytes.
+	CurrentKey() (id string, key []byte, err error)
+
+	// Key returns the key with the given ID.
+	Key(id string) ([]byte, error)
+}
+
+// ErrWrongEncryptionKey is returned when a file was encrypted with another
+// key than the one the KeyProvider returns for its ID.
+var ErrWrongEncryptionKey = errors.New("wrong encryption key")
+
+// ErrNotEncrypted is returned when reading a file without an encryption
+// header, e.g. a file written by an unencrypted Env.
+var ErrNotEncrypted = errors.New("file is not encrypted")
+
+// KeyRing is a KeyProvider holding the keys in memory.
+type KeyRing struct {
+	mu      sync.RWMutex
+	current string
+	keys    map[string][]byte
+}
+
+// NewKeyRing creates an empty KeyRing.
+func NewKeyRing() *KeyRing {
+	return &KeyRing{keys: make(map[string][]byte)}
+}
+
+// AddKey adds a key and makes it the current key.
+func (r *KeyRing) AddKey(id string, key []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys[id] = append([]byte(nil), key...)
+	r.current = id
+}
+
+// CurrentKey implements KeyProvider.
+func (r *KeyRing) CurrentKey() (string, []byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	key, ok := r.keys[r.current]
+	if !ok {
+		return "", nil, errors.New("no encryption key")
+	}
+	return r.current, key, nil
+}
+
+// Key implements KeyProvider.
+func (r *KeyRing) Key(id string) ([]byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	key, ok := r.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown encryption key %q", id)
+	}
+	return key, nil
+}
+
+// NewEncryptedEnv creates an Env encrypting all the files of the DB, stored
+// in the FileSystem of base, with AES in CTR mode. base must outlive the
+// returned Env.
+func NewEncryptedEnv(base *Env, keys KeyProvider) *Env {
+	return NewEncryptedEnvWithCipher(base, keys, aes.NewCipher)
+}
+
+// NewEncryptedEnvWithCipher creates an Env encrypting all the files of the
+// DB with the block cipher created by newCipher, in CTR mode. The block size
+// of the cipher must not exceed 16 bytes.
+func NewEncryptedEnvWithCipher(base *Env, keys KeyProvider, newCipher func(key []byte) (cipher.Block, error)) *Env {
+	return NewFileSystemEnv(&encryptedFileSystem{
+		FileSystem: NewEnvFileSystem(base),
+		keys:       keys,
+		newCipher:  newCipher,
+	})
+}
+
+// Every encrypted file starts with a header of encryptionHeaderLen bytes:
+//
+//	magic (8) | key ID length (1) | key ID (64) | IV (16) | key check (16)
+//
+// followed by the data encrypted in CTR mode. The key check is a MAC of the
+// IV, telling whether the key of the ID is the one the file was encrypted
+// with.
+const (
+	encryptionMagic     = "GRDBENC1"
+	encryptionMaxKeyID  = 64
+	encryptionIVLen     = 16
+	encryptionCheckLen  = 16
+	encryptionHeaderLen = 128
+)
+
+type encryptedFileSystem struct {
+	FileSystem
+	keys      KeyProvider
+	newCipher func(key []byte) (cipher.Block, error)
+}
+
+// fileCipher encrypts the data of a file.
+type fileCipher struct {
+	block cipher.Block
+	iv    []byte
+}
+
+// streamAt returns the CTR stream starting at offset of the data.
+func (c *fileCipher) streamAt(offset uint64) cipher.Stream {
+	bs := uint64(c.block.BlockSize())
+	// Add the block index to the IV, as a big-endian counter.
+	iv := append([]byte(nil), c.iv[:bs]...)
+	carry := offset / bs
+	for i := len(iv) - 1; i >= 0 && carry > 0; i-- {
+		sum := uint64(iv[i]) + carry&0xff
+		iv[i] = byte(sum)
+		carry = carry>>8 + sum>>8
+	}
+	stream := cipher.NewCTR(c.block, iv)
+	if skip := offset % bs; skip > 0 {
+		buf := make([]byte, skip)
+		stream.XORKeyStream(buf, buf)
+	}
+	return stream
+}
+
+func keyCheck(key, iv []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(iv)
+	return mac.Sum(nil)[:encryptionCheckLen]
+}
+
+// newHeader returns the header and the cipher of a new file.
+func (fs *encryptedFileSystem) newHeader() ([]byte, *fileCipher, error) {
+	id, key, err := fs.keys.CurrentKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(id) > encryptionMaxKeyID {
+		return nil, nil, fmt.Errorf("encryption key ID %q is too long", id)
+	}
+	block, err := fs.newCipher(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if block.BlockSize() > encryptionIVLen {
+		return nil, nil, errors.New("cipher block size is too large")
+	}
+	header := make([]byte, encryptionHeaderLen)
+	copy(header, encryptionMagic)
+	header[8] = byte(len(id))
+	copy(header[9:], id)
+	iv := header[9+encryptionMaxKeyID : 9+encryptionMaxKeyID+encryptionIVLen]
+	if _, err := rand.Read(iv); err != nil {
+		return nil, nil, err
+	}
+	copy(header[9+encryptionMaxKeyID+encryptionIVLen:], keyCheck(key, iv))
+	return header, &fileCipher{block, iv}, nil
+}
+
+// parseHeader returns the cipher of the file with the header given.
+func (fs *encryptedFileSystem) parseHeader(name string, header []byte) (*fileCipher, error) {
+	if len(header) < encryptionHeaderLen || string(header[:8]) != encryptionMagic || header[8] > encryptionMaxKeyID {
+		return nil, fmt.Errorf("%s: %w", name, ErrNotEncrypted)
+	}
+	id := string(header[9 : 9+header[8]])
+	iv := header[9+encryptionMaxKeyID : 9+encryptionMaxKeyID+encryptionIVLen]
+	check := header[9+encryptionMaxKeyID+encryptionIVLen : 9+encryptionMaxKeyID+encryptionIVLen+encryptionCheckLen]
+	key, err := fs.keys.Key(id)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(check, keyCheck(key, iv)) {
+		return nil, fmt.Errorf("%s: %w", name, ErrWrongEncryptionKey)
+	}
+	block, err := fs.newCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return &fileCipher{block, iv}, nil
+}
+
+func (fs *encryptedFileSystem) Name() string { return "gorocksdb.EncryptedFileSystem" }
+
+func (fs *encryptedFileSystem) NewSequentialFile(name string) (SequentialFile, error) {
+	f, err := fs.FileSystem.NewSequentialFile(name)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, encryptionHeaderLen)
+	n, err := io.ReadFull(f, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		f.Close()
+		return nil, err
+	}
+	if n == 0 {
+		// A crash can leave a new file empty, before its header was
+		// written; it holds no data.
+		return f, nil
+	}
+	c, err := fs.parseHeader(name, header[:n])
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &encryptedSequentialFile{SequentialFile: f, cipher: c, stream: c.streamAt(0)}, nil
+}
+
+func (fs *encryptedFileSystem) NewRandomAccessFile(name string) (RandomAccessFile, error) {
+	f, err := fs.FileSystem.NewRandomAccessFile(name)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, encryptionHeaderLen)
+	n, err := f.ReadAt(header, 0)
+	if err != nil && err != io.EOF {
+		f.Close()
+		return nil, err
+	}
+	if n == 0 {
+		return f, nil
+	}
+	c, err := fs.parseHeader(name, header[:n])
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &encryptedRandomAccessFile{RandomAccessFile: f, cipher: c}, nil
+}
+
+func (fs *encryptedFileSystem) NewWritableFile(name string) (WritableFile, error) {
+	header, c, err := fs.newHeader()
+	if err != nil {
+		return nil, err
+	}
+	f, err := fs.FileSystem.NewWritableFile(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Write(header); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &encryptedWritableFile{WritableFile: f, cipher: c, stream: c.streamAt(0)}, nil
+}
+
+func (fs *encryptedFileSystem) GetFileSize(name string) (uint64, error) {
+	size, err := fs.FileSystem.GetFileSize(name)
+	if err != nil || size < encryptionHeaderLen {
+		return 0, err
+	}
+	return size - encryptionHeaderLen, nil
+}
+
+type encryptedSequentialFile struct {
+	SequentialFile
+	cipher *fileCipher
+	stream cipher.Stream
+	offset uint64
+}
+
+func (f *encryptedSequentialFile) Read(p []byte) (int, error) {
+	n, err := f.SequentialFile.Read(p)
+	f.stream.XORKeyStream(p[:n], p[:n])
+	f.offset += uint64(n)
+	return n, err
+}
+
+func (f *encryptedSequentialFile) Skip(n uint64) error {
+	if err := f.SequentialFile.Skip(n); err != nil {
+		return err
+	}
+	f.offset += n
+	f.stream = f.cipher.streamAt(f.offset)
+	return nil
+}
+
+type encryptedRandomAccessFile struct {
+	RandomAccessFile
+	cipher *fileCipher
+}
+
+func (f *encryptedRandomAccessFile) ReadAt(p []byte, off int64) (int, error) {
+	n, err := f.RandomAccessFile.ReadAt(p, off+encryptionHeaderLen)
+	f.cipher.streamAt(uint64(off)).XORKeyStream(p[:n], p[:n])
+	return n, err
+}
+
+type encryptedWritableFile struct {
+	WritableFile
+	cipher *fileCipher
+	stream cipher.Stream
+	offset uint64
+	buf    []byte
+}
+
+func (f *encryptedWritableFile) Write(p []byte) (int, error) {
+	// p must not be modified, encrypt it into a separate buffer.
+	if cap(f.buf) < len(p) {
+		f.buf = make([]byte, len(p))
+	}
+	buf := f.buf[:len(p)]
+	f.stream.XORKeyStream(buf, p)
+	n, err := f.WritableFile.Write(buf)
+	f.offset += uint64(n)
+	if n < len(p) {
+		// Resume the stream after the bytes written, for a retry of the
+		// rest.
+		f.stream = f.cipher.streamAt(f.offset)
+	}
+	return n, err
+}
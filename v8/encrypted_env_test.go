@@ -0,0 +1,138 @@
+package gorocksdb
+
+import (
+	"bytes"
+	"crypto/aes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestEncryptedEnv(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorocksdb-TestEncryptedEnv")
+	ensure.Nil(t, err)
+	base := NewDefaultEnv()
+	defer base.Destroy()
+	keys := NewKeyRing()
+	keys.AddKey("k1", bytes.Repeat([]byte{1}, 32))
+
+	open := func(keys KeyProvider) (*DB, *Env, error) {
+		env := NewEncryptedEnv(base, keys)
+		opts := NewDefaultOptions()
+		defer opts.Destroy()
+		opts.SetEnv(env)
+		opts.SetCreateIfMissing(true)
+		db, err := OpenDb(opts, dir)
+		return db, env, err
+	}
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	ro := NewDefaultReadOptions()
+	defer ro.Destroy()
+
+	db, env, err := open(keys)
+	ensure.Nil(t, err)
+	ensure.Nil(t, db.Put(wo, []byte("key1"), []byte("plaintext-value-1")))
+	ensure.Nil(t, db.Flush(NewDefaultFlushOptions()))
+	db.Close()
+	env.Destroy()
+
+	// The values are not stored in clear.
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	ensure.Nil(t, err)
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		ensure.Nil(t, err)
+		ensure.False(t, bytes.Contains(data, []byte("plaintext-value")), file)
+	}
+
+	// Rotate the key, the files encrypted with the previous one are still
+	// readable.
+	keys.AddKey("k2", bytes.Repeat([]byte{2}, 32))
+	db, env, err = open(keys)
+	ensure.Nil(t, err)
+	ensure.Nil(t, db.Put(wo, []byte("key2"), []byte("plaintext-value-2")))
+	ensure.Nil(t, db.Flush(NewDefaultFlushOptions()))
+	for _, key := range []string{"key1", "key2"} {
+		v, err := db.GetBytes(ro, []byte(key))
+		ensure.Nil(t, err)
+		ensure.DeepEqual(t, v, []byte("plaintext-value-"+key[3:]))
+	}
+	db.Close()
+	env.Destroy()
+
+	// Opening with the wrong key fails.
+	wrongKeys := NewKeyRing()
+	wrongKeys.AddKey("k1", bytes.Repeat([]byte{3}, 32))
+	wrongKeys.AddKey("k2", bytes.Repeat([]byte{4}, 32))
+	_, env, err = open(wrongKeys)
+	defer env.Destroy()
+	ensure.NotNil(t, err)
+	ensure.True(t, strings.Contains(err.Error(), ErrWrongEncryptionKey.Error()))
+}
+
+// shortWriteFile fails its first write after writing half of it.
+type shortWriteFile struct {
+	WritableFile
+	failed bool
+}
+
+func (f *shortWriteFile) Write(p []byte) (int, error) {
+	if f.failed || len(p) < 2 {
+		return f.WritableFile.Write(p)
+	}
+	f.failed = true
+	n, _ := f.WritableFile.Write(p[:len(p)/2])
+	return n, errors.New("short write")
+}
+
+func TestEncryptedFileSystemRecovery(t *testing.T) {
+	mem := newMemFileSystem()
+	keys := NewKeyRing()
+	keys.AddKey("k1", bytes.Repeat([]byte{1}, 32))
+	fs := &encryptedFileSystem{FileSystem: mem, keys: keys, newCipher: aes.NewCipher}
+
+	// An empty file, e.g. left by a crash before its header was written, is
+	// read as an empty encrypted file.
+	f, err := mem.NewWritableFile("/empty")
+	ensure.Nil(t, err)
+	ensure.Nil(t, f.Close())
+	sf, err := fs.NewSequentialFile("/empty")
+	ensure.Nil(t, err)
+	n, err := sf.Read(make([]byte, 16))
+	ensure.DeepEqual(t, n, 0)
+	ensure.DeepEqual(t, err, io.EOF)
+	ensure.Nil(t, sf.Close())
+	rf, err := fs.NewRandomAccessFile("/empty")
+	ensure.Nil(t, err)
+	n, err = rf.ReadAt(make([]byte, 16), 0)
+	ensure.DeepEqual(t, n, 0)
+	ensure.DeepEqual(t, err, io.EOF)
+	ensure.Nil(t, rf.Close())
+
+	// Retrying the rest of a short write encrypts it with the right stream.
+	w, err := fs.NewWritableFile("/data")
+	ensure.Nil(t, err)
+	ew := w.(*encryptedWritableFile)
+	ew.WritableFile = &shortWriteFile{WritableFile: ew.WritableFile}
+	data := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+	n, err = w.Write(data)
+	ensure.NotNil(t, err)
+	n2, err := w.Write(data[n:])
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, n+n2, len(data))
+	ensure.Nil(t, w.Close())
+
+	rf, err = fs.NewRandomAccessFile("/data")
+	ensure.Nil(t, err)
+	defer rf.Close()
+	got := make([]byte, len(data))
+	n, err = rf.ReadAt(got, 0)
+	ensure.DeepEqual(t, n, len(data))
+	ensure.DeepEqual(t, got, data)
+}
@@ -0,0 +1,244 @@
+#include "gorocksdb_internal.h"
+#include "rocksdb/env.h"
+#include "rocksdb/file_system.h"
+
+using rocksdb::Env;
+using rocksdb::FileLock;
+using rocksdb::FileOptions;
+using rocksdb::FileSystem;
+using rocksdb::FSDirectory;
+using rocksdb::FSRandomAccessFile;
+using rocksdb::FSSequentialFile;
+using rocksdb::FSWritableFile;
+using rocksdb::IOOptions;
+using rocksdb::IOStatus;
+using rocksdb::Slice;
+
+// gorocksdb_envfs_file_t holds a file opened by gorocksdb_envfs_new_file,
+// only one of the members is set.
+struct gorocksdb_envfs_file_t {
+  std::unique_ptr<FSSequentialFile> sequential;
+  std::unique_ptr<FSRandomAccessFile> random_access;
+  std::unique_ptr<FSWritableFile> writable;
+};
+
+struct gorocksdb_envfs_lock_t {
+  FileLock* rep;
+};
+
+namespace {
+
+// The status codes returned by the gorocksdb_envfs_* functions, see
+// newEnvFileSystemError.
+enum {
+  kEnvFSStatusOK = 0,
+  kEnvFSStatusIOError = 1,
+  kEnvFSStatusNotFound = 2,
+  kEnvFSStatusNoSpace = 3,
+};
+
+int SaveIOError(char** errptr, const IOStatus& s) {
+  if (!gorocksdb::SaveError(errptr, s)) {
+    return kEnvFSStatusOK;
+  }
+  if (s.IsNotFound() || s.IsPathNotFound()) {
+    return kEnvFSStatusNotFound;
+  }
+  if (s.IsNoSpace()) {
+    return kEnvFSStatusNoSpace;
+  }
+  return kEnvFSStatusIOError;
+}
+
+FileSystem* GetFileSystem(rocksdb_env_t* env) {
+  return gorocksdb::Rep<Env>(env)->GetFileSystem().get();
+}
+
+// ReadInto copies result to scratch if the file returned data it owns.
+void ReadInto(const Slice& result, char* scratch, size_t* read) {
+  if (result.data() != scratch && result.size() > 0) {
+    memmove(scratch, result.data(), result.size());
+  }
+  *read = result.size();
+}
+
+}  // namespace
+
+extern "C" {
+
+int gorocksdb_envfs_new_file(rocksdb_env_t* env, int kind, const char* name,
+                             size_t name_len, gorocksdb_envfs_file_t** file,
+                             char** errptr) {
+  FileSystem* fs = GetFileSystem(env);
+  std::string fname(name, name_len);
+  std::unique_ptr<gorocksdb_envfs_file_t> f(new gorocksdb_envfs_file_t);
+  IOStatus s;
+  switch (kind) {
+    case 0:
+      s = fs->NewSequentialFile(fname, FileOptions(), &f->sequential, nullptr);
+      break;
+    case 1:
+      s = fs->NewRandomAccessFile(fname, FileOptions(), &f->random_access,
+                                  nullptr);
+      break;
+    default:
+      s = fs->NewWritableFile(fname, FileOptions(), &f->writable, nullptr);
+      break;
+  }
+  *file = s.ok() ? f.release() : nullptr;
+  return SaveIOError(errptr, s);
+}
+
+int gorocksdb_envfs_file_exists(rocksdb_env_t* env, const char* name,
+                                size_t name_len, char** errptr) {
+  return SaveIOError(errptr,
+                     GetFileSystem(env)->FileExists(std::string(name, name_len),
+                                                    IOOptions(), nullptr));
+}
+
+int gorocksdb_envfs_get_children(rocksdb_env_t* env, const char* dir,
+                                 size_t dir_len, char** names,
+                                 size_t* names_len, char** errptr) {
+  std::vector<std::string> children;
+  IOStatus s = GetFileSystem(env)->GetChildren(std::string(dir, dir_len),
+                                               IOOptions(), &children, nullptr);
+  // The names are returned separated by NUL characters.
+  std::string result;
+  for (const auto& child : children) {
+    result.append(child);
+    result.push_back('\0');
+  }
+  *names = static_cast<char*>(malloc(result.size() + 1));
+  memcpy(*names, result.data(), result.size());
+  *names_len = result.size();
+  return SaveIOError(errptr, s);
+}
+
+int gorocksdb_envfs_delete_file(rocksdb_env_t* env, const char* name,
+                                size_t name_len, char** errptr) {
+  return SaveIOError(errptr,
+                     GetFileSystem(env)->DeleteFile(std::string(name, name_len),
+                                                    IOOptions(), nullptr));
+}
+
+int gorocksdb_envfs_create_dir(rocksdb_env_t* env, const char* name,
+                               size_t name_len, char** errptr) {
+  return SaveIOError(errptr,
+                     GetFileSystem(env)->CreateDir(std::string(name, name_len),
+                                                   IOOptions(), nullptr));
+}
+
+int gorocksdb_envfs_create_dir_if_missing(rocksdb_env_t* env, const char* name,
+                                          size_t name_len, char** errptr) {
+  return SaveIOError(errptr, GetFileSystem(env)->CreateDirIfMissing(
+                                 std::string(name, name_len), IOOptions(),
+                                 nullptr));
+}
+
+int gorocksdb_envfs_delete_dir(rocksdb_env_t* env, const char* name,
+                               size_t name_len, char** errptr) {
+  return SaveIOError(errptr,
+                     GetFileSystem(env)->DeleteDir(std::string(name, name_len),
+                                                   IOOptions(), nullptr));
+}
+
+int gorocksdb_envfs_sync_dir(rocksdb_env_t* env, const char* name,
+                             size_t name_len, char** errptr) {
+  std::unique_ptr<FSDirectory> dir;
+  IOStatus s = GetFileSystem(env)->NewDirectory(std::string(name, name_len),
+                                                IOOptions(), &dir, nullptr);
+  if (s.ok()) {
+    s = dir->Fsync(IOOptions(), nullptr);
+  }
+  return SaveIOError(errptr, s);
+}
+
+int gorocksdb_envfs_get_file_size(rocksdb_env_t* env, const char* name,
+                                  size_t name_len, uint64_t* size,
+                                  char** errptr) {
+  return SaveIOError(errptr, GetFileSystem(env)->GetFileSize(
+                                 std::string(name, name_len), IOOptions(),
+                                 size, nullptr));
+}
+
+int gorocksdb_envfs_get_file_modification_time(rocksdb_env_t* env,
+                                               const char* name,
+                                               size_t name_len, uint64_t* mtime,
+                                               char** errptr) {
+  return SaveIOError(errptr, GetFileSystem(env)->GetFileModificationTime(
+                                 std::string(name, name_len), IOOptions(),
+                                 mtime, nullptr));
+}
+
+int gorocksdb_envfs_rename_file(rocksdb_env_t* env, const char* src,
+                                size_t src_len, const char* target,
+                                size_t target_len, char** errptr) {
+  return SaveIOError(errptr, GetFileSystem(env)->RenameFile(
+                                 std::string(src, src_len),
+                                 std::string(target, target_len), IOOptions(),
+                                 nullptr));
+}
+
+int gorocksdb_envfs_lock_file(rocksdb_env_t* env, const char* name,
+                              size_t name_len, gorocksdb_envfs_lock_t** lock,
+                              char** errptr) {
+  FileLock* rep = nullptr;
+  IOStatus s = GetFileSystem(env)->LockFile(std::string(name, name_len),
+                                            IOOptions(), &rep, nullptr);
+  *lock = s.ok() ? new gorocksdb_envfs_lock_t{rep} : nullptr;
+  return SaveIOError(errptr, s);
+}
+
+int gorocksdb_envfs_unlock_file(rocksdb_env_t* env,
+                                gorocksdb_envfs_lock_t* lock, char** errptr) {
+  IOStatus s = GetFileSystem(env)->UnlockFile(lock->rep, IOOptions(), nullptr);
+  delete lock;
+  return SaveIOError(errptr, s);
+}
+
+int gorocksdb_envfs_file_read(gorocksdb_envfs_file_t* file, char* buf,
+                              size_t n, size_t* read, char** errptr) {
+  Slice result;
+  IOStatus s = file->sequential->Read(n, IOOptions(), &result, buf, nullptr);
+  ReadInto(result, buf, read);
+  return SaveIOError(errptr, s);
+}
+
+int gorocksdb_envfs_file_skip(gorocksdb_envfs_file_t* file, uint64_t n,
+                              char** errptr) {
+  return SaveIOError(errptr, file->sequential->Skip(n));
+}
+
+int gorocksdb_envfs_file_read_at(gorocksdb_envfs_file_t* file, uint64_t offset,
+                                 char* buf, size_t n, size_t* read,
+                                 char** errptr) {
+  Slice result;
+  IOStatus s =
+      file->random_access->Read(offset, n, IOOptions(), &result, buf, nullptr);
+  ReadInto(result, buf, read);
+  return SaveIOError(errptr, s);
+}
+
+int gorocksdb_envfs_file_append(gorocksdb_envfs_file_t* file, const char* data,
+                                size_t n, char** errptr) {
+  return SaveIOError(errptr, file->writable->Append(Slice(data, n),
+                                                    IOOptions(), nullptr));
+}
+
+int gorocksdb_envfs_file_flush(gorocksdb_envfs_file_t* file, char** errptr) {
+  return SaveIOError(errptr, file->writable->Flush(IOOptions(), nullptr));
+}
+
+int gorocksdb_envfs_file_sync(gorocksdb_envfs_file_t* file, char** errptr) {
+  return SaveIOError(errptr, file->writable->Sync(IOOptions(), nullptr));
+}
+
+int gorocksdb_envfs_file_close(gorocksdb_envfs_file_t* file, char** errptr) {
+  return SaveIOError(errptr, file->writable->Close(IOOptions(), nullptr));
+}
+
+void gorocksdb_envfs_file_destroy(gorocksdb_envfs_file_t* file) {
+  delete file;
+}
+
+}  // extern "C"
@@ -0,0 +1,298 @@
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+// #include "gorocksdb.h"
+import "C"
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// NewEnvFileSystem returns the FileSystem of env, e.g. to wrap it in a Go
+// FileSystem turned back into an Env by NewFileSystemEnv. env must outlive
+// the returned FileSystem.
+func NewEnvFileSystem(env *Env) FileSystem {
+	return &envFileSystem{env}
+}
+
+type envFileSystem struct {
+	env *Env
+}
+
+// envFileSystemError is an error returned by the FileSystem of an Env.
+type envFileSystemError struct {
+	msg string
+	err error
+}
+
+func (e *envFileSystemError) Error() string { return e.msg }
+func (e *envFileSystemError) Unwrap() error { return e.err }
+
+// newEnvFileSystemError returns the error of a gorocksdb_envfs_* call
+// returning code, one of the fsStatus codes, and frees cErr.
+func newEnvFileSystemError(code C.int, cErr *C.char) error {
+	if code == fsStatusOK {
+		return nil
+	}
+	defer C.rocksdb_free(unsafe.Pointer(cErr))
+	err := &envFileSystemError{msg: C.GoString(cErr)}
+	switch code {
+	case fsStatusNotFound:
+		err.err = fs.ErrNotExist
+	case fsStatusNoSpace:
+		err.err = syscall.ENOSPC
+	}
+	return err
+}
+
+func (e *envFileSystem) Name() string { return "gorocksdb.EnvFileSystem" }
+
+func (e *envFileSystem) newFile(kind int, name string) (*C.gorocksdb_envfs_file_t, error) {
+	var (
+		cErr  *C.char
+		cFile *C.gorocksdb_envfs_file_t
+		cName = C.CString(name)
+	)
+	defer C.free(unsafe.Pointer(cName))
+	code := C.gorocksdb_envfs_new_file(e.env.c, C.int(kind), cName, C.size_t(len(name)), &cFile, &cErr)
+	return cFile, newEnvFileSystemError(code, cErr)
+}
+
+func (e *envFileSystem) NewSequentialFile(name string) (SequentialFile, error) {
+	cFile, err := e.newFile(0, name)
+	if err != nil {
+		return nil, err
+	}
+	return &envFile{cFile}, nil
+}
+
+func (e *envFileSystem) NewRandomAccessFile(name string) (RandomAccessFile, error) {
+	cFile, err := e.newFile(1, name)
+	if err != nil {
+		return nil, err
+	}
+	return &envFile{cFile}, nil
+}
+
+func (e *envFileSystem) NewWritableFile(name string) (WritableFile, error) {
+	cFile, err := e.newFile(2, name)
+	if err != nil {
+		return nil, err
+	}
+	return &envWritableFile{envFile{cFile}}, nil
+}
+
+// call calls a gorocksdb_envfs_* function taking a file name.
+func (e *envFileSystem) call(name string, f func(env *C.rocksdb_env_t, cName *C.char, cNameLen C.size_t, cErr **C.char) C.int) error {
+	var (
+		cErr  *C.char
+		cName = C.CString(name)
+	)
+	defer C.free(unsafe.Pointer(cName))
+	return newEnvFileSystemError(f(e.env.c, cName, C.size_t(len(name)), &cErr), cErr)
+}
+
+func (e *envFileSystem) FileExists(name string) (bool, error) {
+	err := e.call(name, func(env *C.rocksdb_env_t, cName *C.char, cNameLen C.size_t, cErr **C.char) C.int {
+		return C.gorocksdb_envfs_file_exists(env, cName, cNameLen, cErr)
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (e *envFileSystem) GetChildren(dir string) ([]string, error) {
+	var (
+		cNames    *C.char
+		cNamesLen C.size_t
+	)
+	err := e.call(dir, func(env *C.rocksdb_env_t, cName *C.char, cNameLen C.size_t, cErr **C.char) C.int {
+		return C.gorocksdb_envfs_get_children(env, cName, cNameLen, &cNames, &cNamesLen, cErr)
+	})
+	defer C.free(unsafe.Pointer(cNames))
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	data := charToByte(cNames, cNamesLen)
+	for start := 0; start < len(data); {
+		end := start
+		for data[end] != 0 {
+			end++
+		}
+		names = append(names, string(data[start:end]))
+		start = end + 1
+	}
+	return names, nil
+}
+
+func (e *envFileSystem) DeleteFile(name string) error {
+	return e.call(name, func(env *C.rocksdb_env_t, cName *C.char, cNameLen C.size_t, cErr **C.char) C.int {
+		return C.gorocksdb_envfs_delete_file(env, cName, cNameLen, cErr)
+	})
+}
+
+func (e *envFileSystem) CreateDir(name string) error {
+	return e.call(name, func(env *C.rocksdb_env_t, cName *C.char, cNameLen C.size_t, cErr **C.char) C.int {
+		return C.gorocksdb_envfs_create_dir(env, cName, cNameLen, cErr)
+	})
+}
+
+func (e *envFileSystem) CreateDirIfMissing(name string) error {
+	return e.call(name, func(env *C.rocksdb_env_t, cName *C.char, cNameLen C.size_t, cErr **C.char) C.int {
+		return C.gorocksdb_envfs_create_dir_if_missing(env, cName, cNameLen, cErr)
+	})
+}
+
+func (e *envFileSystem) DeleteDir(name string) error {
+	return e.call(name, func(env *C.rocksdb_env_t, cName *C.char, cNameLen C.size_t, cErr **C.char) C.int {
+		return C.gorocksdb_envfs_delete_dir(env, cName, cNameLen, cErr)
+	})
+}
+
+func (e *envFileSystem) SyncDir(name string) error {
+	return e.call(name, func(env *C.rocksdb_env_t, cName *C.char, cNameLen C.size_t, cErr **C.char) C.int {
+		return C.gorocksdb_envfs_sync_dir(env, cName, cNameLen, cErr)
+	})
+}
+
+func (e *envFileSystem) GetFileSize(name string) (uint64, error) {
+	var size C.uint64_t
+	err := e.call(name, func(env *C.rocksdb_env_t, cName *C.char, cNameLen C.size_t, cErr **C.char) C.int {
+		return C.gorocksdb_envfs_get_file_size(env, cName, cNameLen, &size, cErr)
+	})
+	return uint64(size), err
+}
+
+func (e *envFileSystem) GetFileModificationTime(name string) (time.Time, error) {
+	var mtime C.uint64_t
+	err := e.call(name, func(env *C.rocksdb_env_t, cName *C.char, cNameLen C.size_t, cErr **C.char) C.int {
+		return C.gorocksdb_envfs_get_file_modification_time(env, cName, cNameLen, &mtime, cErr)
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(int64(mtime), 0), nil
+}
+
+func (e *envFileSystem) RenameFile(src, target string) error {
+	var (
+		cErr    *C.char
+		cSrc    = C.CString(src)
+		cTarget = C.CString(target)
+	)
+	defer C.free(unsafe.Pointer(cSrc))
+	defer C.free(unsafe.Pointer(cTarget))
+	code := C.gorocksdb_envfs_rename_file(e.env.c, cSrc, C.size_t(len(src)), cTarget, C.size_t(len(target)), &cErr)
+	return newEnvFileSystemError(code, cErr)
+}
+
+func (e *envFileSystem) LockFile(name string) (io.Closer, error) {
+	var cLock *C.gorocksdb_envfs_lock_t
+	err := e.call(name, func(env *C.rocksdb_env_t, cName *C.char, cNameLen C.size_t, cErr **C.char) C.int {
+		return C.gorocksdb_envfs_lock_file(env, cName, cNameLen, &cLock, cErr)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &envFileLock{e.env, cLock}, nil
+}
+
+type envFileLock struct {
+	env *Env
+	c   *C.gorocksdb_envfs_lock_t
+}
+
+func (l *envFileLock) Close() error {
+	var cErr *C.char
+	return newEnvFileSystemError(C.gorocksdb_envfs_unlock_file(l.env.c, l.c, &cErr), cErr)
+}
+
+// envFile is a file opened by an envFileSystem.
+type envFile struct {
+	c *C.gorocksdb_envfs_file_t
+}
+
+func (f *envFile) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	var (
+		cErr *C.char
+		read C.size_t
+	)
+	code := C.gorocksdb_envfs_file_read(f.c, byteToChar(p), C.size_t(len(p)), &read, &cErr)
+	if err := newEnvFileSystemError(code, cErr); err != nil {
+		return int(read), err
+	}
+	if read == 0 {
+		return 0, io.EOF
+	}
+	return int(read), nil
+}
+
+func (f *envFile) Skip(n uint64) error {
+	var cErr *C.char
+	return newEnvFileSystemError(C.gorocksdb_envfs_file_skip(f.c, C.uint64_t(n), &cErr), cErr)
+}
+
+func (f *envFile) ReadAt(p []byte, off int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	var (
+		cErr *C.char
+		read C.size_t
+	)
+	code := C.gorocksdb_envfs_file_read_at(f.c, C.uint64_t(off), byteToChar(p), C.size_t(len(p)), &read, &cErr)
+	if err := newEnvFileSystemError(code, cErr); err != nil {
+		return int(read), err
+	}
+	if int(read) < len(p) {
+		return int(read), io.EOF
+	}
+	return int(read), nil
+}
+
+func (f *envFile) Close() error {
+	C.gorocksdb_envfs_file_destroy(f.c)
+	f.c = nil
+	return nil
+}
+
+type envWritableFile struct {
+	envFile
+}
+
+func (f *envWritableFile) Write(p []byte) (int, error) {
+	var cErr *C.char
+	code := C.gorocksdb_envfs_file_append(f.c, byteToChar(p), C.size_t(len(p)), &cErr)
+	if err := newEnvFileSystemError(code, cErr); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (f *envWritableFile) Flush() error {
+	var cErr *C.char
+	return newEnvFileSystemError(C.gorocksdb_envfs_file_flush(f.c, &cErr), cErr)
+}
+
+func (f *envWritableFile) Sync() error {
+	var cErr *C.char
+	return newEnvFileSystemError(C.gorocksdb_envfs_file_sync(f.c, &cErr), cErr)
+}
+
+func (f *envWritableFile) Close() error {
+	var cErr *C.char
+	err := newEnvFileSystemError(C.gorocksdb_envfs_file_close(f.c, &cErr), cErr)
+	f.envFile.Close()
+	return err
+}
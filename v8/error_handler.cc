@@ -0,0 +1,91 @@
+#include "gorocksdb_internal.h"
+#include "rocksdb/listener.h"
+#include "rocksdb/utilities/optimistic_transaction_db.h"
+#include "rocksdb/utilities/transaction_db.h"
+
+extern "C" {
+#include "_cgo_export.h"
+}
+
+using rocksdb::BackgroundErrorReason;
+using rocksdb::BackgroundErrorRecoveryInfo;
+using rocksdb::DB;
+using rocksdb::EventListener;
+using rocksdb::OptimisticTransactionDB;
+using rocksdb::Options;
+using rocksdb::Status;
+using rocksdb::TransactionDB;
+
+namespace {
+
+// GoErrorListener passes the background errors of a DB to a Go
+// errorListener, which decides how they are handled.
+class GoErrorListener : public EventListener {
+ public:
+  explicit GoErrorListener(uintptr_t handle) : handle_(handle) {}
+
+  const char* Name() const override { return "GoErrorListener"; }
+
+  void OnBackgroundError(BackgroundErrorReason reason,
+                         Status* bg_error) override {
+    std::string msg = bg_error->ToString();
+    if (gorocksdb_errorlistener_on_background_error(
+            handle_, static_cast<int>(reason), bg_error->severity(),
+            const_cast<char*>(msg.data()), msg.size(),
+            gorocksdb::IsNoSpace(*bg_error))) {
+      *bg_error = Status::OK();
+    }
+  }
+
+  void OnErrorRecoveryBegin(BackgroundErrorReason reason, Status bg_error,
+                            bool* auto_recovery) override {
+    std::string msg = bg_error.ToString();
+    *auto_recovery = gorocksdb_errorlistener_on_error_recovery_begin(
+        handle_, static_cast<int>(reason), const_cast<char*>(msg.data()),
+        msg.size(), gorocksdb::IsNoSpace(bg_error));
+  }
+
+  void OnErrorRecoveryEnd(const BackgroundErrorRecoveryInfo& info) override {
+    std::string old_msg = info.old_bg_error.ToString();
+    std::string new_msg;
+    if (!info.new_bg_error.ok()) {
+      new_msg = info.new_bg_error.ToString();
+    }
+    gorocksdb_errorlistener_on_error_recovery_end(
+        handle_, const_cast<char*>(old_msg.data()), old_msg.size(),
+        gorocksdb::IsNoSpace(info.old_bg_error),
+        const_cast<char*>(new_msg.data()), new_msg.size(),
+        gorocksdb::IsNoSpace(info.new_bg_error));
+  }
+
+ private:
+  uintptr_t handle_;
+};
+
+}  // namespace
+
+extern "C" {
+
+void gorocksdb_options_add_error_listener(rocksdb_options_t* opts,
+                                          uintptr_t handle) {
+  gorocksdb::RepValue<Options>(opts).listeners.push_back(
+      std::make_shared<GoErrorListener>(handle));
+}
+
+unsigned char gorocksdb_resume(rocksdb_t* db, char** errptr) {
+  return gorocksdb::SaveWriteError(errptr, gorocksdb::Rep<DB>(db)->Resume());
+}
+
+unsigned char gorocksdb_transactiondb_resume(rocksdb_transactiondb_t* db,
+                                             char** errptr) {
+  return gorocksdb::SaveWriteError(errptr,
+                                   gorocksdb::Rep<TransactionDB>(db)->Resume());
+}
+
+unsigned char gorocksdb_optimistictransactiondb_resume(
+    rocksdb_optimistictransactiondb_t* db, char** errptr) {
+  return gorocksdb::SaveWriteError(
+      errptr, gorocksdb::Rep<OptimisticTransactionDB>(db)->Resume());
+}
+
+}  // extern "C"
@@ -0,0 +1,205 @@
+package gorocksdb
+
+// #include "rocksdb/c.h"
+// #include "gorocksdb.h"
+import "C"
+import (
+	"sync"
+	"unsafe"
+)
+
+// BackgroundErrorReason is the operation a background error comes from.
+type BackgroundErrorReason int
+
+const (
+	BackgroundErrorFlush BackgroundErrorReason = iota
+	BackgroundErrorCompaction
+	BackgroundErrorWriteCallback
+	BackgroundErrorMemTable
+	BackgroundErrorManifestWrite
+	BackgroundErrorFlushNoWAL
+	BackgroundErrorManifestWriteNoWAL
+)
+
+// An ErrorHandler is notified of the background errors of a DB, e.g. a
+// flush failing on a full disk, and controls their recovery. A DB stops
+// accepting writes after a serious background error until it recovers,
+// automatically or with DB.Resume.
+//
+// The errors wrap their RocksDB status message; running out of space is
+// reported as a NoSpaceError.
+type ErrorHandler interface {
+	// OnBackgroundError is called when a background operation fails.
+	// Returning true ignores the error, so the DB keeps accepting writes.
+	OnBackgroundError(reason BackgroundErrorReason, err error) bool
+
+	// OnErrorRecoveryBegin is called before the DB starts recovering
+	// automatically from a background error, e.g. once an SstFileManager
+	// sees free space again. Returning false disables the automatic
+	// recovery, leaving it to DB.Resume.
+	OnErrorRecoveryBegin(reason BackgroundErrorReason, err error) bool
+
+	// OnErrorRecoveryEnd is called when a recovery from oldErr ends. err is
+	// nil if the DB recovered.
+	OnErrorRecoveryEnd(oldErr, err error)
+}
+
+// errorListener tracks the background error of a DB and passes the errors
+// to its ErrorHandler.
+type errorListener struct {
+	handle  uintptr
+	mu      sync.Mutex
+	handler ErrorHandler
+	err     error
+}
+
+// Hold references to the error listeners while their DB is open.
+var errorListeners = newRegistry()
+
+func getErrorListener(handle C.uintptr_t) *errorListener {
+	return errorListeners.get(uintptr(handle)).(*errorListener)
+}
+
+// release unregisters the listener once its DB is closed or failed to open.
+func (l *errorListener) release() {
+	if l != nil {
+		errorListeners.release(l.handle)
+	}
+}
+
+// SetErrorHandler sets the handler of the background errors of the DBs
+// opened with the options. A nil handler only tracks them for
+// DB.GetBackgroundError. Each DB tracks its own errors.
+func (opts *Options) SetErrorHandler(handler ErrorHandler) {
+	opts.errorHandler = handler
+	opts.trackErrors = true
+}
+
+// openOptions returns the options to open a DB with. If an ErrorHandler is
+// set, they are a copy of opts with a listener of the background errors of
+// this DB only, which must be released when the DB is closed or fails to
+// open. The returned function frees the options once the DB is open.
+func (opts *Options) openOptions() (*C.rocksdb_options_t, *errorListener, func()) {
+	if !opts.trackErrors {
+		return opts.c, nil, func() {}
+	}
+	l := &errorListener{handler: opts.errorHandler}
+	l.handle = errorListeners.register(l)
+	c := C.rocksdb_options_create_copy(opts.c)
+	C.gorocksdb_options_add_error_listener(c, C.uintptr_t(l.handle))
+	return c, l, func() { C.rocksdb_options_destroy(c) }
+}
+
+// Resume recovers the DB from a background error once its cause is fixed,
+// e.g. after space was freed on the disk, so that it accepts writes again.
+// It does nothing if there is no background error.
+func (db *DB) Resume() error {
+	var cErr *C.char
+	noSpace := C.gorocksdb_resume(db.c, &cErr)
+	return db.errorListener.resumed(cErr, noSpace)
+}
+
+// GetBackgroundError returns the background error the DB has not recovered
+// from yet, or nil. The errors are only tracked if an ErrorHandler, even a
+// nil one, was set on the options the DB was opened with.
+func (db *DB) GetBackgroundError() error {
+	return db.errorListener.getError()
+}
+
+// Resume recovers the DB from a background error, see DB.Resume.
+func (db *TransactionDB) Resume() error {
+	var cErr *C.char
+	noSpace := C.gorocksdb_transactiondb_resume(db.c, &cErr)
+	return db.errorListener.resumed(cErr, noSpace)
+}
+
+// GetBackgroundError returns the background error the DB has not recovered
+// from yet, see DB.GetBackgroundError.
+func (db *TransactionDB) GetBackgroundError() error {
+	return db.errorListener.getError()
+}
+
+// Resume recovers the DB from a background error, see DB.Resume.
+func (db *OptimisticTransactionDB) Resume() error {
+	var cErr *C.char
+	noSpace := C.gorocksdb_optimistictransactiondb_resume(db.c, &cErr)
+	return db.errorListener.resumed(cErr, noSpace)
+}
+
+// GetBackgroundError returns the background error the DB has not recovered
+// from yet, see DB.GetBackgroundError.
+func (db *OptimisticTransactionDB) GetBackgroundError() error {
+	return db.errorListener.getError()
+}
+
+// resumed returns the error of a resume, and clears the tracked background
+// error if the DB recovered.
+func (l *errorListener) resumed(cErr *C.char, noSpace C.uchar) error {
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newWriteError(C.GoString(cErr), noSpace)
+	}
+	if l != nil {
+		l.setError(nil)
+	}
+	return nil
+}
+
+// getError returns the tracked background error, or nil if the errors are
+// not tracked.
+func (l *errorListener) getError() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+func (l *errorListener) setError(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.err = err
+}
+
+// backgroundError converts the message of a background error status.
+func backgroundError(cMsg *C.char, cMsgLen C.size_t, cNoSpace C.uchar) error {
+	if cMsgLen == 0 {
+		return nil
+	}
+	return newWriteError(C.GoStringN(cMsg, C.int(cMsgLen)), cNoSpace)
+}
+
+//export gorocksdb_errorlistener_on_background_error
+func gorocksdb_errorlistener_on_background_error(handle C.uintptr_t, cReason C.int, cSeverity C.int, cMsg *C.char, cMsgLen C.size_t, cNoSpace C.uchar) C.uchar {
+	l := getErrorListener(handle)
+	err := backgroundError(cMsg, cMsgLen, cNoSpace)
+	if handler := l.handler; handler != nil && handler.OnBackgroundError(BackgroundErrorReason(cReason), err) {
+		return boolToChar(true)
+	}
+	// Errors without severity do not stop the DB.
+	if cSeverity > 0 {
+		l.setError(err)
+	}
+	return boolToChar(false)
+}
+
+//export gorocksdb_errorlistener_on_error_recovery_begin
+func gorocksdb_errorlistener_on_error_recovery_begin(handle C.uintptr_t, cReason C.int, cMsg *C.char, cMsgLen C.size_t, cNoSpace C.uchar) C.uchar {
+	handler := getErrorListener(handle).handler
+	if handler == nil {
+		return boolToChar(true)
+	}
+	return boolToChar(handler.OnErrorRecoveryBegin(BackgroundErrorReason(cReason), backgroundError(cMsg, cMsgLen, cNoSpace)))
+}
+
+//export gorocksdb_errorlistener_on_error_recovery_end
+func gorocksdb_errorlistener_on_error_recovery_end(handle C.uintptr_t, cOldMsg *C.char, cOldMsgLen C.size_t, cOldNoSpace C.uchar,
+	cMsg *C.char, cMsgLen C.size_t, cNoSpace C.uchar) {
+	l := getErrorListener(handle)
+	err := backgroundError(cMsg, cMsgLen, cNoSpace)
+	l.setError(err)
+	if handler := l.handler; handler != nil {
+		handler.OnErrorRecoveryEnd(backgroundError(cOldMsg, cOldMsgLen, cOldNoSpace), err)
+	}
+}
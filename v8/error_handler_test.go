@@ -0,0 +1,248 @@
+package gorocksdb
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+// fullFileSystem is an in-memory FileSystem whose table files fail to be
+// written while it is full.
+type fullFileSystem struct {
+	*memFileSystem
+	full int32
+}
+
+func (m *fullFileSystem) setFull(full bool) {
+	var v int32
+	if full {
+		v = 1
+	}
+	atomic.StoreInt32(&m.full, v)
+}
+
+func (m *fullFileSystem) NewWritableFile(name string) (WritableFile, error) {
+	f, err := m.memFileSystem.NewWritableFile(name)
+	if err != nil || !strings.HasSuffix(name, ".sst") {
+		return f, err
+	}
+	return &fullWritableFile{f, m}, nil
+}
+
+type fullWritableFile struct {
+	WritableFile
+	fs *fullFileSystem
+}
+
+func (w *fullWritableFile) Write(p []byte) (int, error) {
+	if atomic.LoadInt32(&w.fs.full) != 0 {
+		return 0, syscall.ENOSPC
+	}
+	return w.WritableFile.Write(p)
+}
+
+// testErrorHandler records the background errors and ignores them if
+// ignore is set.
+type testErrorHandler struct {
+	ignore bool
+
+	mu      sync.Mutex
+	reasons []BackgroundErrorReason
+	errs    []error
+}
+
+func (h *testErrorHandler) OnBackgroundError(reason BackgroundErrorReason, err error) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.reasons = append(h.reasons, reason)
+	h.errs = append(h.errs, err)
+	return h.ignore
+}
+
+func (h *testErrorHandler) OnErrorRecoveryBegin(reason BackgroundErrorReason, err error) bool {
+	return true
+}
+
+func (h *testErrorHandler) OnErrorRecoveryEnd(oldErr, err error) {}
+
+func openFullFileSystemDB(t *testing.T, handler ErrorHandler) (*DB, *fullFileSystem, func()) {
+	dbs, fileSystem, closeDBs := openFullFileSystemDBs(t, handler, 1)
+	return dbs[0], fileSystem, closeDBs
+}
+
+// openFullFileSystemDBs opens n DBs with the same options.
+func openFullFileSystemDBs(t *testing.T, handler ErrorHandler, n int) ([]*DB, *fullFileSystem, func()) {
+	fileSystem := &fullFileSystem{memFileSystem: newMemFileSystem()}
+	env := NewFileSystemEnv(fileSystem)
+	opts := NewDefaultOptions()
+	opts.SetEnv(env)
+	opts.SetCreateIfMissing(true)
+	opts.SetErrorHandler(handler)
+
+	dbs := make([]*DB, n)
+	for i := range dbs {
+		db, err := OpenDb(opts, "/db"+strconv.Itoa(i))
+		ensure.Nil(t, err)
+		dbs[i] = db
+	}
+	return dbs, fileSystem, func() {
+		for _, db := range dbs {
+			db.Close()
+		}
+		opts.Destroy()
+		env.Destroy()
+	}
+}
+
+func TestDBResume(t *testing.T) {
+	handler := &testErrorHandler{}
+	db, fileSystem, closeDB := openFullFileSystemDB(t, handler)
+	defer closeDB()
+
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	fo := NewDefaultFlushOptions()
+	defer fo.Destroy()
+
+	ensure.Nil(t, db.Put(wo, []byte("key1"), []byte("value1")))
+	ensure.Nil(t, db.GetBackgroundError())
+	fileSystem.setFull(true)
+	ensure.NotNil(t, db.Flush(fo))
+
+	handler.mu.Lock()
+	ensure.True(t, len(handler.reasons) > 0)
+	ensure.DeepEqual(t, handler.reasons[0], BackgroundErrorFlush)
+	_, ok := handler.errs[0].(*NoSpaceError)
+	ensure.True(t, ok)
+	handler.mu.Unlock()
+
+	_, ok = db.GetBackgroundError().(*NoSpaceError)
+	ensure.True(t, ok)
+	ensure.NotNil(t, db.Put(wo, []byte("key2"), []byte("value2")))
+
+	fileSystem.setFull(false)
+	ensure.Nil(t, db.Resume())
+	ensure.Nil(t, db.GetBackgroundError())
+	ensure.Nil(t, db.Put(wo, []byte("key2"), []byte("value2")))
+	ensure.Nil(t, db.Flush(fo))
+
+	ro := NewDefaultReadOptions()
+	defer ro.Destroy()
+	for _, key := range []string{"key1", "key2"} {
+		v, err := db.GetBytes(ro, []byte(key))
+		ensure.Nil(t, err)
+		ensure.NotNil(t, v)
+	}
+}
+
+func TestErrorHandlerIgnore(t *testing.T) {
+	handler := &testErrorHandler{ignore: true}
+	db, fileSystem, closeDB := openFullFileSystemDB(t, handler)
+	defer closeDB()
+
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	fo := NewDefaultFlushOptions()
+	defer fo.Destroy()
+
+	ensure.Nil(t, db.Put(wo, []byte("key1"), []byte("value1")))
+	fileSystem.setFull(true)
+	ensure.NotNil(t, db.Flush(fo))
+
+	handler.mu.Lock()
+	ensure.True(t, len(handler.reasons) > 0)
+	handler.mu.Unlock()
+	ensure.Nil(t, db.GetBackgroundError())
+	ensure.Nil(t, db.Put(wo, []byte("key2"), []byte("value2")))
+
+	fileSystem.setFull(false)
+	ensure.Nil(t, db.Flush(fo))
+}
+
+func TestBackgroundErrorPerDB(t *testing.T) {
+	dbs, fileSystem, closeDBs := openFullFileSystemDBs(t, nil, 2)
+	defer closeDBs()
+
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	fo := NewDefaultFlushOptions()
+	defer fo.Destroy()
+
+	for _, db := range dbs {
+		ensure.Nil(t, db.Put(wo, []byte("key1"), []byte("value1")))
+	}
+	fileSystem.setFull(true)
+	ensure.NotNil(t, dbs[0].Flush(fo))
+	fileSystem.setFull(false)
+
+	ensure.NotNil(t, dbs[0].GetBackgroundError())
+	ensure.Nil(t, dbs[1].GetBackgroundError())
+	ensure.Nil(t, dbs[1].Put(wo, []byte("key2"), []byte("value2")))
+	ensure.Nil(t, dbs[1].Flush(fo))
+	ensure.NotNil(t, dbs[0].GetBackgroundError())
+
+	ensure.Nil(t, dbs[0].Resume())
+	ensure.Nil(t, dbs[0].GetBackgroundError())
+}
+
+func TestTransactionDBResume(t *testing.T) {
+	fileSystem := &fullFileSystem{memFileSystem: newMemFileSystem()}
+	env := NewFileSystemEnv(fileSystem)
+	defer env.Destroy()
+	opts := NewDefaultOptions()
+	defer opts.Destroy()
+	opts.SetEnv(env)
+	opts.SetCreateIfMissing(true)
+	opts.SetErrorHandler(nil)
+	tdbOpts := NewDefaultTransactionDBOptions()
+	defer tdbOpts.Destroy()
+	db, err := OpenTransactionDb(opts, tdbOpts, "/db")
+	ensure.Nil(t, err)
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	fo := NewDefaultFlushOptions()
+	defer fo.Destroy()
+
+	ensure.Nil(t, db.Put(wo, []byte("key1"), []byte("value1")))
+	ensure.Nil(t, db.GetBackgroundError())
+	fileSystem.setFull(true)
+	baseDB := db.GetBaseDB()
+	defer CloseBaseDBOfTransactionDB(baseDB)
+	ensure.NotNil(t, baseDB.Flush(fo))
+	_, ok := db.GetBackgroundError().(*NoSpaceError)
+	ensure.True(t, ok)
+
+	fileSystem.setFull(false)
+	ensure.Nil(t, db.Resume())
+	ensure.Nil(t, db.GetBackgroundError())
+	ensure.Nil(t, db.Put(wo, []byte("key2"), []byte("value2")))
+}
+
+func TestErrorListenerRelease(t *testing.T) {
+	count := func() int {
+		errorListeners.mu.RLock()
+		defer errorListeners.mu.RUnlock()
+		return len(errorListeners.items)
+	}
+	before := count()
+
+	_, _, closeDBs := openFullFileSystemDBs(t, nil, 2)
+	ensure.DeepEqual(t, count(), before+2)
+	closeDBs()
+	ensure.DeepEqual(t, count(), before)
+
+	// a failed open does not keep its listener
+	opts := NewDefaultOptions()
+	defer opts.Destroy()
+	opts.SetErrorHandler(nil)
+	_, err := OpenDb(opts, "/nonexistent/gorocksdb-TestErrorListenerRelease")
+	ensure.NotNil(t, err)
+	ensure.DeepEqual(t, count(), before)
+}
@@ -0,0 +1,330 @@
+package gorocksdb
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FaultOp is a kind of file operation a FaultInjectionEnv can fail.
+type FaultOp int
+
+// Faulty operations.
+const (
+	// FaultRead fails the reads of files.
+	FaultRead FaultOp = iota
+	// FaultWrite fails the writes to files.
+	FaultWrite
+	// FaultSync fails the syncs of files.
+	FaultSync
+
+	numFaultOps = int(FaultSync) + 1
+)
+
+// ErrInjectedFault is wrapped by the errors of the operations failed by a
+// FaultInjectionEnv. RocksDB reports them as IO errors containing its message.
+var ErrInjectedFault = errors.New("injected fault")
+
+// FaultInjectionEnv is an Env failing file operations on purpose, to test
+// how an application recovers from IO errors and crashes. It wraps the
+// FileSystem of a base Env, tracks the data written to files but not synced
+// yet and can drop it to simulate a power loss.
+type FaultInjectionEnv struct {
+	*Env
+	fs *faultFileSystem
+}
+
+// NewFaultInjectionEnv creates a FaultInjectionEnv storing the files in the
+// FileSystem of base. base must outlive the FaultInjectionEnv.
+func NewFaultInjectionEnv(base *Env) *FaultInjectionEnv {
+	fs := &faultFileSystem{
+		base:  NewEnvFileSystem(base),
+		rand:  rand.New(rand.NewSource(time.Now().UnixNano())),
+		files: make(map[string]*faultFileState),
+	}
+	return &FaultInjectionEnv{Env: NewFileSystemEnv(fs), fs: fs}
+}
+
+// SetFileFault fails the operations op on the files for which match returns
+// true. A nil match stops failing op on chosen files.
+func (e *FaultInjectionEnv) SetFileFault(op FaultOp, match func(name string) bool) {
+	e.fs.mu.Lock()
+	defer e.fs.mu.Unlock()
+	e.fs.matches[op] = match
+}
+
+// SetFaultProbability fails the operations op on any file with probability
+// p, between 0 and 1.
+func (e *FaultInjectionEnv) SetFaultProbability(op FaultOp, p float64) {
+	e.fs.mu.Lock()
+	defer e.fs.mu.Unlock()
+	e.fs.probabilities[op] = p
+}
+
+// SetSeed seeds the random generator deciding the faults of
+// SetFaultProbability, to make them reproducible.
+func (e *FaultInjectionEnv) SetSeed(seed int64) {
+	e.fs.mu.Lock()
+	defer e.fs.mu.Unlock()
+	e.fs.rand.Seed(seed)
+}
+
+// ClearFaults stops failing operations.
+func (e *FaultInjectionEnv) ClearFaults() {
+	e.fs.mu.Lock()
+	defer e.fs.mu.Unlock()
+	e.fs.matches = [numFaultOps]func(string) bool{}
+	e.fs.probabilities = [numFaultOps]float64{}
+}
+
+// DirtyFiles returns the sorted names of the files with data written but not
+// synced yet.
+func (e *FaultInjectionEnv) DirtyFiles() []string {
+	e.fs.mu.Lock()
+	defer e.fs.mu.Unlock()
+	var names []string
+	for name, state := range e.fs.files {
+		if state.size > state.synced {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DropUnsyncedData truncates the dirty files to the data synced, like a
+// power loss would. It must be called while no DB uses the Env, e.g. after
+// closing them.
+func (e *FaultInjectionEnv) DropUnsyncedData() error {
+	for _, name := range e.DirtyFiles() {
+		if err := e.fs.truncate(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// faultFileState tracks the sizes of a file written through a
+// faultFileSystem.
+type faultFileState struct {
+	size   uint64
+	synced uint64
+}
+
+// faultFileSystem is the FileSystem of a FaultInjectionEnv.
+type faultFileSystem struct {
+	base FileSystem
+
+	mu            sync.Mutex
+	rand          *rand.Rand
+	matches       [numFaultOps]func(name string) bool
+	probabilities [numFaultOps]float64
+	files         map[string]*faultFileState
+}
+
+// fault returns an error if op must fail on the file name.
+func (fs *faultFileSystem) fault(op FaultOp, name string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if match := fs.matches[op]; (match != nil && match(name)) ||
+		(fs.probabilities[op] > 0 && fs.rand.Float64() < fs.probabilities[op]) {
+		return fmt.Errorf("%s: %w", name, ErrInjectedFault)
+	}
+	return nil
+}
+
+// truncate truncates the file name to its synced size.
+func (fs *faultFileSystem) truncate(name string) error {
+	fs.mu.Lock()
+	state, ok := fs.files[name]
+	fs.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	data := make([]byte, state.synced)
+	r, err := fs.base.NewSequentialFile(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.ReadFull(r, data)
+	r.Close()
+	if err != nil {
+		return err
+	}
+	w, err := fs.base.NewWritableFile(name)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Sync(); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	state.size = state.synced
+	return nil
+}
+
+func (fs *faultFileSystem) Name() string { return "gorocksdb.FaultInjectionFileSystem" }
+
+func (fs *faultFileSystem) NewSequentialFile(name string) (SequentialFile, error) {
+	f, err := fs.base.NewSequentialFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return &faultSequentialFile{f, fs, name}, nil
+}
+
+func (fs *faultFileSystem) NewRandomAccessFile(name string) (RandomAccessFile, error) {
+	f, err := fs.base.NewRandomAccessFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return &faultRandomAccessFile{f, fs, name}, nil
+}
+
+func (fs *faultFileSystem) NewWritableFile(name string) (WritableFile, error) {
+	f, err := fs.base.NewWritableFile(name)
+	if err != nil {
+		return nil, err
+	}
+	state := &faultFileState{}
+	fs.mu.Lock()
+	fs.files[name] = state
+	fs.mu.Unlock()
+	return &faultWritableFile{f, fs, name, state}, nil
+}
+
+func (fs *faultFileSystem) FileExists(name string) (bool, error) {
+	return fs.base.FileExists(name)
+}
+
+func (fs *faultFileSystem) GetChildren(dir string) ([]string, error) {
+	return fs.base.GetChildren(dir)
+}
+
+func (fs *faultFileSystem) DeleteFile(name string) error {
+	if err := fs.base.DeleteFile(name); err != nil {
+		return err
+	}
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	delete(fs.files, name)
+	return nil
+}
+
+func (fs *faultFileSystem) CreateDir(name string) error {
+	return fs.base.CreateDir(name)
+}
+
+func (fs *faultFileSystem) CreateDirIfMissing(name string) error {
+	return fs.base.CreateDirIfMissing(name)
+}
+
+func (fs *faultFileSystem) DeleteDir(name string) error {
+	return fs.base.DeleteDir(name)
+}
+
+func (fs *faultFileSystem) SyncDir(name string) error {
+	if err := fs.fault(FaultSync, name); err != nil {
+		return err
+	}
+	return fs.base.SyncDir(name)
+}
+
+func (fs *faultFileSystem) GetFileSize(name string) (uint64, error) {
+	return fs.base.GetFileSize(name)
+}
+
+func (fs *faultFileSystem) GetFileModificationTime(name string) (time.Time, error) {
+	return fs.base.GetFileModificationTime(name)
+}
+
+func (fs *faultFileSystem) RenameFile(src, target string) error {
+	if err := fs.base.RenameFile(src, target); err != nil {
+		return err
+	}
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if state, ok := fs.files[src]; ok {
+		fs.files[target] = state
+		delete(fs.files, src)
+	} else {
+		delete(fs.files, target)
+	}
+	return nil
+}
+
+func (fs *faultFileSystem) LockFile(name string) (io.Closer, error) {
+	return fs.base.LockFile(name)
+}
+
+type faultSequentialFile struct {
+	SequentialFile
+	fs   *faultFileSystem
+	name string
+}
+
+func (f *faultSequentialFile) Read(p []byte) (int, error) {
+	if err := f.fs.fault(FaultRead, f.name); err != nil {
+		return 0, err
+	}
+	return f.SequentialFile.Read(p)
+}
+
+type faultRandomAccessFile struct {
+	RandomAccessFile
+	fs   *faultFileSystem
+	name string
+}
+
+func (f *faultRandomAccessFile) ReadAt(p []byte, off int64) (int, error) {
+	if err := f.fs.fault(FaultRead, f.name); err != nil {
+		return 0, err
+	}
+	return f.RandomAccessFile.ReadAt(p, off)
+}
+
+type faultWritableFile struct {
+	WritableFile
+	fs    *faultFileSystem
+	name  string
+	state *faultFileState
+}
+
+func (f *faultWritableFile) Write(p []byte) (int, error) {
+	if err := f.fs.fault(FaultWrite, f.name); err != nil {
+		return 0, err
+	}
+	n, err := f.WritableFile.Write(p)
+	f.fs.mu.Lock()
+	f.state.size += uint64(n)
+	f.fs.mu.Unlock()
+	return n, err
+}
+
+func (f *faultWritableFile) Sync() error {
+	if err := f.fs.fault(FaultSync, f.name); err != nil {
+		return err
+	}
+	if err := f.WritableFile.Sync(); err != nil {
+		return err
+	}
+	f.fs.mu.Lock()
+	f.state.synced = f.state.size
+	f.fs.mu.Unlock()
+	return nil
+}
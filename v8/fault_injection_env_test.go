@@ -0,0 +1,92 @@
+package gorocksdb
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func isWAL(name string) bool { return strings.HasSuffix(name, ".log") }
+
+func TestFaultInjectionEnv(t *testing.T) {
+	base := NewDefaultEnv()
+	defer base.Destroy()
+	env := NewFaultInjectionEnv(base)
+	defer env.Destroy()
+
+	db := newTestDB(t, "TestFaultInjectionEnv", func(opts *Options) {
+		opts.SetEnv(env.Env)
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	ensure.Nil(t, db.Put(wo, []byte("key1"), []byte("value")))
+	var dirtyWAL bool
+	for _, name := range env.DirtyFiles() {
+		dirtyWAL = dirtyWAL || isWAL(name)
+	}
+	ensure.True(t, dirtyWAL)
+
+	wo.SetSync(true)
+	ensure.Nil(t, db.Put(wo, []byte("key2"), []byte("value")))
+	for _, name := range env.DirtyFiles() {
+		ensure.False(t, isWAL(name))
+	}
+
+	env.SetFileFault(FaultWrite, isWAL)
+	err := db.Put(wo, []byte("key3"), []byte("value"))
+	ensure.NotNil(t, err)
+	ensure.True(t, strings.Contains(err.Error(), ErrInjectedFault.Error()))
+}
+
+func TestFaultInjectionEnvDropUnsyncedData(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorocksdb-TestFaultInjectionEnvDropUnsyncedData")
+	ensure.Nil(t, err)
+	base := NewDefaultEnv()
+	defer base.Destroy()
+	env := NewFaultInjectionEnv(base)
+	defer env.Destroy()
+	fs := env.fs
+	name := filepath.Join(dir, "file")
+
+	w, err := fs.NewWritableFile(name)
+	ensure.Nil(t, err)
+	_, err = w.Write([]byte("synced"))
+	ensure.Nil(t, err)
+	ensure.Nil(t, w.Sync())
+	_, err = w.Write([]byte("-unsynced"))
+	ensure.Nil(t, err)
+	ensure.Nil(t, w.Close())
+	ensure.DeepEqual(t, env.DirtyFiles(), []string{name})
+
+	ensure.Nil(t, env.DropUnsyncedData())
+	ensure.DeepEqual(t, len(env.DirtyFiles()), 0)
+	r, err := fs.NewSequentialFile(name)
+	ensure.Nil(t, err)
+	data, err := io.ReadAll(r)
+	ensure.Nil(t, err)
+	ensure.Nil(t, r.Close())
+	ensure.DeepEqual(t, data, []byte("synced"))
+
+	env.SetFaultProbability(FaultRead, 1)
+	r, err = fs.NewSequentialFile(name)
+	ensure.Nil(t, err)
+	_, err = r.Read(make([]byte, 1))
+	ensure.True(t, errors.Is(err, ErrInjectedFault))
+	ensure.Nil(t, r.Close())
+
+	env.ClearFaults()
+	f, err := fs.NewRandomAccessFile(name)
+	ensure.Nil(t, err)
+	buf := make([]byte, 6)
+	_, err = f.ReadAt(buf, 0)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, buf, []byte("synced"))
+	ensure.Nil(t, f.Close())
+}
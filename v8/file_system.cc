@@ -0,0 +1,403 @@
+#include "gorocksdb_internal.h"
+#include "rocksdb/env.h"
+#include "rocksdb/file_system.h"
+
+extern "C" {
+#include "_cgo_export.h"
+}
+
+using rocksdb::Env;
+using rocksdb::FileLock;
+using rocksdb::FileOptions;
+using rocksdb::FileSystem;
+using rocksdb::FSDirectory;
+using rocksdb::FSRandomAccessFile;
+using rocksdb::FSSequentialFile;
+using rocksdb::FSWritableFile;
+using rocksdb::IODebugContext;
+using rocksdb::IOOptions;
+using rocksdb::IOStatus;
+using rocksdb::Logger;
+using rocksdb::Slice;
+
+namespace {
+
+// The status codes returned by the Go FileSystem callbacks, see fsStatus.
+enum {
+  kGoStatusOK = 0,
+  kGoStatusIOError = 1,
+  kGoStatusNotFound = 2,
+  kGoStatusNoSpace = 3,
+};
+
+// ToIOStatus converts the status code and the malloc'ed error message
+// returned by a Go FileSystem callback, freeing the message.
+IOStatus ToIOStatus(int code, char* msg) {
+  std::string m(msg != nullptr ? msg : "");
+  free(msg);
+  switch (code) {
+    case kGoStatusOK:
+      return IOStatus::OK();
+    case kGoStatusNotFound:
+      return IOStatus::PathNotFound(m);
+    case kGoStatusNoSpace:
+      return IOStatus::NoSpace(m);
+    default:
+      return IOStatus::IOError(m);
+  }
+}
+
+char* CName(const std::string& name) { return const_cast<char*>(name.data()); }
+
+// GoFile releases the handle of a Go file when destroyed.
+class GoFile {
+ public:
+  explicit GoFile(uintptr_t handle) : handle_(handle) {}
+  ~GoFile() { gorocksdb_file_release(handle_); }
+
+ protected:
+  uintptr_t handle_;
+};
+
+class GoSequentialFile : public FSSequentialFile, GoFile {
+ public:
+  using GoFile::GoFile;
+
+  IOStatus Read(size_t n, const IOOptions& /*options*/, Slice* result,
+                char* scratch, IODebugContext* /*dbg*/) override {
+    size_t read = 0;
+    char* msg = nullptr;
+    int code = gorocksdb_file_read(handle_, scratch, n, &read, &msg);
+    *result = Slice(scratch, read);
+    return ToIOStatus(code, msg);
+  }
+
+  IOStatus Skip(uint64_t n) override {
+    char* msg = nullptr;
+    int code = gorocksdb_file_skip(handle_, n, &msg);
+    return ToIOStatus(code, msg);
+  }
+};
+
+class GoRandomAccessFile : public FSRandomAccessFile, GoFile {
+ public:
+  using GoFile::GoFile;
+
+  IOStatus Read(uint64_t offset, size_t n, const IOOptions& /*options*/,
+                Slice* result, char* scratch,
+                IODebugContext* /*dbg*/) const override {
+    size_t read = 0;
+    char* msg = nullptr;
+    int code = gorocksdb_file_read_at(handle_, offset, scratch, n, &read, &msg);
+    *result = Slice(scratch, read);
+    return ToIOStatus(code, msg);
+  }
+};
+
+class GoWritableFile : public FSWritableFile, GoFile {
+ public:
+  using FSWritableFile::Append;
+
+  explicit GoWritableFile(uintptr_t handle) : GoFile(handle), size_(0) {}
+
+  IOStatus Append(const Slice& data, const IOOptions& /*options*/,
+                  IODebugContext* /*dbg*/) override {
+    char* msg = nullptr;
+    int code = gorocksdb_file_append(handle_, const_cast<char*>(data.data()),
+                                     data.size(), &msg);
+    if (code == kGoStatusOK) {
+      size_ += data.size();
+    }
+    return ToIOStatus(code, msg);
+  }
+
+  IOStatus Close(const IOOptions& /*options*/,
+                 IODebugContext* /*dbg*/) override {
+    char* msg = nullptr;
+    int code = gorocksdb_file_close(handle_, &msg);
+    return ToIOStatus(code, msg);
+  }
+
+  IOStatus Flush(const IOOptions& /*options*/,
+                 IODebugContext* /*dbg*/) override {
+    char* msg = nullptr;
+    int code = gorocksdb_file_flush(handle_, &msg);
+    return ToIOStatus(code, msg);
+  }
+
+  IOStatus Sync(const IOOptions& /*options*/,
+                IODebugContext* /*dbg*/) override {
+    char* msg = nullptr;
+    int code = gorocksdb_file_sync(handle_, &msg);
+    return ToIOStatus(code, msg);
+  }
+
+  uint64_t GetFileSize(const IOOptions& /*options*/,
+                       IODebugContext* /*dbg*/) override {
+    return size_;
+  }
+
+ private:
+  uint64_t size_;
+};
+
+class GoDirectory : public FSDirectory {
+ public:
+  GoDirectory(uintptr_t idx, std::string name)
+      : idx_(idx), name_(std::move(name)) {}
+
+  IOStatus Fsync(const IOOptions& /*options*/,
+                 IODebugContext* /*dbg*/) override {
+    char* msg = nullptr;
+    int code =
+        gorocksdb_filesystem_sync_dir(idx_, CName(name_), name_.size(), &msg);
+    return ToIOStatus(code, msg);
+  }
+
+ private:
+  uintptr_t idx_;
+  std::string name_;
+};
+
+class GoFileLock : public FileLock {
+ public:
+  explicit GoFileLock(uintptr_t handle) : handle(handle) {}
+  uintptr_t handle;
+};
+
+// GoFileSystem calls into a Go FileSystem.
+class GoFileSystem : public FileSystem {
+ public:
+  explicit GoFileSystem(uintptr_t idx) : idx_(idx) {
+    char* name = gorocksdb_filesystem_name(idx);
+    name_ = name;
+    free(name);
+  }
+
+  const char* Name() const override { return name_.c_str(); }
+
+  IOStatus NewSequentialFile(const std::string& fname,
+                             const FileOptions& /*file_opts*/,
+                             std::unique_ptr<FSSequentialFile>* result,
+                             IODebugContext* /*dbg*/) override {
+    uintptr_t handle = 0;
+    IOStatus s = NewFile(kSequentialFile, fname, &handle);
+    if (s.ok()) {
+      result->reset(new GoSequentialFile(handle));
+    }
+    return s;
+  }
+
+  IOStatus NewRandomAccessFile(const std::string& fname,
+                               const FileOptions& /*file_opts*/,
+                               std::unique_ptr<FSRandomAccessFile>* result,
+                               IODebugContext* /*dbg*/) override {
+    uintptr_t handle = 0;
+    IOStatus s = NewFile(kRandomAccessFile, fname, &handle);
+    if (s.ok()) {
+      result->reset(new GoRandomAccessFile(handle));
+    }
+    return s;
+  }
+
+  IOStatus NewWritableFile(const std::string& fname,
+                           const FileOptions& /*file_opts*/,
+                           std::unique_ptr<FSWritableFile>* result,
+                           IODebugContext* /*dbg*/) override {
+    uintptr_t handle = 0;
+    IOStatus s = NewFile(kWritableFile, fname, &handle);
+    if (s.ok()) {
+      result->reset(new GoWritableFile(handle));
+    }
+    return s;
+  }
+
+  IOStatus ReuseWritableFile(const std::string& fname,
+                             const std::string& old_fname,
+                             const FileOptions& file_opts,
+                             std::unique_ptr<FSWritableFile>* result,
+                             IODebugContext* dbg) override {
+    IOStatus s = RenameFile(old_fname, fname, file_opts.io_options, dbg);
+    if (!s.ok()) {
+      return s;
+    }
+    return NewWritableFile(fname, file_opts, result, dbg);
+  }
+
+  IOStatus NewDirectory(const std::string& name, const IOOptions& /*io_opts*/,
+                        std::unique_ptr<FSDirectory>* result,
+                        IODebugContext* /*dbg*/) override {
+    result->reset(new GoDirectory(idx_, name));
+    return IOStatus::OK();
+  }
+
+  IOStatus FileExists(const std::string& fname, const IOOptions& /*options*/,
+                      IODebugContext* /*dbg*/) override {
+    char* msg = nullptr;
+    int code = gorocksdb_filesystem_file_exists(idx_, CName(fname),
+                                                fname.size(), &msg);
+    if (code == kGoStatusNotFound) {
+      free(msg);
+      return IOStatus::NotFound();
+    }
+    return ToIOStatus(code, msg);
+  }
+
+  IOStatus GetChildren(const std::string& dir, const IOOptions& /*options*/,
+                       std::vector<std::string>* result,
+                       IODebugContext* /*dbg*/) override {
+    char* names = nullptr;
+    size_t len = 0;
+    char* msg = nullptr;
+    int code = gorocksdb_filesystem_get_children(idx_, CName(dir), dir.size(),
+                                                 &names, &len, &msg);
+    result->clear();
+    // The names are returned separated by NUL characters.
+    for (size_t start = 0; start < len;) {
+      size_t end = start;
+      while (end < len && names[end] != '\0') {
+        end++;
+      }
+      result->emplace_back(names + start, end - start);
+      start = end + 1;
+    }
+    free(names);
+    return ToIOStatus(code, msg);
+  }
+
+  IOStatus DeleteFile(const std::string& fname, const IOOptions& /*options*/,
+                      IODebugContext* /*dbg*/) override {
+    char* msg = nullptr;
+    int code = gorocksdb_filesystem_delete_file(idx_, CName(fname),
+                                                fname.size(), &msg);
+    return ToIOStatus(code, msg);
+  }
+
+  IOStatus CreateDir(const std::string& dirname, const IOOptions& /*options*/,
+                     IODebugContext* /*dbg*/) override {
+    char* msg = nullptr;
+    int code = gorocksdb_filesystem_create_dir(idx_, CName(dirname),
+                                               dirname.size(), &msg);
+    return ToIOStatus(code, msg);
+  }
+
+  IOStatus CreateDirIfMissing(const std::string& dirname,
+                              const IOOptions& /*options*/,
+                              IODebugContext* /*dbg*/) override {
+    char* msg = nullptr;
+    int code = gorocksdb_filesystem_create_dir_if_missing(
+        idx_, CName(dirname), dirname.size(), &msg);
+    return ToIOStatus(code, msg);
+  }
+
+  IOStatus DeleteDir(const std::string& dirname, const IOOptions& /*options*/,
+                     IODebugContext* /*dbg*/) override {
+    char* msg = nullptr;
+    int code = gorocksdb_filesystem_delete_dir(idx_, CName(dirname),
+                                               dirname.size(), &msg);
+    return ToIOStatus(code, msg);
+  }
+
+  IOStatus GetFileSize(const std::string& fname, const IOOptions& /*options*/,
+                       uint64_t* file_size, IODebugContext* /*dbg*/) override {
+    char* msg = nullptr;
+    int code = gorocksdb_filesystem_get_file_size(idx_, CName(fname),
+                                                  fname.size(), file_size, &msg);
+    return ToIOStatus(code, msg);
+  }
+
+  IOStatus GetFileModificationTime(const std::string& fname,
+                                   const IOOptions& /*options*/,
+                                   uint64_t* file_mtime,
+                                   IODebugContext* /*dbg*/) override {
+    char* msg = nullptr;
+    int code = gorocksdb_filesystem_get_file_modification_time(
+        idx_, CName(fname), fname.size(), file_mtime, &msg);
+    return ToIOStatus(code, msg);
+  }
+
+  IOStatus RenameFile(const std::string& src, const std::string& target,
+                      const IOOptions& /*options*/,
+                      IODebugContext* /*dbg*/) override {
+    char* msg = nullptr;
+    int code = gorocksdb_filesystem_rename_file(
+        idx_, CName(src), src.size(), CName(target), target.size(), &msg);
+    return ToIOStatus(code, msg);
+  }
+
+  IOStatus LockFile(const std::string& fname, const IOOptions& /*options*/,
+                    FileLock** lock, IODebugContext* /*dbg*/) override {
+    uintptr_t handle = 0;
+    char* msg = nullptr;
+    int code = gorocksdb_filesystem_lock_file(idx_, CName(fname), fname.size(),
+                                              &handle, &msg);
+    *lock = code == kGoStatusOK ? new GoFileLock(handle) : nullptr;
+    return ToIOStatus(code, msg);
+  }
+
+  IOStatus UnlockFile(FileLock* lock, const IOOptions& /*options*/,
+                      IODebugContext* /*dbg*/) override {
+    char* msg = nullptr;
+    int code =
+        gorocksdb_filesystem_unlock_file(static_cast<GoFileLock*>(lock)->handle,
+                                         &msg);
+    delete lock;
+    return ToIOStatus(code, msg);
+  }
+
+  IOStatus GetTestDirectory(const IOOptions& /*options*/,
+                            std::string* /*path*/,
+                            IODebugContext* /*dbg*/) override {
+    return IOStatus::NotSupported("GetTestDirectory");
+  }
+
+  // The DB runs without an info log, the Go FileSystem does not provide one.
+  IOStatus NewLogger(const std::string& /*fname*/, const IOOptions& /*io_opts*/,
+                     std::shared_ptr<Logger>* /*result*/,
+                     IODebugContext* /*dbg*/) override {
+    return IOStatus::NotSupported("NewLogger");
+  }
+
+  IOStatus GetAbsolutePath(const std::string& db_path,
+                           const IOOptions& /*options*/,
+                           std::string* output_path,
+                           IODebugContext* /*dbg*/) override {
+    *output_path = db_path;
+    return IOStatus::OK();
+  }
+
+  IOStatus IsDirectory(const std::string& /*path*/,
+                       const IOOptions& /*options*/, bool* /*is_dir*/,
+                       IODebugContext* /*dbg*/) override {
+    return IOStatus::NotSupported("IsDirectory");
+  }
+
+ private:
+  // The kinds of files opened by gorocksdb_filesystem_new_file.
+  enum {
+    kSequentialFile = 0,
+    kRandomAccessFile = 1,
+    kWritableFile = 2,
+  };
+
+  IOStatus NewFile(int kind, const std::string& fname, uintptr_t* handle) {
+    char* msg = nullptr;
+    int code = gorocksdb_filesystem_new_file(idx_, kind, CName(fname),
+                                             fname.size(), handle, &msg);
+    return ToIOStatus(code, msg);
+  }
+
+  uintptr_t idx_;
+  std::string name_;
+};
+
+}  // namespace
+
+extern "C" {
+
+rocksdb_env_t* gorocksdb_env_create_from_filesystem(uintptr_t idx) {
+  std::shared_ptr<FileSystem> fs = std::make_shared<GoFileSystem>(idx);
+  return new rocksdb_env_t{rocksdb::NewCompositeEnv(fs).release(), false};
+}
+
+}  // extern "C"
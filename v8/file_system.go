@@ -0,0 +1,344 @@
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+// #include "gorocksdb.h"
+import "C"
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"syscall"
+	"time"
+)
+
+// FileSystem is the storage used by a DB, to be implemented in Go and turned
+// into an Env by NewFileSystemEnv. It is called from RocksDB background
+// threads, so its methods must be safe for concurrent use.
+//
+// Errors matching fs.ErrNotExist are reported to RocksDB as missing files and
+// errors matching syscall.ENOSPC as a full disk; any other error is reported
+// as an IO error.
+type FileSystem interface {
+	// Name returns the name of the FileSystem, for logging.
+	Name() string
+
+	// NewSequentialFile opens an existing file for sequential reading.
+	NewSequentialFile(name string) (SequentialFile, error)
+
+	// NewRandomAccessFile opens an existing file for reading at any offset.
+	NewRandomAccessFile(name string) (RandomAccessFile, error)
+
+	// NewWritableFile creates a new file for appending, truncating any
+	// existing file with the same name.
+	NewWritableFile(name string) (WritableFile, error)
+
+	// FileExists returns true if the file or directory exists.
+	FileExists(name string) (bool, error)
+
+	// GetChildren returns the names of the entries of the directory, without
+	// the directory path.
+	GetChildren(dir string) ([]string, error)
+
+	// DeleteFile deletes a file.
+	DeleteFile(name string) error
+
+	// CreateDir creates a directory, failing if it exists.
+	CreateDir(name string) error
+
+	// CreateDirIfMissing creates a directory if it does not exist.
+	CreateDirIfMissing(name string) error
+
+	// DeleteDir deletes an empty directory.
+	DeleteDir(name string) error
+
+	// SyncDir persists the entries of a directory, e.g. the files created or
+	// renamed in it.
+	SyncDir(name string) error
+
+	// GetFileSize returns the size of a file.
+	GetFileSize(name string) (uint64, error)
+
+	// GetFileModificationTime returns the last modification time of a file.
+	GetFileModificationTime(name string) (time.Time, error)
+
+	// RenameFile renames src to target, replacing target if it exists.
+	RenameFile(src, target string) error
+
+	// LockFile locks a file to prevent other processes from opening the same
+	// DB. The lock is released by closing the returned io.Closer.
+	LockFile(name string) (io.Closer, error)
+}
+
+// SequentialFile is a file read sequentially. Read returns io.EOF at the end
+// of the file.
+type SequentialFile interface {
+	io.ReadCloser
+
+	// Skip skips n bytes, or up to the end of the file.
+	Skip(n uint64) error
+}
+
+// RandomAccessFile is a file read at any offset. It can be read concurrently.
+type RandomAccessFile interface {
+	io.ReaderAt
+	io.Closer
+}
+
+// WritableFile is a file written sequentially.
+type WritableFile interface {
+	io.WriteCloser
+
+	// Flush pushes the buffered data to the storage.
+	Flush() error
+
+	// Sync persists the data written so far.
+	Sync() error
+}
+
+// NewFileSystemEnv creates an Env storing the DB files in fs. The other
+// services of the Env, e.g. threads and clock, are the default ones. The DB
+// runs without an info log, as fs does not provide one.
+func NewFileSystemEnv(fileSystem FileSystem) *Env {
+	idx := fileSystems.Append(fileSystem)
+	return NewNativeEnv(C.gorocksdb_env_create_from_filesystem(C.uintptr_t(idx)))
+}
+
+// Hold references to the FileSystems, and to the files and locks they open
+// until RocksDB releases them.
+var (
+	fileSystems       = NewCOWList()
+	fileSystemHandles = newRegistry()
+)
+
+// The status codes returned to the C++ FileSystem, see file_system.cc.
+const (
+	fsStatusOK       = 0
+	fsStatusIOError  = 1
+	fsStatusNotFound = 2
+	fsStatusNoSpace  = 3
+)
+
+// fsStatus returns the status code of err, storing its message in cErr.
+func fsStatus(err error, cErr **C.char) C.int {
+	if err == nil {
+		return fsStatusOK
+	}
+	*cErr = C.CString(err.Error())
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return fsStatusNotFound
+	case errors.Is(err, syscall.ENOSPC):
+		return fsStatusNoSpace
+	default:
+		return fsStatusIOError
+	}
+}
+
+// openFile is a file opened by a FileSystem. RocksDB closes the writable
+// files, but not the files it reads, which are closed when released.
+type openFile struct {
+	file   io.Closer
+	closed bool
+}
+
+func getFileSystem(idx C.uintptr_t) FileSystem {
+	return fileSystems.Get(int(idx)).(FileSystem)
+}
+
+func getOpenFile(handle C.uintptr_t) *openFile {
+	return fileSystemHandles.get(uintptr(handle)).(*openFile)
+}
+
+//export gorocksdb_filesystem_name
+func gorocksdb_filesystem_name(idx C.uintptr_t) *C.char {
+	return C.CString(getFileSystem(idx).Name())
+}
+
+//export gorocksdb_filesystem_new_file
+func gorocksdb_filesystem_new_file(idx C.uintptr_t, kind C.int, cName *C.char, cNameLen C.size_t, handle *C.uintptr_t, cErr **C.char) C.int {
+	fileSystem := getFileSystem(idx)
+	name := C.GoStringN(cName, C.int(cNameLen))
+	var (
+		file io.Closer
+		err  error
+	)
+	switch kind {
+	case 0:
+		file, err = fileSystem.NewSequentialFile(name)
+	case 1:
+		file, err = fileSystem.NewRandomAccessFile(name)
+	default:
+		file, err = fileSystem.NewWritableFile(name)
+	}
+	if err != nil {
+		return fsStatus(err, cErr)
+	}
+	*handle = C.uintptr_t(fileSystemHandles.register(&openFile{file: file}))
+	return fsStatusOK
+}
+
+//export gorocksdb_filesystem_file_exists
+func gorocksdb_filesystem_file_exists(idx C.uintptr_t, cName *C.char, cNameLen C.size_t, cErr **C.char) C.int {
+	exists, err := getFileSystem(idx).FileExists(C.GoStringN(cName, C.int(cNameLen)))
+	if err == nil && !exists {
+		return fsStatusNotFound
+	}
+	return fsStatus(err, cErr)
+}
+
+//export gorocksdb_filesystem_get_children
+func gorocksdb_filesystem_get_children(idx C.uintptr_t, cDir *C.char, cDirLen C.size_t, cNames **C.char, cNamesLen *C.size_t, cErr **C.char) C.int {
+	names, err := getFileSystem(idx).GetChildren(C.GoStringN(cDir, C.int(cDirLen)))
+	if err != nil {
+		return fsStatus(err, cErr)
+	}
+	var size int
+	for _, name := range names {
+		size += len(name) + 1
+	}
+	buf := (*C.char)(C.malloc(C.size_t(size + 1)))
+	data := charToByte(buf, C.size_t(size))
+	var n int
+	for _, name := range names {
+		n += copy(data[n:], name)
+		data[n] = 0
+		n++
+	}
+	*cNames = buf
+	*cNamesLen = C.size_t(size)
+	return fsStatusOK
+}
+
+//export gorocksdb_filesystem_delete_file
+func gorocksdb_filesystem_delete_file(idx C.uintptr_t, cName *C.char, cNameLen C.size_t, cErr **C.char) C.int {
+	return fsStatus(getFileSystem(idx).DeleteFile(C.GoStringN(cName, C.int(cNameLen))), cErr)
+}
+
+//export gorocksdb_filesystem_create_dir
+func gorocksdb_filesystem_create_dir(idx C.uintptr_t, cName *C.char, cNameLen C.size_t, cErr **C.char) C.int {
+	return fsStatus(getFileSystem(idx).CreateDir(C.GoStringN(cName, C.int(cNameLen))), cErr)
+}
+
+//export gorocksdb_filesystem_create_dir_if_missing
+func gorocksdb_filesystem_create_dir_if_missing(idx C.uintptr_t, cName *C.char, cNameLen C.size_t, cErr **C.char) C.int {
+	return fsStatus(getFileSystem(idx).CreateDirIfMissing(C.GoStringN(cName, C.int(cNameLen))), cErr)
+}
+
+//export gorocksdb_filesystem_delete_dir
+func gorocksdb_filesystem_delete_dir(idx C.uintptr_t, cName *C.char, cNameLen C.size_t, cErr **C.char) C.int {
+	return fsStatus(getFileSystem(idx).DeleteDir(C.GoStringN(cName, C.int(cNameLen))), cErr)
+}
+
+//export gorocksdb_filesystem_sync_dir
+func gorocksdb_filesystem_sync_dir(idx C.uintptr_t, cName *C.char, cNameLen C.size_t, cErr **C.char) C.int {
+	return fsStatus(getFileSystem(idx).SyncDir(C.GoStringN(cName, C.int(cNameLen))), cErr)
+}
+
+//export gorocksdb_filesystem_get_file_size
+func gorocksdb_filesystem_get_file_size(idx C.uintptr_t, cName *C.char, cNameLen C.size_t, cSize *C.uint64_t, cErr **C.char) C.int {
+	size, err := getFileSystem(idx).GetFileSize(C.GoStringN(cName, C.int(cNameLen)))
+	*cSize = C.uint64_t(size)
+	return fsStatus(err, cErr)
+}
+
+//export gorocksdb_filesystem_get_file_modification_time
+func gorocksdb_filesystem_get_file_modification_time(idx C.uintptr_t, cName *C.char, cNameLen C.size_t, cTime *C.uint64_t, cErr **C.char) C.int {
+	mtime, err := getFileSystem(idx).GetFileModificationTime(C.GoStringN(cName, C.int(cNameLen)))
+	if err == nil {
+		*cTime = C.uint64_t(mtime.Unix())
+	}
+	return fsStatus(err, cErr)
+}
+
+//export gorocksdb_filesystem_rename_file
+func gorocksdb_filesystem_rename_file(idx C.uintptr_t, cSrc *C.char, cSrcLen C.size_t, cTarget *C.char, cTargetLen C.size_t, cErr **C.char) C.int {
+	src := C.GoStringN(cSrc, C.int(cSrcLen))
+	target := C.GoStringN(cTarget, C.int(cTargetLen))
+	return fsStatus(getFileSystem(idx).RenameFile(src, target), cErr)
+}
+
+//export gorocksdb_filesystem_lock_file
+func gorocksdb_filesystem_lock_file(idx C.uintptr_t, cName *C.char, cNameLen C.size_t, handle *C.uintptr_t, cErr **C.char) C.int {
+	lock, err := getFileSystem(idx).LockFile(C.GoStringN(cName, C.int(cNameLen)))
+	if err != nil {
+		return fsStatus(err, cErr)
+	}
+	*handle = C.uintptr_t(fileSystemHandles.register(lock))
+	return fsStatusOK
+}
+
+//export gorocksdb_filesystem_unlock_file
+func gorocksdb_filesystem_unlock_file(handle C.uintptr_t, cErr **C.char) C.int {
+	lock := fileSystemHandles.release(uintptr(handle)).(io.Closer)
+	return fsStatus(lock.Close(), cErr)
+}
+
+//export gorocksdb_file_read
+func gorocksdb_file_read(handle C.uintptr_t, cBuf *C.char, n C.size_t, cRead *C.size_t, cErr **C.char) C.int {
+	file := getOpenFile(handle).file.(SequentialFile)
+	buf := charToByte(cBuf, n)
+	var (
+		read int
+		err  error
+	)
+	// RocksDB expects a short read only at the end of the file.
+	for read < len(buf) && err == nil {
+		var m int
+		m, err = file.Read(buf[read:])
+		read += m
+	}
+	*cRead = C.size_t(read)
+	if err == io.EOF {
+		err = nil
+	}
+	return fsStatus(err, cErr)
+}
+
+//export gorocksdb_file_skip
+func gorocksdb_file_skip(handle C.uintptr_t, n C.uint64_t, cErr **C.char) C.int {
+	return fsStatus(getOpenFile(handle).file.(SequentialFile).Skip(uint64(n)), cErr)
+}
+
+//export gorocksdb_file_read_at
+func gorocksdb_file_read_at(handle C.uintptr_t, offset C.uint64_t, cBuf *C.char, n C.size_t, cRead *C.size_t, cErr **C.char) C.int {
+	file := getOpenFile(handle).file.(RandomAccessFile)
+	read, err := file.ReadAt(charToByte(cBuf, n), int64(offset))
+	*cRead = C.size_t(read)
+	if err == io.EOF {
+		err = nil
+	}
+	return fsStatus(err, cErr)
+}
+
+//export gorocksdb_file_append
+func gorocksdb_file_append(handle C.uintptr_t, cData *C.char, n C.size_t, cErr **C.char) C.int {
+	_, err := getOpenFile(handle).file.(WritableFile).Write(charToByte(cData, n))
+	return fsStatus(err, cErr)
+}
+
+//export gorocksdb_file_flush
+func gorocksdb_file_flush(handle C.uintptr_t, cErr **C.char) C.int {
+	return fsStatus(getOpenFile(handle).file.(WritableFile).Flush(), cErr)
+}
+
+//export gorocksdb_file_sync
+func gorocksdb_file_sync(handle C.uintptr_t, cErr **C.char) C.int {
+	return fsStatus(getOpenFile(handle).file.(WritableFile).Sync(), cErr)
+}
+
+//export gorocksdb_file_close
+func gorocksdb_file_close(handle C.uintptr_t, cErr **C.char) C.int {
+	f := getOpenFile(handle)
+	f.closed = true
+	return fsStatus(f.file.Close(), cErr)
+}
+
+//export gorocksdb_file_release
+func gorocksdb_file_release(handle C.uintptr_t) {
+	f := fileSystemHandles.release(uintptr(handle)).(*openFile)
+	if !f.closed {
+		f.file.Close()
+	}
+}
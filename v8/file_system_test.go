@@ -0,0 +1,290 @@
+package gorocksdb
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+)
+
+// memFileSystem is an in-memory FileSystem.
+type memFileSystem struct {
+	mu    sync.Mutex
+	files map[string]*memFile
+	dirs  map[string]bool
+	locks map[string]bool
+}
+
+type memFile struct {
+	mu    sync.RWMutex
+	data  []byte
+	mtime time.Time
+}
+
+func newMemFileSystem() *memFileSystem {
+	return &memFileSystem{
+		files: make(map[string]*memFile),
+		dirs:  map[string]bool{"/": true},
+		locks: make(map[string]bool),
+	}
+}
+
+func notExist(name string) error {
+	return &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+func (m *memFileSystem) Name() string { return "memFileSystem" }
+
+func (m *memFileSystem) file(name string) (*memFile, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.files[name]
+	if !ok {
+		return nil, notExist(name)
+	}
+	return f, nil
+}
+
+func (m *memFileSystem) NewSequentialFile(name string) (SequentialFile, error) {
+	f, err := m.file(name)
+	if err != nil {
+		return nil, err
+	}
+	return &memSequentialFile{f: f}, nil
+}
+
+func (m *memFileSystem) NewRandomAccessFile(name string) (RandomAccessFile, error) {
+	f, err := m.file(name)
+	if err != nil {
+		return nil, err
+	}
+	return &memRandomAccessFile{f}, nil
+}
+
+func (m *memFileSystem) NewWritableFile(name string) (WritableFile, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.dirs[path.Dir(name)] {
+		return nil, notExist(path.Dir(name))
+	}
+	f := &memFile{mtime: time.Now()}
+	m.files[name] = f
+	return &memWritableFile{f}, nil
+}
+
+func (m *memFileSystem) FileExists(name string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.files[name]
+	return ok || m.dirs[name], nil
+}
+
+func (m *memFileSystem) GetChildren(dir string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.dirs[dir] {
+		return nil, notExist(dir)
+	}
+	var names []string
+	for name := range m.files {
+		if path.Dir(name) == dir {
+			names = append(names, path.Base(name))
+		}
+	}
+	for name := range m.dirs {
+		if name != dir && path.Dir(name) == dir {
+			names = append(names, path.Base(name))
+		}
+	}
+	return names, nil
+}
+
+func (m *memFileSystem) DeleteFile(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.files[name]; !ok {
+		return notExist(name)
+	}
+	delete(m.files, name)
+	return nil
+}
+
+func (m *memFileSystem) CreateDir(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.dirs[name] {
+		return fs.ErrExist
+	}
+	m.dirs[name] = true
+	return nil
+}
+
+func (m *memFileSystem) CreateDirIfMissing(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dirs[name] = true
+	return nil
+}
+
+func (m *memFileSystem) DeleteDir(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.dirs, name)
+	return nil
+}
+
+func (m *memFileSystem) SyncDir(name string) error { return nil }
+
+func (m *memFileSystem) GetFileSize(name string) (uint64, error) {
+	f, err := m.file(name)
+	if err != nil {
+		return 0, err
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return uint64(len(f.data)), nil
+}
+
+func (m *memFileSystem) GetFileModificationTime(name string) (time.Time, error) {
+	f, err := m.file(name)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return f.mtime, nil
+}
+
+func (m *memFileSystem) RenameFile(src, target string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.files[src]
+	if !ok {
+		return notExist(src)
+	}
+	delete(m.files, src)
+	m.files[target] = f
+	return nil
+}
+
+func (m *memFileSystem) LockFile(name string) (io.Closer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.locks[name] {
+		return nil, errors.New("lock already held: " + name)
+	}
+	m.locks[name] = true
+	return memLock{m, name}, nil
+}
+
+type memLock struct {
+	m    *memFileSystem
+	name string
+}
+
+func (l memLock) Close() error {
+	l.m.mu.Lock()
+	defer l.m.mu.Unlock()
+	delete(l.m.locks, l.name)
+	return nil
+}
+
+type memSequentialFile struct {
+	f   *memFile
+	pos int
+}
+
+func (s *memSequentialFile) Read(p []byte) (int, error) {
+	s.f.mu.RLock()
+	defer s.f.mu.RUnlock()
+	if s.pos >= len(s.f.data) {
+		return 0, io.EOF
+	}
+	n := copy(p, s.f.data[s.pos:])
+	s.pos += n
+	return n, nil
+}
+
+func (s *memSequentialFile) Skip(n uint64) error {
+	s.pos += int(n)
+	return nil
+}
+
+func (s *memSequentialFile) Close() error { return nil }
+
+type memRandomAccessFile struct {
+	f *memFile
+}
+
+func (r *memRandomAccessFile) ReadAt(p []byte, off int64) (int, error) {
+	r.f.mu.RLock()
+	defer r.f.mu.RUnlock()
+	if off >= int64(len(r.f.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, r.f.data[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (r *memRandomAccessFile) Close() error { return nil }
+
+type memWritableFile struct {
+	f *memFile
+}
+
+func (w *memWritableFile) Write(p []byte) (int, error) {
+	w.f.mu.Lock()
+	defer w.f.mu.Unlock()
+	w.f.data = append(w.f.data, p...)
+	w.f.mtime = time.Now()
+	return len(p), nil
+}
+
+func (w *memWritableFile) Flush() error { return nil }
+func (w *memWritableFile) Sync() error  { return nil }
+func (w *memWritableFile) Close() error { return nil }
+
+func TestFileSystemEnv(t *testing.T) {
+	fileSystem := newMemFileSystem()
+	env := NewFileSystemEnv(fileSystem)
+	defer env.Destroy()
+
+	opts := NewDefaultOptions()
+	defer opts.Destroy()
+	opts.SetEnv(env)
+	opts.SetCreateIfMissing(true)
+
+	db, err := OpenDb(opts, "/db")
+	ensure.Nil(t, err)
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	ensure.Nil(t, db.Put(wo, []byte("key"), []byte("value")))
+	ensure.Nil(t, db.Flush(NewDefaultFlushOptions()))
+	db.Close()
+
+	names, err := fileSystem.GetChildren("/db")
+	ensure.Nil(t, err)
+	var hasCurrent, hasTable bool
+	for _, name := range names {
+		hasCurrent = hasCurrent || name == "CURRENT"
+		hasTable = hasTable || strings.HasSuffix(name, ".sst")
+	}
+	ensure.True(t, hasCurrent)
+	ensure.True(t, hasTable)
+
+	db, err = OpenDb(opts, "/db")
+	ensure.Nil(t, err)
+	defer db.Close()
+	ro := NewDefaultReadOptions()
+	defer ro.Destroy()
+	v, err := db.GetBytes(ro, []byte("key"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v, []byte("value"))
+}
@@ -1,3 +1,5 @@
+#pragma once
+
 #include <stdlib.h>
 #include "rocksdb/c.h"
 
@@ -14,12 +16,303 @@ extern rocksdb_compactionfilter_t* gorocksdb_compactionfilter_create(uintptr_t i
 /* Comparator */
 
 extern rocksdb_comparator_t* gorocksdb_comparator_create(uintptr_t idx);
+extern rocksdb_comparator_t* gorocksdb_comparator_create_reverse_bytewise();
+extern rocksdb_comparator_t* gorocksdb_comparator_create_uint64();
+extern rocksdb_comparator_t* gorocksdb_comparator_create_bytewise_u64ts();
 
 /* Merge Operator */
 
 extern rocksdb_mergeoperator_t* gorocksdb_mergeoperator_create(uintptr_t idx);
 extern void gorocksdb_mergeoperator_delete_value(void* state, const char* v, size_t s);
+extern void gorocksdb_options_set_go_merge_operator(rocksdb_options_t* opts, uintptr_t idx);
+extern rocksdb_mergeoperator_t* gorocksdb_mergeoperator_create_uint64add();
+extern rocksdb_mergeoperator_t* gorocksdb_mergeoperator_create_stringappend(const char* delim, size_t delim_len);
+extern rocksdb_mergeoperator_t* gorocksdb_mergeoperator_create_max();
+extern rocksdb_mergeoperator_t* gorocksdb_mergeoperator_create_min();
+extern rocksdb_mergeoperator_t* gorocksdb_mergeoperator_create_bytesxor();
+extern rocksdb_mergeoperator_t* gorocksdb_mergeoperator_create_sortedset_union(const char* delim, size_t delim_len);
 
 /* Slice Transform */
 
 extern rocksdb_slicetransform_t* gorocksdb_slicetransform_create(uintptr_t idx);
+
+/* TableProperties */
+
+typedef struct gorocksdb_tableproperties_t gorocksdb_tableproperties_t;
+
+enum {
+    gorocksdb_tableproperties_data_size = 0,
+    gorocksdb_tableproperties_index_size,
+    gorocksdb_tableproperties_filter_size,
+    gorocksdb_tableproperties_raw_key_size,
+    gorocksdb_tableproperties_raw_value_size,
+    gorocksdb_tableproperties_num_data_blocks,
+    gorocksdb_tableproperties_num_entries,
+    gorocksdb_tableproperties_num_deletions,
+    gorocksdb_tableproperties_num_merge_operands,
+    gorocksdb_tableproperties_num_range_deletions,
+    gorocksdb_tableproperties_format_version,
+    gorocksdb_tableproperties_fixed_key_len,
+    gorocksdb_tableproperties_column_family_id,
+    gorocksdb_tableproperties_creation_time,
+    gorocksdb_tableproperties_oldest_key_time,
+    gorocksdb_tableproperties_file_creation_time
+};
+
+enum {
+    gorocksdb_tableproperties_column_family_name = 0,
+    gorocksdb_tableproperties_filter_policy_name,
+    gorocksdb_tableproperties_comparator_name,
+    gorocksdb_tableproperties_merge_operator_name,
+    gorocksdb_tableproperties_prefix_extractor_name,
+    gorocksdb_tableproperties_property_collectors_names,
+    gorocksdb_tableproperties_compression_name,
+    gorocksdb_tableproperties_compression_options
+};
+
+extern uint64_t gorocksdb_tableproperties_get_uint64(const gorocksdb_tableproperties_t* props, int property);
+extern const char* gorocksdb_tableproperties_get_string(const gorocksdb_tableproperties_t* props, int property, size_t* len);
+extern size_t gorocksdb_tableproperties_user_count(const gorocksdb_tableproperties_t* props);
+extern const char* gorocksdb_tableproperties_user_key(const gorocksdb_tableproperties_t* props, size_t i, size_t* len);
+extern const char* gorocksdb_tableproperties_user_value(const gorocksdb_tableproperties_t* props, size_t i, size_t* len);
+extern void gorocksdb_tableproperties_destroy(gorocksdb_tableproperties_t* props);
+
+/* SstFileReader */
+
+typedef struct gorocksdb_sstfilereader_t gorocksdb_sstfilereader_t;
+
+extern gorocksdb_sstfilereader_t* gorocksdb_sstfilereader_create(const rocksdb_options_t* options);
+extern void gorocksdb_sstfilereader_open(gorocksdb_sstfilereader_t* reader, const char* path, char** errptr);
+extern rocksdb_iterator_t* gorocksdb_sstfilereader_new_iterator(gorocksdb_sstfilereader_t* reader, const rocksdb_readoptions_t* options);
+extern void gorocksdb_sstfilereader_verify_checksum(gorocksdb_sstfilereader_t* reader, char** errptr);
+extern gorocksdb_tableproperties_t* gorocksdb_sstfilereader_get_table_properties(gorocksdb_sstfilereader_t* reader);
+extern void gorocksdb_sstfilereader_destroy(gorocksdb_sstfilereader_t* reader);
+
+/* SstFileWriter */
+
+extern rocksdb_sstfilewriter_t* gorocksdb_sstfilewriter_create_cf(const rocksdb_envoptions_t* env, const rocksdb_options_t* options, rocksdb_column_family_handle_t* cf);
+
+/* IngestExternalFile */
+
+extern void gorocksdb_ingestexternalfileoptions_set_verify_checksums_before_ingest(rocksdb_ingestexternalfileoptions_t* opt, unsigned char v);
+extern void gorocksdb_ingestexternalfileoptions_set_write_global_seqno(rocksdb_ingestexternalfileoptions_t* opt, unsigned char v);
+extern void gorocksdb_ingestexternalfileoptions_set_verify_file_checksum(rocksdb_ingestexternalfileoptions_t* opt, unsigned char v);
+extern void gorocksdb_ingest_external_files(
+    rocksdb_t* db, size_t num_args, rocksdb_column_family_handle_t* const* cfs,
+    const size_t* num_files, const char* const* files,
+    rocksdb_ingestexternalfileoptions_t* const* options, char** errptr);
+extern int gorocksdb_get_merge_operands(
+    rocksdb_t* db, rocksdb_readoptions_t* options, rocksdb_column_family_handle_t* cf,
+    const char* key, size_t key_len, int max_operands, char** values,
+    size_t* values_len, char** errptr);
+
+/* CompactionFilterFactory */
+
+extern void gorocksdb_options_set_compaction_filter_factory(rocksdb_options_t* opts, uintptr_t idx);
+
+/* CompactionFilterV2 */
+
+typedef struct gorocksdb_compactionfilter_v2_t gorocksdb_compactionfilter_v2_t;
+
+extern gorocksdb_compactionfilter_v2_t* gorocksdb_compactionfilter_v2_create(uintptr_t handle, const char* name);
+extern void gorocksdb_compactionfilter_v2_destroy(gorocksdb_compactionfilter_v2_t* filter);
+extern void gorocksdb_options_set_compaction_filter_v2(rocksdb_options_t* opts, gorocksdb_compactionfilter_v2_t* filter);
+
+/* TablePropertiesCollector */
+
+extern void gorocksdb_options_add_table_properties_collector_factory(rocksdb_options_t* opts, uintptr_t idx);
+extern void gorocksdb_usercollectedproperties_add(void* properties, const char* key, size_t key_len, const char* value, size_t value_len);
+
+/* TablePropertiesCollection */
+
+typedef struct gorocksdb_tablepropertiescollection_t gorocksdb_tablepropertiescollection_t;
+
+extern gorocksdb_tablepropertiescollection_t* gorocksdb_get_properties_of_all_tables(
+    rocksdb_t* db, rocksdb_column_family_handle_t* cf, char** errptr);
+extern gorocksdb_tablepropertiescollection_t* gorocksdb_get_properties_of_tables_in_range(
+    rocksdb_t* db, rocksdb_column_family_handle_t* cf, size_t num_ranges,
+    const char* const* start_keys, const size_t* start_keys_len,
+    const char* const* limit_keys, const size_t* limit_keys_len, char** errptr);
+extern size_t gorocksdb_tablepropertiescollection_count(const gorocksdb_tablepropertiescollection_t* c);
+extern const char* gorocksdb_tablepropertiescollection_file(const gorocksdb_tablepropertiescollection_t* c, size_t i, size_t* len);
+extern gorocksdb_tableproperties_t* gorocksdb_tablepropertiescollection_properties(const gorocksdb_tablepropertiescollection_t* c, size_t i);
+extern void gorocksdb_tablepropertiescollection_destroy(gorocksdb_tablepropertiescollection_t* c);
+
+/* SstPartitionerFactory */
+
+extern void gorocksdb_options_set_sst_partitioner_factory(rocksdb_options_t* opts, uintptr_t idx);
+extern void gorocksdb_options_set_sst_partitioner_fixed_prefix_factory(rocksdb_options_t* opts, size_t prefix_len);
+
+/* RateLimiter */
+
+extern rocksdb_ratelimiter_t* gorocksdb_ratelimiter_create_with_mode(
+    int64_t rate_bytes_per_sec, int64_t refill_period_us, int32_t fairness,
+    int mode, unsigned char auto_tuned);
+extern void gorocksdb_ratelimiter_set_bytes_per_second(rocksdb_ratelimiter_t* limiter, int64_t bytes_per_second);
+extern int64_t gorocksdb_ratelimiter_get_bytes_per_second(rocksdb_ratelimiter_t* limiter);
+extern int64_t gorocksdb_ratelimiter_get_single_burst_bytes(rocksdb_ratelimiter_t* limiter);
+extern int64_t gorocksdb_ratelimiter_get_total_bytes_through(rocksdb_ratelimiter_t* limiter, int priority);
+extern int64_t gorocksdb_ratelimiter_get_total_requests(rocksdb_ratelimiter_t* limiter, int priority);
+
+/* Cache */
+
+typedef struct gorocksdb_secondary_cache_t gorocksdb_secondary_cache_t;
+
+extern rocksdb_cache_t* gorocksdb_cache_create_lru(
+    size_t capacity, int num_shard_bits, unsigned char strict_capacity_limit,
+    double high_pri_pool_ratio, double low_pri_pool_ratio,
+    gorocksdb_secondary_cache_t* secondary_cache);
+extern void gorocksdb_cache_erase_unref_entries(rocksdb_cache_t* cache);
+extern void gorocksdb_cache_get_usage_by_role(rocksdb_cache_t* cache, uint64_t* usage, size_t num_roles);
+extern gorocksdb_secondary_cache_t* gorocksdb_secondary_cache_create_compressed(
+    size_t capacity, int num_shard_bits, int compression_type);
+extern void gorocksdb_secondary_cache_destroy(gorocksdb_secondary_cache_t* cache);
+
+/* SstFileManager */
+
+typedef struct gorocksdb_sstfilemanager_t gorocksdb_sstfilemanager_t;
+
+extern gorocksdb_sstfilemanager_t* gorocksdb_sstfilemanager_create(
+    rocksdb_env_t* env, int64_t delete_rate_bytes_per_sec,
+    unsigned char delete_existing_trash, double max_trash_db_ratio,
+    uint64_t bytes_max_delete_chunk, char** errptr);
+extern void gorocksdb_sstfilemanager_destroy(gorocksdb_sstfilemanager_t* sfm);
+extern void gorocksdb_options_set_sst_file_manager(rocksdb_options_t* opts, gorocksdb_sstfilemanager_t* sfm);
+extern void gorocksdb_sstfilemanager_set_max_allowed_space_usage(gorocksdb_sstfilemanager_t* sfm, uint64_t max_allowed_space);
+extern void gorocksdb_sstfilemanager_set_compaction_buffer_size(gorocksdb_sstfilemanager_t* sfm, uint64_t compaction_buffer_size);
+extern unsigned char gorocksdb_sstfilemanager_is_max_allowed_space_reached(gorocksdb_sstfilemanager_t* sfm);
+extern unsigned char gorocksdb_sstfilemanager_is_max_allowed_space_reached_including_compactions(gorocksdb_sstfilemanager_t* sfm);
+extern uint64_t gorocksdb_sstfilemanager_get_total_size(gorocksdb_sstfilemanager_t* sfm);
+extern int64_t gorocksdb_sstfilemanager_get_delete_rate_bytes_per_second(gorocksdb_sstfilemanager_t* sfm);
+extern void gorocksdb_sstfilemanager_set_delete_rate_bytes_per_second(gorocksdb_sstfilemanager_t* sfm, int64_t delete_rate);
+extern double gorocksdb_sstfilemanager_get_max_trash_db_ratio(gorocksdb_sstfilemanager_t* sfm);
+extern void gorocksdb_sstfilemanager_set_max_trash_db_ratio(gorocksdb_sstfilemanager_t* sfm, double ratio);
+extern uint64_t gorocksdb_sstfilemanager_get_total_trash_size(gorocksdb_sstfilemanager_t* sfm);
+
+/* FileSystem */
+
+extern rocksdb_env_t* gorocksdb_env_create_from_filesystem(uintptr_t idx);
+
+/* Env FileSystem */
+
+typedef struct gorocksdb_envfs_file_t gorocksdb_envfs_file_t;
+typedef struct gorocksdb_envfs_lock_t gorocksdb_envfs_lock_t;
+
+extern int gorocksdb_envfs_new_file(rocksdb_env_t* env, int kind, const char* name, size_t name_len, gorocksdb_envfs_file_t** file, char** errptr);
+extern int gorocksdb_envfs_file_exists(rocksdb_env_t* env, const char* name, size_t name_len, char** errptr);
+extern int gorocksdb_envfs_get_children(rocksdb_env_t* env, const char* dir, size_t dir_len, char** names, size_t* names_len, char** errptr);
+extern int gorocksdb_envfs_delete_file(rocksdb_env_t* env, const char* name, size_t name_len, char** errptr);
+extern int gorocksdb_envfs_create_dir(rocksdb_env_t* env, const char* name, size_t name_len, char** errptr);
+extern int gorocksdb_envfs_create_dir_if_missing(rocksdb_env_t* env, const char* name, size_t name_len, char** errptr);
+extern int gorocksdb_envfs_delete_dir(rocksdb_env_t* env, const char* name, size_t name_len, char** errptr);
+extern int gorocksdb_envfs_sync_dir(rocksdb_env_t* env, const char* name, size_t name_len, char** errptr);
+extern int gorocksdb_envfs_get_file_size(rocksdb_env_t* env, const char* name, size_t name_len, uint64_t* size, char** errptr);
+extern int gorocksdb_envfs_get_file_modification_time(rocksdb_env_t* env, const char* name, size_t name_len, uint64_t* mtime, char** errptr);
+extern int gorocksdb_envfs_rename_file(rocksdb_env_t* env, const char* src, size_t src_len, const char* target, size_t target_len, char** errptr);
+extern int gorocksdb_envfs_lock_file(rocksdb_env_t* env, const char* name, size_t name_len, gorocksdb_envfs_lock_t** lock, char** errptr);
+extern int gorocksdb_envfs_unlock_file(rocksdb_env_t* env, gorocksdb_envfs_lock_t* lock, char** errptr);
+extern int gorocksdb_envfs_file_read(gorocksdb_envfs_file_t* file, char* buf, size_t n, size_t* read, char** errptr);
+extern int gorocksdb_envfs_file_skip(gorocksdb_envfs_file_t* file, uint64_t n, char** errptr);
+extern int gorocksdb_envfs_file_read_at(gorocksdb_envfs_file_t* file, uint64_t offset, char* buf, size_t n, size_t* read, char** errptr);
+extern int gorocksdb_envfs_file_append(gorocksdb_envfs_file_t* file, const char* data, size_t n, char** errptr);
+extern int gorocksdb_envfs_file_flush(gorocksdb_envfs_file_t* file, char** errptr);
+extern int gorocksdb_envfs_file_sync(gorocksdb_envfs_file_t* file, char** errptr);
+extern int gorocksdb_envfs_file_close(gorocksdb_envfs_file_t* file, char** errptr);
+extern void gorocksdb_envfs_file_destroy(gorocksdb_envfs_file_t* file);
+
+/* PerfLevel and IOStatsContext */
+
+typedef struct gorocksdb_iostats_t {
+  uint64_t thread_pool_id;
+  uint64_t bytes_written;
+  uint64_t bytes_read;
+  uint64_t open_nanos;
+  uint64_t allocate_nanos;
+  uint64_t write_nanos;
+  uint64_t read_nanos;
+  uint64_t range_sync_nanos;
+  uint64_t fsync_nanos;
+  uint64_t prepare_write_nanos;
+  uint64_t logger_nanos;
+  uint64_t cpu_write_nanos;
+  uint64_t cpu_read_nanos;
+} gorocksdb_iostats_t;
+
+extern int gorocksdb_get_perf_level(void);
+extern void gorocksdb_iostats_context_get(gorocksdb_iostats_t* stats);
+extern void gorocksdb_iostats_context_reset(void);
+extern char* gorocksdb_iostats_context_report(unsigned char exclude_zero_counters);
+
+/* Tracing and replay */
+
+typedef struct gorocksdb_replayer_t gorocksdb_replayer_t;
+typedef struct gorocksdb_trace_record_t gorocksdb_trace_record_t;
+typedef struct gorocksdb_trace_result_t gorocksdb_trace_result_t;
+
+extern void gorocksdb_start_trace(rocksdb_t* db, uint64_t max_trace_file_size, uint64_t sampling_frequency, uint64_t filter, unsigned char preserve_write_order, unsigned char block_cache, const char* path, char** errptr);
+extern void gorocksdb_end_trace(rocksdb_t* db, unsigned char block_cache, char** errptr);
+extern gorocksdb_replayer_t* gorocksdb_replayer_create(rocksdb_t* db, rocksdb_column_family_handle_t** cfs, size_t num_cfs, const char* path, char** errptr);
+extern void gorocksdb_replayer_prepare(gorocksdb_replayer_t* replayer, char** errptr);
+extern uint64_t gorocksdb_replayer_header_timestamp(gorocksdb_replayer_t* replayer);
+extern gorocksdb_trace_record_t* gorocksdb_replayer_next(gorocksdb_replayer_t* replayer, char** errptr);
+extern gorocksdb_trace_result_t* gorocksdb_replayer_execute(gorocksdb_replayer_t* replayer, gorocksdb_trace_record_t* record, char** errptr);
+extern void gorocksdb_replayer_destroy(gorocksdb_replayer_t* replayer);
+extern int gorocksdb_trace_type_write();
+extern int gorocksdb_trace_type_get();
+extern int gorocksdb_trace_type_iterator_seek();
+extern int gorocksdb_trace_type_iterator_seek_for_prev();
+extern int gorocksdb_trace_type_multiget();
+extern int gorocksdb_trace_record_type(gorocksdb_trace_record_t* record);
+extern uint64_t gorocksdb_trace_record_timestamp(gorocksdb_trace_record_t* record);
+extern size_t gorocksdb_trace_record_num_keys(gorocksdb_trace_record_t* record);
+extern const char* gorocksdb_trace_record_key(gorocksdb_trace_record_t* record, size_t i, size_t* len);
+extern void gorocksdb_trace_record_destroy(gorocksdb_trace_record_t* record);
+extern size_t gorocksdb_trace_result_count(gorocksdb_trace_result_t* result);
+extern int gorocksdb_trace_result_get(gorocksdb_trace_result_t* result, size_t i, const char** key, size_t* key_len, const char** value, size_t* value_len, char** errptr);
+extern void gorocksdb_trace_result_destroy(gorocksdb_trace_result_t* result);
+
+/* CompactRangeOptions and CompactFiles */
+
+extern void gorocksdb_compactoptions_set_target_path_id(rocksdb_compactoptions_t* opt, uint32_t v);
+extern uint32_t gorocksdb_compactoptions_get_target_path_id(rocksdb_compactoptions_t* opt);
+extern void gorocksdb_compactoptions_set_allow_write_stall(rocksdb_compactoptions_t* opt, unsigned char v);
+extern unsigned char gorocksdb_compactoptions_get_allow_write_stall(rocksdb_compactoptions_t* opt);
+extern void gorocksdb_compactoptions_set_max_subcompactions(rocksdb_compactoptions_t* opt, uint32_t v);
+extern uint32_t gorocksdb_compactoptions_get_max_subcompactions(rocksdb_compactoptions_t* opt);
+extern void gorocksdb_compact_files(rocksdb_t* db, rocksdb_column_family_handle_t* cf, const char* const* files, size_t num_files, int output_level, char** errptr);
+
+/* CompactionService */
+
+extern void gorocksdb_options_set_compaction_service(rocksdb_options_t* opts, uintptr_t idx);
+extern char* gorocksdb_open_and_compact(rocksdb_options_t* opts, const char* name, const char* output_directory, const char* input, size_t input_len, size_t* output_len, char** errptr);
+
+/* ErrorHandler and Resume */
+
+extern void gorocksdb_options_add_error_listener(rocksdb_options_t* opts, uintptr_t handle);
+extern unsigned char gorocksdb_resume(rocksdb_t* db, char** errptr);
+extern unsigned char gorocksdb_transactiondb_resume(rocksdb_transactiondb_t* db, char** errptr);
+extern unsigned char gorocksdb_optimistictransactiondb_resume(rocksdb_optimistictransactiondb_t* db, char** errptr);
+
+/* Writes */
+
+extern unsigned char gorocksdb_put(rocksdb_t* db, rocksdb_writeoptions_t* options, const char* key, size_t keylen, const char* val, size_t vallen, char** errptr);
+extern unsigned char gorocksdb_put_with_ts(rocksdb_t* db, rocksdb_writeoptions_t* options, const char* key, size_t keylen, const char* ts, size_t tslen, const char* val, size_t vallen, char** errptr);
+extern unsigned char gorocksdb_put_cf(rocksdb_t* db, rocksdb_writeoptions_t* options, rocksdb_column_family_handle_t* cf, const char* key, size_t keylen, const char* val, size_t vallen, char** errptr);
+extern unsigned char gorocksdb_delete(rocksdb_t* db, rocksdb_writeoptions_t* options, const char* key, size_t keylen, char** errptr);
+extern unsigned char gorocksdb_delete_cf(rocksdb_t* db, rocksdb_writeoptions_t* options, rocksdb_column_family_handle_t* cf, const char* key, size_t keylen, char** errptr);
+extern unsigned char gorocksdb_merge(rocksdb_t* db, rocksdb_writeoptions_t* options, const char* key, size_t keylen, const char* val, size_t vallen, char** errptr);
+extern unsigned char gorocksdb_merge_cf(rocksdb_t* db, rocksdb_writeoptions_t* options, rocksdb_column_family_handle_t* cf, const char* key, size_t keylen, const char* val, size_t vallen, char** errptr);
+extern unsigned char gorocksdb_write(rocksdb_t* db, rocksdb_writeoptions_t* options, rocksdb_writebatch_t* batch, char** errptr);
+extern unsigned char gorocksdb_flush(rocksdb_t* db, rocksdb_flushoptions_t* options, char** errptr);
+extern unsigned char gorocksdb_flush_cf(rocksdb_t* db, rocksdb_flushoptions_t* options, rocksdb_column_family_handle_t* cf, char** errptr);
+extern unsigned char gorocksdb_transactiondb_put(rocksdb_transactiondb_t* db, rocksdb_writeoptions_t* options, const char* key, size_t keylen, const char* val, size_t vallen, char** errptr);
+extern unsigned char gorocksdb_transactiondb_put_cf(rocksdb_transactiondb_t* db, rocksdb_writeoptions_t* options, rocksdb_column_family_handle_t* cf, const char* key, size_t keylen, const char* val, size_t vallen, char** errptr);
+extern unsigned char gorocksdb_transactiondb_write(rocksdb_transactiondb_t* db, rocksdb_writeoptions_t* options, rocksdb_writebatch_t* batch, char** errptr);
+extern unsigned char gorocksdb_transactiondb_delete(rocksdb_transactiondb_t* db, rocksdb_writeoptions_t* options, const char* key, size_t keylen, char** errptr);
+extern unsigned char gorocksdb_transactiondb_delete_cf(rocksdb_transactiondb_t* db, rocksdb_writeoptions_t* options, rocksdb_column_family_handle_t* cf, const char* key, size_t keylen, char** errptr);
+extern unsigned char gorocksdb_optimistictransactiondb_write(rocksdb_optimistictransactiondb_t* db, rocksdb_writeoptions_t* options, rocksdb_writebatch_t* batch, char** errptr);
+extern unsigned char gorocksdb_transaction_commit(rocksdb_transaction_t* txn, char** errptr);
+extern unsigned char gorocksdb_transaction_put(rocksdb_transaction_t* txn, const char* key, size_t keylen, const char* val, size_t vallen, char** errptr);
+extern unsigned char gorocksdb_transaction_put_cf(rocksdb_transaction_t* txn, rocksdb_column_family_handle_t* cf, const char* key, size_t keylen, const char* val, size_t vallen, char** errptr);
+extern unsigned char gorocksdb_transaction_delete(rocksdb_transaction_t* txn, const char* key, size_t keylen, char** errptr);
+extern unsigned char gorocksdb_transaction_delete_cf(rocksdb_transaction_t* txn, rocksdb_column_family_handle_t* cf, const char* key, size_t keylen, char** errptr);
@@ -0,0 +1,102 @@
+#pragma once
+
+// Helpers shared by the C++ parts of gorocksdb. They are only needed where the
+// RocksDB C API does not expose a feature yet.
+
+#include <stdlib.h>
+#include <string.h>
+
+#include <memory>
+#include <string>
+
+#include "rocksdb/advanced_cache.h"
+#include "rocksdb/cache.h"
+#include "rocksdb/db.h"
+#include "rocksdb/env.h"
+#include "rocksdb/iterator.h"
+#include "rocksdb/options.h"
+#include "rocksdb/rate_limiter.h"
+#include "rocksdb/status.h"
+#include "rocksdb/table_properties.h"
+
+extern "C" {
+#include "gorocksdb.h"
+}
+
+// The structs of the RocksDB C API are opaque, but all of them keep the
+// wrapped C++ object in their first member. The structs we need to allocate
+// are redeclared exactly as in db/c.cc.
+struct rocksdb_iterator_t {
+  rocksdb::Iterator* rep;
+};
+struct rocksdb_ratelimiter_t {
+  std::shared_ptr<rocksdb::RateLimiter> rep;
+};
+struct rocksdb_cache_t {
+  std::shared_ptr<rocksdb::Cache> rep;
+};
+struct rocksdb_env_t {
+  rocksdb::Env* rep;
+  bool is_default;
+};
+
+namespace gorocksdb {
+
+// Rep returns the C++ object of a C API struct holding it by pointer,
+// e.g. rocksdb_t or rocksdb_column_family_handle_t.
+template <typename T, typename C>
+inline T* Rep(C* c) {
+  return *reinterpret_cast<T**>(c);
+}
+
+// RepValue returns the C++ object of a C API struct holding it by value,
+// e.g. rocksdb_options_t or rocksdb_readoptions_t.
+template <typename T, typename C>
+inline T& RepValue(C* c) {
+  return *reinterpret_cast<T*>(c);
+}
+
+// CopyString returns a malloc'ed copy of s, to be freed with rocksdb_free.
+inline char* CopyString(const std::string& s) {
+  char* result = static_cast<char*>(malloc(s.size() + 1));
+  memcpy(result, s.data(), s.size());
+  result[s.size()] = '\0';
+  return result;
+}
+
+// SaveError stores the message of a failed status into errptr the same way
+// the RocksDB C API does.
+inline bool SaveError(char** errptr, const rocksdb::Status& s) {
+  if (s.ok()) {
+    return false;
+  }
+  if (*errptr != nullptr) {
+    free(*errptr);
+  }
+  *errptr = CopyString(s.ToString());
+  return true;
+}
+
+// IsNoSpace returns whether s failed because of the lack of space, on the
+// disk or allowed by an SstFileManager.
+inline unsigned char IsNoSpace(const rocksdb::Status& s) {
+  return s.IsNoSpace() || s.subcode() == rocksdb::Status::kSpaceLimit;
+}
+
+// SaveWriteError is SaveError for the writes. It returns whether s failed
+// because of the lack of space.
+inline unsigned char SaveWriteError(char** errptr, const rocksdb::Status& s) {
+  SaveError(errptr, s);
+  return IsNoSpace(s);
+}
+
+// NewTableProperties wraps props for the gorocksdb_tableproperties_* functions.
+gorocksdb_tableproperties_t* NewTableProperties(
+    std::shared_ptr<const rocksdb::TableProperties> props);
+
+// NewTablePropertiesCollection wraps collection for the
+// gorocksdb_tablepropertiescollection_* functions.
+gorocksdb_tablepropertiescollection_t* NewTablePropertiesCollection(
+    const rocksdb::TablePropertiesCollection& collection);
+
+}  // namespace gorocksdb
@@ -0,0 +1,280 @@
+package gorocksdb
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OpType is the type of a database operation seen by a Hook.
+type OpType int
+
+const (
+	// OpGet is a Get, GetCF, GetBytes or GetPinned call.
+	OpGet OpType = iota
+	// OpMultiGet is a MultiGet, MultiGetCF or MultiGetCFMultiCF call.
+	OpMultiGet
+	// OpPut is a Put or PutCF call.
+	OpPut
+	// OpDelete is a Delete or DeleteCF call.
+	OpDelete
+	// OpMerge is a Merge or MergeCF call.
+	OpMerge
+	// OpWrite is a Write of a WriteBatch.
+	OpWrite
+	// OpIteratorSeek is a Seek, SeekForPrev, SeekToFirst or SeekToLast
+	// call on an Iterator.
+	OpIteratorSeek
+	// OpIteratorNext is a Next call on an Iterator.
+	OpIteratorNext
+	// OpIteratorPrev is a Prev call on an Iterator.
+	OpIteratorPrev
+	// OpGetForUpdate is a GetForUpdate, GetForUpdateCF or
+	// GetPinnedForUpdateCF call on a Transaction.
+	OpGetForUpdate
+	// OpCommit is a Commit of a Transaction.
+	OpCommit
+)
+
+var opTypeNames = [...]string{
+	OpGet:          "Get",
+	OpMultiGet:     "MultiGet",
+	OpPut:          "Put",
+	OpDelete:       "Delete",
+	OpMerge:        "Merge",
+	OpWrite:        "Write",
+	OpIteratorSeek: "IteratorSeek",
+	OpIteratorNext: "IteratorNext",
+	OpIteratorPrev: "IteratorPrev",
+	OpGetForUpdate: "GetForUpdate",
+	OpCommit:       "Commit",
+}
+
+// String returns the name of the operation type.
+func (t OpType) String() string {
+	if t < 0 || int(t) >= len(opTypeNames) {
+		return "Unknown"
+	}
+	return opTypeNames[t]
+}
+
+// OpInfo describes a database operation.
+type OpInfo struct {
+	Type OpType
+	// ColumnFamily is the column family of the operation, or nil for the
+	// default column family and for operations spanning several of them.
+	ColumnFamily *ColumnFamilyHandle
+	// KeySize is the total size of the keys passed to the operation.
+	KeySize int
+	// ValueSize is the total size of the values passed to the operation.
+	// For a Write it is the size of the serialized WriteBatch, KeySize
+	// being 0.
+	ValueSize int
+	// Count is the number of keys of a MultiGet or the number of updates
+	// of a Write, 0 for a Commit and 1 for the other operations.
+	Count int
+}
+
+// OpResult is the outcome of a database operation.
+type OpResult struct {
+	Duration time.Duration
+	Err      error
+	// Perf holds the perf context counters accumulated by the operation.
+	// It is nil unless a perf level was set with SetHookPerfLevel.
+	Perf *PerfContextSnapshot
+}
+
+// Hook instruments the operations of a DB or TransactionDB, including
+// those of its transactions.
+//
+// Hooks run synchronously on the goroutine calling the operation, so they
+// should be cheap; Before and After may be called concurrently by
+// concurrent operations.
+type Hook interface {
+	// Before is called before the operation runs. The returned value,
+	// e.g. a trace span, is passed to After.
+	Before(op *OpInfo) interface{}
+
+	// After is called once the operation has completed.
+	After(op *OpInfo, state interface{}, result *OpResult)
+}
+
+// AfterHook is a Hook calling the function after each operation, e.g. to
+// record latency histograms.
+type AfterHook func(op *OpInfo, result *OpResult)
+
+// Before implements Hook.
+func (h AfterHook) Before(op *OpInfo) interface{} {
+	return nil
+}
+
+// After implements Hook.
+func (h AfterHook) After(op *OpInfo, state interface{}, result *OpResult) {
+	h(op, result)
+}
+
+// AddHook registers a hook run around each operation of the database.
+// Iterators only run the hooks registered before their creation.
+func (db *DB) AddHook(hook Hook) {
+	db.hooks.add(hook)
+}
+
+// ClearHooks unregisters all the hooks of the database.
+func (db *DB) ClearHooks() {
+	db.hooks.clear()
+}
+
+// SetHookPerfLevel sets the perf level at which the hooked operations
+// collect the perf context counters reported in OpResult.Perf. Collecting
+// them locks the goroutine to its OS thread for the duration of each
+// operation. The default level, KDisable, disables the collection.
+func (db *DB) SetHookPerfLevel(level PerfLevel) {
+	db.hooks.setPerfLevel(level)
+}
+
+// AddHook registers a hook run around each operation of the database and
+// of its transactions. Iterators and transactions only run the hooks
+// registered before their creation.
+func (db *TransactionDB) AddHook(hook Hook) {
+	db.hooks.add(hook)
+}
+
+// ClearHooks unregisters all the hooks of the database.
+func (db *TransactionDB) ClearHooks() {
+	db.hooks.clear()
+}
+
+// SetHookPerfLevel sets the perf level at which the hooked operations
+// collect the perf context counters reported in OpResult.Perf.
+func (db *TransactionDB) SetHookPerfLevel(level PerfLevel) {
+	db.hooks.setPerfLevel(level)
+}
+
+// hookSet is an immutable set of hooks.
+type hookSet struct {
+	hooks     []Hook
+	perfLevel PerfLevel
+}
+
+// hookList holds the hooks of a database. The zero value holds no hooks.
+type hookList struct {
+	v  atomic.Value
+	mu sync.Mutex
+}
+
+// load returns the current hooks, or nil if there are none.
+func (l *hookList) load() *hookSet {
+	set, _ := l.v.Load().(*hookSet)
+	if set == nil || len(set.hooks) == 0 {
+		return nil
+	}
+	return set
+}
+
+func (l *hookList) update(fn func(set *hookSet)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	set := &hookSet{perfLevel: KDisable}
+	if old, _ := l.v.Load().(*hookSet); old != nil {
+		*set = *old
+		set.hooks = append([]Hook(nil), old.hooks...)
+	}
+	fn(set)
+	l.v.Store(set)
+}
+
+func (l *hookList) add(hook Hook) {
+	l.update(func(set *hookSet) { set.hooks = append(set.hooks, hook) })
+}
+
+func (l *hookList) clear() {
+	l.update(func(set *hookSet) { set.hooks = nil })
+}
+
+func (l *hookList) setPerfLevel(level PerfLevel) {
+	l.update(func(set *hookSet) { set.perfLevel = level })
+}
+
+// hookCall is an operation in progress.
+type hookCall struct {
+	set       *hookSet
+	info      OpInfo
+	states    []interface{}
+	start     time.Time
+	perf      *PerfContext
+	perfBegin PerfContextSnapshot
+	prevLevel PerfLevel
+}
+
+// begin calls the Before hooks of the set. It must be paired with a call
+// to end on the same goroutine.
+func (set *hookSet) begin(info OpInfo) *hookCall {
+	call := &hookCall{set: set, info: info, states: make([]interface{}, len(set.hooks))}
+	for i, hook := range set.hooks {
+		call.states[i] = hook.Before(&call.info)
+	}
+	if set.perfLevel > KDisable {
+		// The perf context is thread-local.
+		runtime.LockOSThread()
+		call.prevLevel = GetPerfLevel()
+		SetPerfLevel(set.perfLevel)
+		call.perf = NewPerfContext()
+		call.perfBegin = call.perf.Snapshot()
+	}
+	call.start = time.Now()
+	return call
+}
+
+// end calls the After hooks of the operation with the error it returned.
+func (call *hookCall) end(err *error) {
+	result := &OpResult{Duration: time.Since(call.start), Err: *err}
+	if call.perf != nil {
+		perf := call.perf.Snapshot().Sub(call.perfBegin)
+		result.Perf = &perf
+		call.perf.Destroy()
+		SetPerfLevel(call.prevLevel)
+		runtime.UnlockOSThread()
+	}
+	for i, hook := range call.set.hooks {
+		hook.After(&call.info, call.states[i], result)
+	}
+}
+
+// beginOp starts an operation on a database with the given hooks, and
+// returns nil if there are none.
+func beginOp(l *hookList, op OpType, cf *ColumnFamilyHandle, keySize, valueSize, count int) *hookCall {
+	return l.load().beginOp(op, cf, keySize, valueSize, count)
+}
+
+// beginOp starts an operation with the hooks of the set, and returns nil
+// if the set is nil.
+func (set *hookSet) beginOp(op OpType, cf *ColumnFamilyHandle, keySize, valueSize, count int) *hookCall {
+	if set == nil {
+		return nil
+	}
+	return set.begin(OpInfo{Type: op, ColumnFamily: cf, KeySize: keySize, ValueSize: valueSize, Count: count})
+}
+
+// totalSize returns the total length of the byte slices.
+func totalSize(bufs [][]byte) int {
+	n := 0
+	for _, b := range bufs {
+		n += len(b)
+	}
+	return n
+}
+
+// common returns the column family shared by all the handles, or nil if
+// they differ.
+func (cfs ColumnFamilyHandles) common() *ColumnFamilyHandle {
+	if len(cfs) == 0 {
+		return nil
+	}
+	for _, cf := range cfs[1:] {
+		if cf != cfs[0] {
+			return nil
+		}
+	}
+	return cfs[0]
+}
@@ -0,0 +1,203 @@
+package gorocksdb
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+type recordedOp struct {
+	info   OpInfo
+	state  interface{}
+	result OpResult
+}
+
+// recordingHook records the operations it sees.
+type recordingHook struct {
+	mu   sync.Mutex
+	seen int
+	ops  []recordedOp
+}
+
+func (h *recordingHook) Before(op *OpInfo) interface{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.seen++
+	return h.seen
+}
+
+func (h *recordingHook) After(op *OpInfo, state interface{}, result *OpResult) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.ops = append(h.ops, recordedOp{*op, state, *result})
+}
+
+func (h *recordingHook) types() []OpType {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	types := make([]OpType, len(h.ops))
+	for i, op := range h.ops {
+		types[i] = op.info.Type
+	}
+	return types
+}
+
+func TestDBHooks(t *testing.T) {
+	db := newTestDB(t, "TestDBHooks", nil)
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	ro := NewDefaultReadOptions()
+	defer ro.Destroy()
+
+	hook := &recordingHook{}
+	db.AddHook(hook)
+	var latencies int
+	db.AddHook(AfterHook(func(op *OpInfo, result *OpResult) {
+		latencies++
+	}))
+
+	ensure.Nil(t, db.Put(wo, []byte("key1"), []byte("value1")))
+	v, err := db.Get(ro, []byte("key1"))
+	ensure.Nil(t, err)
+	v.Free()
+	ensure.Nil(t, db.Delete(wo, []byte("key1")))
+
+	wb := NewWriteBatch()
+	defer wb.Destroy()
+	wb.Put([]byte("key2"), []byte("value"))
+	wb.Put([]byte("key3"), []byte("value"))
+	ensure.Nil(t, db.Write(wo, wb))
+
+	vals, err := db.MultiGet(ro, []byte("key2"), []byte("key3"))
+	ensure.Nil(t, err)
+	vals.Destroy()
+
+	iter := db.NewIterator(ro)
+	for iter.SeekToFirst(); iter.Valid(); iter.Next() {
+	}
+	ensure.Nil(t, iter.Err())
+	iter.Close()
+
+	ensure.DeepEqual(t, hook.types(), []OpType{
+		OpPut, OpGet, OpDelete, OpWrite, OpMultiGet,
+		OpIteratorSeek, OpIteratorNext, OpIteratorNext,
+	})
+	ensure.DeepEqual(t, latencies, len(hook.ops))
+
+	put := hook.ops[0]
+	ensure.DeepEqual(t, put.state, 1)
+	ensure.DeepEqual(t, put.info.KeySize, 4)
+	ensure.DeepEqual(t, put.info.ValueSize, 6)
+	ensure.True(t, put.info.ColumnFamily == nil)
+	ensure.Nil(t, put.result.Err)
+	ensure.True(t, put.result.Duration > 0)
+	ensure.True(t, put.result.Perf == nil)
+	ensure.DeepEqual(t, hook.ops[3].info.Count, 2)
+	ensure.DeepEqual(t, hook.ops[3].info.ValueSize, len(wb.Data()))
+	ensure.DeepEqual(t, hook.ops[4].info.KeySize, 8)
+	ensure.DeepEqual(t, hook.ops[4].info.Count, 2)
+
+	// perf context deltas, on a thread whose perf level is known to check
+	// that it is restored
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	defer SetPerfLevel(GetPerfLevel())
+	SetPerfLevel(KEnableCount)
+	db.SetHookPerfLevel(KEnableTime)
+	v, err = db.Get(ro, []byte("key2"))
+	ensure.Nil(t, err)
+	v.Free()
+	get := hook.ops[len(hook.ops)-1]
+	ensure.DeepEqual(t, get.info.Type, OpGet)
+	ensure.NotNil(t, get.result.Perf)
+	ensure.True(t, get.result.Perf.GetReadBytes > 0)
+	ensure.DeepEqual(t, GetPerfLevel(), KEnableCount)
+
+	// errors are reported
+	invalid := NewDefaultWriteOptions()
+	defer invalid.Destroy()
+	invalid.SetSync(true)
+	invalid.DisableWAL(true)
+	err = db.Put(invalid, []byte("key4"), []byte("value"))
+	ensure.NotNil(t, err)
+	ensure.DeepEqual(t, hook.ops[len(hook.ops)-1].result.Err, err)
+
+	db.ClearHooks()
+	n := len(hook.ops)
+	ensure.Nil(t, db.Put(wo, []byte("key4"), []byte("value")))
+	ensure.DeepEqual(t, len(hook.ops), n)
+}
+
+func TestDBHooksColumnFamily(t *testing.T) {
+	db, cfh, cleanup := newTestDBCF(t, "TestDBHooksColumnFamily")
+	defer cleanup()
+
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	ro := NewDefaultReadOptions()
+	defer ro.Destroy()
+
+	hook := &recordingHook{}
+	db.AddHook(hook)
+
+	ensure.Nil(t, db.PutCF(wo, cfh[1], []byte("key"), []byte("value")))
+	vals, err := db.MultiGetCF(ro, cfh[1], []byte("key"))
+	ensure.Nil(t, err)
+	vals.Destroy()
+	vals, err = db.MultiGetCFMultiCF(ro, cfh, [][]byte{[]byte("key"), []byte("key")})
+	ensure.Nil(t, err)
+	vals.Destroy()
+
+	ensure.DeepEqual(t, hook.types(), []OpType{OpPut, OpMultiGet, OpMultiGet})
+	ensure.True(t, hook.ops[0].info.ColumnFamily == cfh[1])
+	ensure.True(t, hook.ops[1].info.ColumnFamily == cfh[1])
+	ensure.True(t, hook.ops[2].info.ColumnFamily == nil)
+}
+
+func TestTransactionDBHooks(t *testing.T) {
+	db := newTestTransactionDB(t, "TestTransactionDBHooks", nil)
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	ro := NewDefaultReadOptions()
+	defer ro.Destroy()
+
+	hook := &recordingHook{}
+	db.AddHook(hook)
+
+	ensure.Nil(t, db.Put(wo, []byte("key"), []byte("value")))
+	v, err := db.Get(ro, []byte("key"))
+	ensure.Nil(t, err)
+	v.Free()
+	ensure.Nil(t, db.Delete(wo, []byte("key")))
+
+	ensure.DeepEqual(t, hook.types(), []OpType{OpPut, OpGet, OpDelete})
+	ensure.DeepEqual(t, OpGet.String(), "Get")
+
+	// the operations of the transactions are hooked too
+	txn := db.TransactionBegin(wo, NewDefaultTransactionOptions(), nil)
+	defer txn.Destroy()
+	ensure.Nil(t, txn.Put([]byte("key"), []byte("value")))
+	v, err = txn.Get(ro, []byte("key"))
+	ensure.Nil(t, err)
+	v.Free()
+	v, err = txn.GetForUpdate(ro, []byte("other"))
+	ensure.Nil(t, err)
+	v.Free()
+	iter := txn.NewIterator(ro)
+	iter.SeekToFirst()
+	ensure.Nil(t, iter.Err())
+	iter.Close()
+	ensure.Nil(t, txn.Delete([]byte("other")))
+	ensure.Nil(t, txn.Commit())
+
+	ensure.DeepEqual(t, hook.types()[3:], []OpType{
+		OpPut, OpGet, OpGetForUpdate, OpIteratorSeek, OpDelete, OpCommit,
+	})
+	ensure.DeepEqual(t, OpCommit.String(), "Commit")
+}
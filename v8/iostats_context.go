@@ -0,0 +1,78 @@
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+// #include "gorocksdb.h"
+import "C"
+import "unsafe"
+
+// IOStatsContext is a thread local context gathering the IO statistics of
+// the RocksDB operations run by the current thread. Like PerfContext, it is
+// affected by the perf level, and the goroutine must be locked to its OS
+// thread while measuring, see MeasurePerf.
+type IOStatsContext struct{}
+
+// NewIOStatsContext returns the IO stats context of the current thread.
+func NewIOStatsContext() *IOStatsContext {
+	return &IOStatsContext{}
+}
+
+// Reset zeroes all the counters.
+func (ctx *IOStatsContext) Reset() {
+	C.gorocksdb_iostats_context_reset()
+}
+
+// Report returns the counters in a human readable form, excluding the zero
+// counters if excludeZeroCounters is true.
+func (ctx *IOStatsContext) Report(excludeZeroCounters bool) string {
+	cValue := C.gorocksdb_iostats_context_report(boolToChar(excludeZeroCounters))
+	defer C.free(unsafe.Pointer(cValue))
+	return C.GoString(cValue)
+}
+
+// IOStatsSnapshot holds the values of the IOStatsContext counters, named as
+// in rocksdb::IOStatsContext. Times are in nanoseconds.
+type IOStatsSnapshot struct {
+	ThreadPoolID      uint64
+	BytesWritten      uint64
+	BytesRead         uint64
+	OpenNanos         uint64
+	AllocateNanos     uint64
+	WriteNanos        uint64
+	ReadNanos         uint64
+	RangeSyncNanos    uint64
+	FsyncNanos        uint64
+	PrepareWriteNanos uint64
+	LoggerNanos       uint64
+	CPUWriteNanos     uint64
+	CPUReadNanos      uint64
+}
+
+// Snapshot returns the current values of all the counters.
+func (ctx *IOStatsContext) Snapshot() IOStatsSnapshot {
+	var s C.gorocksdb_iostats_t
+	C.gorocksdb_iostats_context_get(&s)
+	return IOStatsSnapshot{
+		ThreadPoolID:      uint64(s.thread_pool_id),
+		BytesWritten:      uint64(s.bytes_written),
+		BytesRead:         uint64(s.bytes_read),
+		OpenNanos:         uint64(s.open_nanos),
+		AllocateNanos:     uint64(s.allocate_nanos),
+		WriteNanos:        uint64(s.write_nanos),
+		ReadNanos:         uint64(s.read_nanos),
+		RangeSyncNanos:    uint64(s.range_sync_nanos),
+		FsyncNanos:        uint64(s.fsync_nanos),
+		PrepareWriteNanos: uint64(s.prepare_write_nanos),
+		LoggerNanos:       uint64(s.logger_nanos),
+		CPUWriteNanos:     uint64(s.cpu_write_nanos),
+		CPUReadNanos:      uint64(s.cpu_read_nanos),
+	}
+}
+
+// Sub returns the counters accumulated since before was taken. The thread
+// pool ID is kept.
+func (s IOStatsSnapshot) Sub(before IOStatsSnapshot) IOStatsSnapshot {
+	result := subCounters(s, before).(IOStatsSnapshot)
+	result.ThreadPoolID = s.ThreadPoolID
+	return result
+}
@@ -27,12 +27,14 @@ import (
 //      }
 //
 type Iterator struct {
-	c *C.rocksdb_iterator_t
+	c     *C.rocksdb_iterator_t
+	hooks *hookSet
+	cf    *ColumnFamilyHandle
 }
 
 // NewNativeIterator creates a Iterator object.
 func NewNativeIterator(c unsafe.Pointer) *Iterator {
-	return &Iterator{(*C.rocksdb_iterator_t)(c)}
+	return &Iterator{c: (*C.rocksdb_iterator_t)(c)}
 }
 
 // Valid returns false only when an Iterator has iterated past either the
@@ -76,26 +78,41 @@ func (iter *Iterator) Value() *Slice {
 
 // Next moves the iterator to the next sequential key in the database.
 func (iter *Iterator) Next() {
+	if call := iter.beginOp(OpIteratorNext, 0); call != nil {
+		defer iter.endOp(call)
+	}
 	C.rocksdb_iter_next(iter.c)
 }
 
 // Prev moves the iterator to the previous sequential key in the database.
 func (iter *Iterator) Prev() {
+	if call := iter.beginOp(OpIteratorPrev, 0); call != nil {
+		defer iter.endOp(call)
+	}
 	C.rocksdb_iter_prev(iter.c)
 }
 
 // SeekToFirst moves the iterator to the first key in the database.
 func (iter *Iterator) SeekToFirst() {
+	if call := iter.beginOp(OpIteratorSeek, 0); call != nil {
+		defer iter.endOp(call)
+	}
 	C.rocksdb_iter_seek_to_first(iter.c)
 }
 
 // SeekToLast moves the iterator to the last key in the database.
 func (iter *Iterator) SeekToLast() {
+	if call := iter.beginOp(OpIteratorSeek, 0); call != nil {
+		defer iter.endOp(call)
+	}
 	C.rocksdb_iter_seek_to_last(iter.c)
 }
 
 // Seek moves the iterator to the position greater than or equal to the key.
 func (iter *Iterator) Seek(key []byte) {
+	if call := iter.beginOp(OpIteratorSeek, len(key)); call != nil {
+		defer iter.endOp(call)
+	}
 	cKey := byteToChar(key)
 	C.rocksdb_iter_seek(iter.c, cKey, C.size_t(len(key)))
 }
@@ -103,6 +120,9 @@ func (iter *Iterator) Seek(key []byte) {
 // SeekForPrev moves the iterator to the last key that less than or equal
 // to the target key, in contrast with Seek.
 func (iter *Iterator) SeekForPrev(key []byte) {
+	if call := iter.beginOp(OpIteratorSeek, len(key)); call != nil {
+		defer iter.endOp(call)
+	}
 	cKey := byteToChar(key)
 	C.rocksdb_iter_seek_for_prev(iter.c, cKey, C.size_t(len(key)))
 }
@@ -119,6 +139,18 @@ func (iter *Iterator) Err() error {
 	return nil
 }
 
+func (iter *Iterator) beginOp(op OpType, keySize int) *hookCall {
+	if iter.hooks == nil {
+		return nil
+	}
+	return iter.hooks.begin(OpInfo{Type: op, ColumnFamily: iter.cf, KeySize: keySize, Count: 1})
+}
+
+func (iter *Iterator) endOp(call *hookCall) {
+	err := iter.Err()
+	call.end(&err)
+}
+
 // Close closes the iterator.
 func (iter *Iterator) Close() {
 	C.rocksdb_iter_destroy(iter.c)
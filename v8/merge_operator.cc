@@ -0,0 +1,109 @@
+#include <deque>
+#include <vector>
+
+#include "gorocksdb_internal.h"
+#include "rocksdb/merge_operator.h"
+
+extern "C" {
+#include "_cgo_export.h"
+}
+
+using rocksdb::Logger;
+using rocksdb::MergeOperator;
+using rocksdb::Options;
+using rocksdb::Slice;
+
+namespace {
+
+// GoMergeOperator calls into a Go MergeOperator. Unlike the merge operators
+// of the RocksDB C API, it supports AllowSingleOperand and ShouldMerge.
+class GoMergeOperator : public MergeOperator {
+ public:
+  explicit GoMergeOperator(uintptr_t idx)
+      : idx_(idx),
+        name_(gorocksdb_mergeoperator_name(idx)),
+        allow_single_operand_(
+            gorocksdb_mergeoperator_allow_single_operand(idx)) {}
+
+  bool FullMergeV2(const MergeOperationInput& merge_in,
+                   MergeOperationOutput* merge_out) const override {
+    std::vector<char*> operands;
+    std::vector<size_t> operands_len;
+    for (const Slice& operand : merge_in.operand_list) {
+      operands.push_back(const_cast<char*>(operand.data()));
+      operands_len.push_back(operand.size());
+    }
+    const Slice* existing = merge_in.existing_value;
+    unsigned char success = 0;
+    size_t new_value_len = 0;
+    char* new_value = gorocksdb_mergeoperator_full_merge(
+        idx_, const_cast<char*>(merge_in.key.data()), merge_in.key.size(),
+        existing != nullptr ? const_cast<char*>(existing->data()) : nullptr,
+        existing != nullptr ? existing->size() : 0, operands.data(),
+        operands_len.data(), static_cast<int>(operands.size()), &success,
+        &new_value_len);
+    if (success) {
+      merge_out->new_value.assign(new_value != nullptr ? new_value : "",
+                                  new_value_len);
+    }
+    free(new_value);
+    return success;
+  }
+
+  bool PartialMergeMulti(const Slice& key,
+                         const std::deque<Slice>& operand_list,
+                         std::string* new_value,
+                         Logger* /*logger*/) const override {
+    std::vector<char*> operands;
+    std::vector<size_t> operands_len;
+    for (const Slice& operand : operand_list) {
+      operands.push_back(const_cast<char*>(operand.data()));
+      operands_len.push_back(operand.size());
+    }
+    unsigned char success = 0;
+    size_t result_len = 0;
+    char* result = gorocksdb_mergeoperator_partial_merge_multi(
+        idx_, const_cast<char*>(key.data()), key.size(), operands.data(),
+        operands_len.data(), static_cast<int>(operands.size()), &success,
+        &result_len);
+    if (success) {
+      new_value->assign(result != nullptr ? result : "", result_len);
+    }
+    free(result);
+    return success;
+  }
+
+  bool AllowSingleOperand() const override { return allow_single_operand_; }
+
+  bool ShouldMerge(const std::vector<Slice>& operands) const override {
+    // RocksDB passes the operands newest first; Go gets them in merge order.
+    std::vector<char*> data;
+    std::vector<size_t> lens;
+    for (auto it = operands.rbegin(); it != operands.rend(); ++it) {
+      data.push_back(const_cast<char*>(it->data()));
+      lens.push_back(it->size());
+    }
+    return gorocksdb_mergeoperator_should_merge(
+        idx_, data.data(), lens.data(), static_cast<int>(data.size()));
+  }
+
+  const char* Name() const override { return name_; }
+
+ private:
+  uintptr_t idx_;
+  // name_ is owned by the Go merge operator registry.
+  const char* name_;
+  bool allow_single_operand_;
+};
+
+}  // namespace
+
+extern "C" {
+
+void gorocksdb_options_set_go_merge_operator(rocksdb_options_t* opts,
+                                             uintptr_t idx) {
+  gorocksdb::RepValue<Options>(opts).merge_operator =
+      std::make_shared<GoMergeOperator>(idx);
+}
+
+}  // extern "C"
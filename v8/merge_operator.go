@@ -74,6 +74,24 @@ type MultiMerger interface {
 	PartialMergeMulti(key []byte, operands [][]byte) ([]byte, bool)
 }
 
+// SingleOperandMerger is implemented by merge operators whose partial merge
+// is also meaningful for a single operand, e.g. to compact the format of the
+// operands. When a MergeOperator implements this interface and
+// AllowSingleOperand returns true, PartialMerge or PartialMergeMulti may be
+// called with a single operand.
+type SingleOperandMerger interface {
+	AllowSingleOperand() bool
+}
+
+// ShouldMerger is implemented by merge operators that can cap the number of
+// operands read during a Get. When a MergeOperator implements this interface,
+// ShouldMerge is called with the operands found so far, in the order they
+// were merged, and returning true makes the Get merge them right away
+// without reading the older entries of the key. It doesn't affect iterators.
+type ShouldMerger interface {
+	ShouldMerge(operands [][]byte) bool
+}
+
 // NewNativeMergeOperator creates a MergeOperator object.
 func NewNativeMergeOperator(c *C.rocksdb_mergeoperator_t) MergeOperator {
 	return nativeMergeOperator{c}
@@ -143,13 +161,12 @@ func gorocksdb_mergeoperator_partial_merge_multi(idx int, cKey *C.char, cKeyLen
 	case MultiMerger:
 		newValue, success = v.PartialMergeMulti(key, operands)
 	case PartialMerger:
-		leftOperand := operands[0]
+		newValue = operands[0]
 		for i := 1; i < int(cNumOperands); i++ {
-			newValue, success = v.PartialMerge(key, leftOperand, operands[i])
+			newValue, success = v.PartialMerge(key, newValue, operands[i])
 			if !success {
 				break
 			}
-			leftOperand = newValue
 		}
 	default:
 		success = false
@@ -166,3 +183,28 @@ func gorocksdb_mergeoperator_partial_merge_multi(idx int, cKey *C.char, cKeyLen
 func gorocksdb_mergeoperator_name(idx int) *C.char {
 	return mergeOperators.Get(idx).(mergeOperatorWrapper).name
 }
+
+//export gorocksdb_mergeoperator_allow_single_operand
+func gorocksdb_mergeoperator_allow_single_operand(idx int) C.uchar {
+	merger := mergeOperators.Get(idx).(mergeOperatorWrapper).mergeOperator
+	if v, ok := merger.(SingleOperandMerger); ok {
+		return boolToChar(v.AllowSingleOperand())
+	}
+	return 0
+}
+
+//export gorocksdb_mergeoperator_should_merge
+func gorocksdb_mergeoperator_should_merge(idx int, cOperands **C.char, cOperandsLen *C.size_t, cNumOperands C.int) C.uchar {
+	merger := mergeOperators.Get(idx).(mergeOperatorWrapper).mergeOperator
+	v, ok := merger.(ShouldMerger)
+	if !ok {
+		return 0
+	}
+	rawOperands := charSlice(cOperands, cNumOperands)
+	operandsLen := sizeSlice(cOperandsLen, cNumOperands)
+	operands := make([][]byte, int(cNumOperands))
+	for i, len := range operandsLen {
+		operands[i] = charToByte(rawOperands[i], len)
+	}
+	return boolToChar(v.ShouldMerge(operands))
+}
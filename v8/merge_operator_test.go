@@ -1,6 +1,7 @@
 package gorocksdb
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/facebookgo/ensure"
@@ -159,6 +160,67 @@ func TestMergeMultiOperator(t *testing.T) {
 }
 
 // Mock Objects
+func TestGetMergeOperands(t *testing.T) {
+	db := newTestDB(t, "TestGetMergeOperands", func(opts *Options) {
+		opts.SetMergeOperator(NewStringAppendMergeOperator(","))
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	ro := NewDefaultReadOptions()
+	key := []byte("key")
+	ensure.Nil(t, db.Put(wo, key, []byte("a")))
+	ensure.Nil(t, db.Merge(wo, key, []byte("b")))
+	ensure.Nil(t, db.Merge(wo, key, []byte("c")))
+
+	operands, err := db.GetMergeOperands(ro, nil, key, 10)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, operands, [][]byte{[]byte("a"), []byte("b"), []byte("c")})
+
+	_, err = db.GetMergeOperands(ro, nil, key, 2)
+	ensure.NotNil(t, err)
+
+	operands, err = db.GetMergeOperands(ro, nil, []byte("missing"), 10)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(operands), 0)
+}
+
+func TestShouldMergeOperator(t *testing.T) {
+	var seen [][][]byte
+	merger := &mockShouldMergeOperator{
+		mockMergeOperator: mockMergeOperator{
+			fullMerge: func(key, existingValue []byte, operands [][]byte) ([]byte, bool) {
+				return bytes.Join(append([][]byte{existingValue}, operands...), nil), true
+			},
+		},
+		shouldMerge: func(operands [][]byte) bool {
+			copied := make([][]byte, len(operands))
+			for i, operand := range operands {
+				copied[i] = append([]byte(nil), operand...)
+			}
+			seen = append(seen, copied)
+			return len(operands) >= 2
+		},
+	}
+	db := newTestDB(t, "TestShouldMergeOperator", func(opts *Options) {
+		opts.SetMergeOperator(merger)
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	key := []byte("key")
+	ensure.Nil(t, db.Put(wo, key, []byte("a")))
+	ensure.Nil(t, db.Merge(wo, key, []byte("b")))
+	ensure.Nil(t, db.Merge(wo, key, []byte("c")))
+	ensure.Nil(t, db.Merge(wo, key, []byte("d")))
+
+	// the Get stops reading the operands once ShouldMerge returns true
+	v, err := db.GetBytes(NewDefaultReadOptions(), key)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v, []byte("cd"))
+	ensure.DeepEqual(t, seen[len(seen)-1], [][]byte{[]byte("c"), []byte("d")})
+}
+
 type mockMergeOperator struct {
 	fullMerge func(key, existingValue []byte, operands [][]byte) ([]byte, bool)
 }
@@ -193,3 +255,12 @@ func (m *mockMergePartialOperator) FullMerge(key, existingValue []byte, operands
 func (m *mockMergePartialOperator) PartialMerge(key, leftOperand, rightOperand []byte) ([]byte, bool) {
 	return m.partialMerge(key, leftOperand, rightOperand)
 }
+
+type mockShouldMergeOperator struct {
+	mockMergeOperator
+	shouldMerge func(operands [][]byte) bool
+}
+
+func (m *mockShouldMergeOperator) ShouldMerge(operands [][]byte) bool {
+	return m.shouldMerge(operands)
+}
@@ -0,0 +1,294 @@
+#include <string.h>
+#include "gorocksdb.h"
+
+// Built-in merge operators implemented in C, so that merging does not need
+// to call into Go for every operand. Their semantics differ in places from
+// the RocksDB operators of the same purpose, so they are all named with a
+// "gorocksdb." prefix: a DB written with one cannot be opened with the other.
+
+typedef struct gorocksdb_native_mergeoperator_t gorocksdb_native_mergeoperator_t;
+
+struct gorocksdb_native_mergeoperator_t {
+    const char* name;
+    // merge combines the n values, which are the existing value (if any)
+    // followed by the operands, into a malloc'ed value.
+    char* (*merge)(const gorocksdb_native_mergeoperator_t* op,
+                   const char* const* values, const size_t* lens, int n,
+                   unsigned char* success, size_t* new_value_length);
+    char* delim;
+    size_t delim_len;
+};
+
+static void gorocksdb_native_mergeoperator_destroy(void* state) {
+    gorocksdb_native_mergeoperator_t* op = (gorocksdb_native_mergeoperator_t*)state;
+    free(op->delim);
+    free(op);
+}
+
+static const char* gorocksdb_native_mergeoperator_name(void* state) {
+    return ((gorocksdb_native_mergeoperator_t*)state)->name;
+}
+
+static char* gorocksdb_native_mergeoperator_full_merge(
+    void* state, const char* key, size_t key_length,
+    const char* existing_value, size_t existing_value_length,
+    const char* const* operands_list, const size_t* operands_list_length,
+    int num_operands, unsigned char* success, size_t* new_value_length) {
+    gorocksdb_native_mergeoperator_t* op = (gorocksdb_native_mergeoperator_t*)state;
+    if (existing_value == NULL) {
+        return op->merge(op, operands_list, operands_list_length, num_operands, success, new_value_length);
+    }
+
+    const char** values = malloc(sizeof(char*) * (num_operands + 1));
+    size_t* lens = malloc(sizeof(size_t) * (num_operands + 1));
+    values[0] = existing_value;
+    lens[0] = existing_value_length;
+    memcpy(values + 1, operands_list, sizeof(char*) * num_operands);
+    memcpy(lens + 1, operands_list_length, sizeof(size_t) * num_operands);
+    char* result = op->merge(op, values, lens, num_operands + 1, success, new_value_length);
+    free(values);
+    free(lens);
+    return result;
+}
+
+static char* gorocksdb_native_mergeoperator_partial_merge(
+    void* state, const char* key, size_t key_length,
+    const char* const* operands_list, const size_t* operands_list_length,
+    int num_operands, unsigned char* success, size_t* new_value_length) {
+    gorocksdb_native_mergeoperator_t* op = (gorocksdb_native_mergeoperator_t*)state;
+    return op->merge(op, operands_list, operands_list_length, num_operands, success, new_value_length);
+}
+
+static rocksdb_mergeoperator_t* gorocksdb_native_mergeoperator_create(
+    const char* name,
+    char* (*merge)(const gorocksdb_native_mergeoperator_t*, const char* const*, const size_t*, int, unsigned char*, size_t*),
+    const char* delim, size_t delim_len) {
+    gorocksdb_native_mergeoperator_t* op = malloc(sizeof(gorocksdb_native_mergeoperator_t));
+    op->name = name;
+    op->merge = merge;
+    op->delim = malloc(delim_len + 1);
+    if (delim_len > 0) {
+        memcpy(op->delim, delim, delim_len);
+    }
+    op->delim_len = delim_len;
+    return rocksdb_mergeoperator_create(
+        op,
+        gorocksdb_native_mergeoperator_destroy,
+        gorocksdb_native_mergeoperator_full_merge,
+        gorocksdb_native_mergeoperator_partial_merge,
+        gorocksdb_mergeoperator_delete_value,
+        gorocksdb_native_mergeoperator_name);
+}
+
+static char* gorocksdb_copy(const char* data, size_t len, unsigned char* success, size_t* new_value_length) {
+    char* result = malloc(len > 0 ? len : 1);
+    if (len > 0) {
+        memcpy(result, data, len);
+    }
+    *new_value_length = len;
+    *success = 1;
+    return result;
+}
+
+static int gorocksdb_bytes_compare(const char* a, size_t alen, const char* b, size_t blen) {
+    int r = memcmp(a, b, alen < blen ? alen : blen);
+    if (r == 0) {
+        r = alen < blen ? -1 : (alen > blen ? 1 : 0);
+    }
+    return r;
+}
+
+/* uint64 add */
+
+static char* gorocksdb_uint64add_merge(
+    const gorocksdb_native_mergeoperator_t* op, const char* const* values,
+    const size_t* lens, int n, unsigned char* success, size_t* new_value_length) {
+    uint64_t sum = 0;
+    for (int i = 0; i < n; i++) {
+        if (lens[i] != 8) {
+            *success = 0;
+            return NULL;
+        }
+        const unsigned char* v = (const unsigned char*)values[i];
+        uint64_t x = 0;
+        for (int j = 7; j >= 0; j--) {
+            x = (x << 8) | v[j];
+        }
+        sum += x;
+    }
+    char* result = malloc(8);
+    for (int j = 0; j < 8; j++) {
+        result[j] = (char)(sum >> (8 * j));
+    }
+    *new_value_length = 8;
+    *success = 1;
+    return result;
+}
+
+rocksdb_mergeoperator_t* gorocksdb_mergeoperator_create_uint64add() {
+    return gorocksdb_native_mergeoperator_create("gorocksdb.UInt64AddOperator", gorocksdb_uint64add_merge, NULL, 0);
+}
+
+/* string append */
+
+static char* gorocksdb_stringappend_merge(
+    const gorocksdb_native_mergeoperator_t* op, const char* const* values,
+    const size_t* lens, int n, unsigned char* success, size_t* new_value_length) {
+    size_t len = 0;
+    for (int i = 0; i < n; i++) {
+        len += lens[i] + (i > 0 ? op->delim_len : 0);
+    }
+    char* result = malloc(len > 0 ? len : 1);
+    char* p = result;
+    for (int i = 0; i < n; i++) {
+        if (i > 0) {
+            memcpy(p, op->delim, op->delim_len);
+            p += op->delim_len;
+        }
+        memcpy(p, values[i], lens[i]);
+        p += lens[i];
+    }
+    *new_value_length = len;
+    *success = 1;
+    return result;
+}
+
+rocksdb_mergeoperator_t* gorocksdb_mergeoperator_create_stringappend(const char* delim, size_t delim_len) {
+    return gorocksdb_native_mergeoperator_create("gorocksdb.StringAppendOperator", gorocksdb_stringappend_merge, delim, delim_len);
+}
+
+/* max / min */
+
+static char* gorocksdb_extremum_merge(
+    const char* const* values, const size_t* lens, int n, int sign,
+    unsigned char* success, size_t* new_value_length) {
+    int best = 0;
+    for (int i = 1; i < n; i++) {
+        if (sign * gorocksdb_bytes_compare(values[i], lens[i], values[best], lens[best]) > 0) {
+            best = i;
+        }
+    }
+    return gorocksdb_copy(values[best], lens[best], success, new_value_length);
+}
+
+static char* gorocksdb_max_merge(
+    const gorocksdb_native_mergeoperator_t* op, const char* const* values,
+    const size_t* lens, int n, unsigned char* success, size_t* new_value_length) {
+    return gorocksdb_extremum_merge(values, lens, n, 1, success, new_value_length);
+}
+
+static char* gorocksdb_min_merge(
+    const gorocksdb_native_mergeoperator_t* op, const char* const* values,
+    const size_t* lens, int n, unsigned char* success, size_t* new_value_length) {
+    return gorocksdb_extremum_merge(values, lens, n, -1, success, new_value_length);
+}
+
+rocksdb_mergeoperator_t* gorocksdb_mergeoperator_create_max() {
+    return gorocksdb_native_mergeoperator_create("gorocksdb.MaxOperator", gorocksdb_max_merge, NULL, 0);
+}
+
+rocksdb_mergeoperator_t* gorocksdb_mergeoperator_create_min() {
+    return gorocksdb_native_mergeoperator_create("gorocksdb.MinOperator", gorocksdb_min_merge, NULL, 0);
+}
+
+/* bytes xor */
+
+static char* gorocksdb_bytesxor_merge(
+    const gorocksdb_native_mergeoperator_t* op, const char* const* values,
+    const size_t* lens, int n, unsigned char* success, size_t* new_value_length) {
+    size_t len = 0;
+    for (int i = 0; i < n; i++) {
+        if (lens[i] > len) {
+            len = lens[i];
+        }
+    }
+    char* result = calloc(len > 0 ? len : 1, 1);
+    for (int i = 0; i < n; i++) {
+        for (size_t j = 0; j < lens[i]; j++) {
+            result[j] ^= values[i][j];
+        }
+    }
+    *new_value_length = len;
+    *success = 1;
+    return result;
+}
+
+rocksdb_mergeoperator_t* gorocksdb_mergeoperator_create_bytesxor() {
+    return gorocksdb_native_mergeoperator_create("gorocksdb.BytesXOROperator", gorocksdb_bytesxor_merge, NULL, 0);
+}
+
+/* sorted set union */
+
+typedef struct {
+    const char* data;
+    size_t len;
+} gorocksdb_member_t;
+
+static int gorocksdb_member_compare(const void* a, const void* b) {
+    const gorocksdb_member_t* x = (const gorocksdb_member_t*)a;
+    const gorocksdb_member_t* y = (const gorocksdb_member_t*)b;
+    return gorocksdb_bytes_compare(x->data, x->len, y->data, y->len);
+}
+
+// gorocksdb_find returns the first occurrence of delim in data, or NULL.
+static const char* gorocksdb_find(const char* data, size_t len, const char* delim, size_t delim_len) {
+    for (size_t i = 0; i + delim_len <= len; i++) {
+        if (memcmp(data + i, delim, delim_len) == 0) {
+            return data + i;
+        }
+    }
+    return NULL;
+}
+
+static char* gorocksdb_sortedset_merge(
+    const gorocksdb_native_mergeoperator_t* op, const char* const* values,
+    const size_t* lens, int n, unsigned char* success, size_t* new_value_length) {
+    size_t cap = 0;
+    for (int i = 0; i < n; i++) {
+        cap += lens[i] / op->delim_len + 1;
+    }
+    gorocksdb_member_t* members = malloc(sizeof(gorocksdb_member_t) * cap);
+    size_t count = 0;
+    for (int i = 0; i < n; i++) {
+        const char* p = values[i];
+        const char* end = values[i] + lens[i];
+        while (p < end) {
+            const char* d = gorocksdb_find(p, end - p, op->delim, op->delim_len);
+            const char* member_end = d != NULL ? d : end;
+            if (member_end > p) {
+                members[count].data = p;
+                members[count].len = member_end - p;
+                count++;
+            }
+            p = d != NULL ? d + op->delim_len : end;
+        }
+    }
+    qsort(members, count, sizeof(gorocksdb_member_t), gorocksdb_member_compare);
+
+    size_t len = 0;
+    size_t unique = 0;
+    for (size_t i = 0; i < count; i++) {
+        if (i == 0 || gorocksdb_member_compare(&members[i], &members[unique - 1]) != 0) {
+            members[unique++] = members[i];
+            len += members[i].len + (unique > 1 ? op->delim_len : 0);
+        }
+    }
+    char* result = malloc(len > 0 ? len : 1);
+    char* p = result;
+    for (size_t i = 0; i < unique; i++) {
+        if (i > 0) {
+            memcpy(p, op->delim, op->delim_len);
+            p += op->delim_len;
+        }
+        memcpy(p, members[i].data, members[i].len);
+        p += members[i].len;
+    }
+    free(members);
+    *new_value_length = len;
+    *success = 1;
+    return result;
+}
+
+rocksdb_mergeoperator_t* gorocksdb_mergeoperator_create_sortedset_union(const char* delim, size_t delim_len) {
+    return gorocksdb_native_mergeoperator_create("gorocksdb.SortedSetUnionOperator", gorocksdb_sortedset_merge, delim, delim_len);
+}
@@ -0,0 +1,126 @@
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+// #include "gorocksdb.h"
+import "C"
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"unsafe"
+)
+
+// The built-in merge operators below are implemented natively, so merging
+// does not call into Go for every operand. Each returned MergeOperator must
+// only be set on a single Options.
+
+// NewUint64AddMergeOperator creates a merge operator adding uint64 values,
+// encoded as 8 bytes in little-endian order like RocksDB's UInt64AddOperator.
+// Unlike it, merging a value of another size fails instead of counting as 0.
+func NewUint64AddMergeOperator() MergeOperator {
+	return NewNativeMergeOperator(C.gorocksdb_mergeoperator_create_uint64add())
+}
+
+// NewStringAppendMergeOperator creates a merge operator appending the
+// operands to the existing value, separated by delimiter.
+func NewStringAppendMergeOperator(delimiter string) MergeOperator {
+	cDelim := C.CString(delimiter)
+	defer C.free(unsafe.Pointer(cDelim))
+	return NewNativeMergeOperator(C.gorocksdb_mergeoperator_create_stringappend(cDelim, C.size_t(len(delimiter))))
+}
+
+// NewMaxMergeOperator creates a merge operator keeping the bytewise largest
+// of the existing value and the operands.
+func NewMaxMergeOperator() MergeOperator {
+	return NewNativeMergeOperator(C.gorocksdb_mergeoperator_create_max())
+}
+
+// NewMinMergeOperator creates a merge operator keeping the bytewise smallest
+// of the existing value and the operands.
+func NewMinMergeOperator() MergeOperator {
+	return NewNativeMergeOperator(C.gorocksdb_mergeoperator_create_min())
+}
+
+// NewBytesXORMergeOperator creates a merge operator XORing the operands into
+// the existing value. The result is as long as the longest input, shorter
+// inputs being padded with zeros.
+func NewBytesXORMergeOperator() MergeOperator {
+	return NewNativeMergeOperator(C.gorocksdb_mergeoperator_create_bytesxor())
+}
+
+// NewSortedSetUnionMergeOperator creates a merge operator for sets stored as
+// delimiter separated members. The result is the union of the existing value
+// and the operands, sorted bytewise and without duplicates or empty members.
+func NewSortedSetUnionMergeOperator(delimiter byte) MergeOperator {
+	cDelim := C.CString(string(delimiter))
+	defer C.free(unsafe.Pointer(cDelim))
+	return NewNativeMergeOperator(C.gorocksdb_mergeoperator_create_sortedset_union(cDelim, 1))
+}
+
+// JSONMergePatch is a merge operator applying JSON merge patches (RFC 7386)
+// to a JSON document. Every operand is a patch; merging fails if the
+// existing value or an operand is not valid JSON. A missing existing value is
+// handled as null.
+type JSONMergePatch struct{}
+
+// FullMerge implements MergeOperator.
+func (JSONMergePatch) FullMerge(key, existingValue []byte, operands [][]byte) ([]byte, bool) {
+	var doc interface{}
+	if existingValue != nil {
+		if err := decodeJSON(existingValue, &doc); err != nil {
+			return nil, false
+		}
+	}
+	for _, operand := range operands {
+		var patch interface{}
+		if err := decodeJSON(operand, &patch); err != nil {
+			return nil, false
+		}
+		doc = applyJSONMergePatch(doc, patch)
+	}
+	result, err := json.Marshal(doc)
+	if err != nil {
+		return nil, false
+	}
+	return result, true
+}
+
+// Name implements MergeOperator.
+func (JSONMergePatch) Name() string { return "gorocksdb.JSONMergePatch" }
+
+// decodeJSON decodes the single JSON value of data into v, keeping numbers
+// as json.Number so that large integers are not rounded.
+func decodeJSON(data []byte, v *interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("invalid JSON: data after the top-level value")
+	}
+	return nil
+}
+
+// applyJSONMergePatch applies patch to target as described in RFC 7386.
+func applyJSONMergePatch(target, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObj, ok := target.(map[string]interface{})
+	if !ok {
+		targetObj = make(map[string]interface{}, len(patchObj))
+	}
+	for name, value := range patchObj {
+		if value == nil {
+			delete(targetObj, name)
+		} else {
+			targetObj[name] = applyJSONMergePatch(targetObj[name], value)
+		}
+	}
+	return targetObj
+}
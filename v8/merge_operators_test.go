@@ -0,0 +1,72 @@
+package gorocksdb
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestBuiltinMergeOperators(t *testing.T) {
+	u64 := func(v uint64) []byte {
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint64(b, v)
+		return b
+	}
+	for _, c := range []struct {
+		name     string
+		merger   func() MergeOperator
+		existing []byte
+		operands [][]byte
+		expected []byte
+	}{
+		{"Uint64Add", NewUint64AddMergeOperator, u64(1), [][]byte{u64(2), u64(3)}, u64(6)},
+		{"StringAppend", func() MergeOperator { return NewStringAppendMergeOperator(", ") }, []byte("a"), [][]byte{[]byte("b"), []byte("c")}, []byte("a, b, c")},
+		{"Max", NewMaxMergeOperator, []byte("b"), [][]byte{[]byte("c"), []byte("a")}, []byte("c")},
+		{"Min", NewMinMergeOperator, []byte("b"), [][]byte{[]byte("c"), []byte("a")}, []byte("a")},
+		{"BytesXOR", NewBytesXORMergeOperator, []byte{0x0f, 0xf0}, [][]byte{{0xff}, {0x01, 0x01, 0x01}}, []byte{0xf1, 0xf1, 0x01}},
+		{"SortedSetUnion", func() MergeOperator { return NewSortedSetUnionMergeOperator(',') }, []byte("c,a"), [][]byte{[]byte("b,a"), []byte("d")}, []byte("a,b,c,d")},
+		{"JSONMergePatch", func() MergeOperator { return JSONMergePatch{} }, []byte(`{"a":1,"b":{"c":2}}`), [][]byte{[]byte(`{"a":null,"b":{"d":3}}`), []byte(`{"e":[1]}`)}, []byte(`{"b":{"c":2,"d":3},"e":[1]}`)},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			db := newTestDB(t, "TestBuiltinMergeOperators"+c.name, func(opts *Options) {
+				opts.SetMergeOperator(c.merger())
+			})
+			defer db.Close()
+
+			key := []byte("key")
+			wo := NewDefaultWriteOptions()
+			ensure.Nil(t, db.Put(wo, key, c.existing))
+			for _, operand := range c.operands {
+				ensure.Nil(t, db.Merge(wo, key, operand))
+			}
+
+			ro := NewDefaultReadOptions()
+			v, err := db.GetBytes(ro, key)
+			ensure.Nil(t, err)
+			ensure.DeepEqual(t, v, c.expected)
+
+			// merging during compaction gives the same result
+			db.CompactRange(Range{nil, nil})
+			v, err = db.GetBytes(ro, key)
+			ensure.Nil(t, err)
+			ensure.DeepEqual(t, v, c.expected)
+		})
+	}
+}
+
+func TestJSONMergePatchInvalid(t *testing.T) {
+	_, ok := JSONMergePatch{}.FullMerge([]byte("k"), []byte("{"), [][]byte{[]byte("{}")})
+	ensure.False(t, ok)
+	v, ok := JSONMergePatch{}.FullMerge([]byte("k"), nil, [][]byte{[]byte(`{"a":{"b":null}}`)})
+	ensure.True(t, ok)
+	ensure.DeepEqual(t, v, []byte(`{"a":{}}`))
+	_, ok = JSONMergePatch{}.FullMerge([]byte("k"), []byte("{} {}"), [][]byte{[]byte("{}")})
+	ensure.False(t, ok)
+}
+
+func TestJSONMergePatchLargeNumbers(t *testing.T) {
+	v, ok := JSONMergePatch{}.FullMerge([]byte("k"), []byte(`{"id":9007199254740993}`), [][]byte{[]byte(`{"n":18446744073709551615}`)})
+	ensure.True(t, ok)
+	ensure.DeepEqual(t, v, []byte(`{"id":9007199254740993,"n":18446744073709551615}`))
+}
@@ -2,6 +2,7 @@ package gorocksdb
 
 // #include <stdlib.h>
 // #include "rocksdb/c.h"
+// #include "gorocksdb.h"
 import "C"
 import (
 	"errors"
@@ -10,9 +11,10 @@ import (
 
 // OptimisticTransactionDB is a reusable handle to a RocksDB optimistic transactional database on disk.
 type OptimisticTransactionDB struct {
-	c    *C.rocksdb_optimistictransactiondb_t
-	name string
-	opts *Options
+	c             *C.rocksdb_optimistictransactiondb_t
+	name          string
+	opts          *Options
+	errorListener *errorListener
 }
 
 // OpenOptimisticTransactionDb opens a database with the specified options.
@@ -25,16 +27,20 @@ func OpenOptimisticTransactionDb(
 		cName = C.CString(name)
 	)
 	defer C.free(unsafe.Pointer(cName))
+	cDBOpts, errorListener, freeOpts := opts.openOptions()
+	defer freeOpts()
 	db := C.rocksdb_optimistictransactiondb_open(
-		opts.c, cName, &cErr)
+		cDBOpts, cName, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		errorListener.release()
 		return nil, errors.New(C.GoString(cErr))
 	}
 	return &OptimisticTransactionDB{
-		name: name,
-		c:    db,
-		opts: opts,
+		name:          name,
+		c:             db,
+		opts:          opts,
+		errorListener: errorListener,
 	}, nil
 }
 
@@ -71,8 +77,10 @@ func OpenOptimisticTransactionDbColumnFamilies(
 	cHandles := make([]*C.rocksdb_column_family_handle_t, numColumnFamilies)
 
 	var cErr *C.char
+	cDBOpts, errorListener, freeOpts := opts.openOptions()
+	defer freeOpts()
 	db := C.rocksdb_optimistictransactiondb_open_column_families(
-		opts.c,
+		cDBOpts,
 		cName,
 		C.int(numColumnFamilies),
 		&cNames[0],
@@ -82,6 +90,7 @@ func OpenOptimisticTransactionDbColumnFamilies(
 	)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		errorListener.release()
 		return nil, nil, errors.New(C.GoString(cErr))
 	}
 
@@ -91,9 +100,10 @@ func OpenOptimisticTransactionDbColumnFamilies(
 	}
 
 	return &OptimisticTransactionDB{
-		name: name,
-		c:    db,
-		opts: opts,
+		name:          name,
+		c:             db,
+		opts:          opts,
+		errorListener: errorListener,
 	}, cfHandles, nil
 }
 
@@ -136,10 +146,10 @@ func (db *OptimisticTransactionDB) NewCheckpoint() (*Checkpoint, error) {
 // Write writes a WriteBatch to the database
 func (db *OptimisticTransactionDB) Write(opts *WriteOptions, batch *WriteBatch) error {
 	var cErr *C.char
-	C.rocksdb_optimistictransactiondb_write(db.c, opts.c, batch.c, &cErr)
+	noSpace := C.gorocksdb_optimistictransactiondb_write(db.c, opts.c, batch.c, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newWriteError(C.GoString(cErr), noSpace)
 	}
 	return nil
 }
@@ -148,6 +158,7 @@ func (db *OptimisticTransactionDB) Write(opts *WriteOptions, batch *WriteBatch)
 func (db *OptimisticTransactionDB) Close() {
 	C.rocksdb_optimistictransactiondb_close(db.c)
 	db.c = nil
+	db.errorListener.release()
 }
 
 // GetBaseDB returns base-database.
@@ -85,14 +85,18 @@ type Options struct {
 	c *C.rocksdb_options_t
 
 	// Hold references for GC.
-	env  *Env
-	bbto *BlockBasedTableOptions
+	env          *Env
+	bbto         *BlockBasedTableOptions
+	cmp          Comparator
+	errorHandler ErrorHandler
+	trackErrors  bool
 
 	// We keep these so we can free their memory in Destroy.
 	ccmp *C.rocksdb_comparator_t
 	cmo  *C.rocksdb_mergeoperator_t
 	cst  *C.rocksdb_slicetransform_t
 	ccf  *C.rocksdb_compactionfilter_t
+	ccf2 *C.gorocksdb_compactionfilter_v2_t
 }
 
 // NewDefaultOptions creates the default Options.
@@ -145,9 +149,44 @@ func (opts *Options) SetCompactionFilter(value CompactionFilter) {
 	C.rocksdb_options_set_compaction_filter(opts.c, opts.ccf)
 }
 
+// SetCompactionFilterV2 sets the specified CompactionFilterV2 which will be
+// applied on compactions. It replaces any filter set by SetCompactionFilter.
+// Default: nil
+func (opts *Options) SetCompactionFilterV2(value CompactionFilterV2) {
+	if opts.ccf2 != nil {
+		C.gorocksdb_compactionfilter_v2_destroy(opts.ccf2)
+	}
+	cName := C.CString(value.Name())
+	defer C.free(unsafe.Pointer(cName))
+	handle := compactionFilterHandles.register(value)
+	opts.ccf2 = C.gorocksdb_compactionfilter_v2_create(C.uintptr_t(handle), cName)
+	C.gorocksdb_options_set_compaction_filter_v2(opts.c, opts.ccf2)
+}
+
+// AddTablePropertiesCollectorFactory adds a factory of collectors of user
+// properties for every sst file written. The user properties are returned
+// along with the built-in ones by DB.GetPropertiesOfAllTables.
+func (opts *Options) AddTablePropertiesCollectorFactory(value TablePropertiesCollectorFactory) {
+	idx := registerTablePropertiesCollectorFactory(value)
+	C.gorocksdb_options_add_table_properties_collector_factory(opts.c, C.uintptr_t(idx))
+}
+
+// SetSstPartitionerFactory sets the factory of the partitioners deciding
+// where the output of compactions is split into several sst files.
+// Default: nil
+func (opts *Options) SetSstPartitionerFactory(value SstPartitionerFactory) {
+	if f, ok := value.(fixedPrefixSstPartitionerFactory); ok {
+		C.gorocksdb_options_set_sst_partitioner_fixed_prefix_factory(opts.c, C.size_t(f.prefixLen))
+		return
+	}
+	idx := registerSstPartitionerFactory(value)
+	C.gorocksdb_options_set_sst_partitioner_factory(opts.c, C.uintptr_t(idx))
+}
+
 // SetComparator sets the comparator which define the order of keys in the table.
 // Default: a comparator that uses lexicographic byte-wise ordering
 func (opts *Options) SetComparator(value Comparator) {
+	opts.cmp = value
 	if nc, ok := value.(nativeComparator); ok {
 		opts.ccmp = nc.c
 	} else {
@@ -161,9 +200,16 @@ func (opts *Options) SetComparator(value Comparator) {
 // if a merge operations are used.
 // Default: nil
 func (opts *Options) SetMergeOperator(value MergeOperator) {
-	if nmo, ok := value.(nativeMergeOperator); ok {
-		opts.cmo = nmo.c
-	} else {
+	switch value.(type) {
+	case nativeMergeOperator:
+		opts.cmo = value.(nativeMergeOperator).c
+	case SingleOperandMerger, ShouldMerger:
+		// The merge operators of the C API do not support these hooks.
+		idx := registerMergeOperator(value)
+		opts.cmo = nil
+		C.gorocksdb_options_set_go_merge_operator(opts.c, C.uintptr_t(idx))
+		return
+	default:
 		idx := registerMergeOperator(value)
 		opts.cmo = C.gorocksdb_mergeoperator_create(C.uintptr_t(idx))
 	}
@@ -190,16 +236,19 @@ func (opts *Options) SetMergeOperator(value MergeOperator) {
 //	C.rocksdb_options_set_compaction_filter(opts.c, value.filter)
 //}
 
-// This is a factory that provides compaction filter objects which allow
-// an application to modify/delete a key-value during background compaction.
+// SetCompactionFilterFactory sets a factory that provides compaction filter
+// objects which allow an application to modify/delete a key-value during
+// background compaction.
 //
 // A new filter will be created on each compaction run.  If multithreaded
 // compaction is being used, each created CompactionFilter will only be used
 // from a single thread and so does not need to be thread-safe.
 //
 // Default: a factory that doesn't provide any object
-// std::shared_ptr<CompactionFilterFactory> compaction_filter_factory;
-// TODO: implement in C and Go
+func (opts *Options) SetCompactionFilterFactory(value CompactionFilterFactory) {
+	idx := registerCompactionFilterFactory(value)
+	C.gorocksdb_options_set_compaction_filter_factory(opts.c, C.uintptr_t(idx))
+}
 
 // Version TWO of the compaction_filter_factory
 // It supports rolling compaction
@@ -899,6 +948,22 @@ func (opts *Options) SetDbWriteBufferSize(value int) {
 	C.rocksdb_options_set_db_write_buffer_size(opts.c, C.size_t(value))
 }
 
+// SetWriteBufferManager sets the WriteBufferManager limiting the memtable
+// memory of the DB. The same WriteBufferManager can be set on the options of
+// many DBs to limit their total memtable memory, in which case
+// SetDbWriteBufferSize is ignored.
+// Default: nil
+func (opts *Options) SetWriteBufferManager(wbm *WriteBufferManager) {
+	C.rocksdb_options_set_write_buffer_manager(opts.c, wbm.c)
+}
+
+// SetSstFileManager sets the SstFileManager tracking the SST files of the DB.
+// The same SstFileManager can be set on the options of many DBs.
+// Default: nil
+func (opts *Options) SetSstFileManager(m *SstFileManager) {
+	C.gorocksdb_options_set_sst_file_manager(opts.c, m.c)
+}
+
 // SetAccessHintOnCompactionStart specifies the file access pattern
 // once a compaction is started.
 //
@@ -1226,13 +1291,13 @@ func (opts *Options) SetAtomicFlush(value bool) {
 // entries in any "N" consecutive entries or the ratio of tombstone
 // entries in the whole file >= the specified deletion ratio.
 func (opts *Options) AddCompactOnDeletionCollectorFactory(windowSize, numDelsTrigger uint) {
-        C.rocksdb_options_add_compact_on_deletion_collector_factory(opts.c, C.size_t(windowSize), C.size_t(numDelsTrigger))
+	C.rocksdb_options_add_compact_on_deletion_collector_factory(opts.c, C.size_t(windowSize), C.size_t(numDelsTrigger))
 }
 
 // AddCompactOnDeletionCollectorFactoryWithRatio similar to AddCompactOnDeletionCollectorFactory
 // with specific deletion ratio.
 func (opts *Options) AddCompactOnDeletionCollectorFactoryWithRatio(windowSize, numDelsTrigger uint, deletionRatio float64) {
-        C.rocksdb_options_add_compact_on_deletion_collector_factory_del_ratio(opts.c, C.size_t(windowSize), C.size_t(numDelsTrigger), C.double(deletionRatio))
+	C.rocksdb_options_add_compact_on_deletion_collector_factory_del_ratio(opts.c, C.size_t(windowSize), C.size_t(numDelsTrigger), C.double(deletionRatio))
 }
 
 // SetMaxSubcompactions represents the maximum number of threads that will
@@ -1288,7 +1353,11 @@ func (opts *Options) Destroy() {
 	if opts.ccf != nil {
 		C.rocksdb_compactionfilter_destroy(opts.ccf)
 	}
+	if opts.ccf2 != nil {
+		C.gorocksdb_compactionfilter_v2_destroy(opts.ccf2)
+	}
 	opts.c = nil
 	opts.env = nil
 	opts.bbto = nil
+	opts.cmp = nil
 }
@@ -0,0 +1,37 @@
+#include "gorocksdb_internal.h"
+
+using rocksdb::CompactRangeOptions;
+
+extern "C" {
+
+void gorocksdb_compactoptions_set_target_path_id(rocksdb_compactoptions_t* opt,
+                                                 uint32_t v) {
+  gorocksdb::RepValue<CompactRangeOptions>(opt).target_path_id = v;
+}
+
+uint32_t gorocksdb_compactoptions_get_target_path_id(
+    rocksdb_compactoptions_t* opt) {
+  return gorocksdb::RepValue<CompactRangeOptions>(opt).target_path_id;
+}
+
+void gorocksdb_compactoptions_set_allow_write_stall(
+    rocksdb_compactoptions_t* opt, unsigned char v) {
+  gorocksdb::RepValue<CompactRangeOptions>(opt).allow_write_stall = v;
+}
+
+unsigned char gorocksdb_compactoptions_get_allow_write_stall(
+    rocksdb_compactoptions_t* opt) {
+  return gorocksdb::RepValue<CompactRangeOptions>(opt).allow_write_stall;
+}
+
+void gorocksdb_compactoptions_set_max_subcompactions(
+    rocksdb_compactoptions_t* opt, uint32_t v) {
+  gorocksdb::RepValue<CompactRangeOptions>(opt).max_subcompactions = v;
+}
+
+uint32_t gorocksdb_compactoptions_get_max_subcompactions(
+    rocksdb_compactoptions_t* opt) {
+  return gorocksdb::RepValue<CompactRangeOptions>(opt).max_subcompactions;
+}
+
+}  // extern "C"
@@ -1,7 +1,10 @@
 package gorocksdb
 
+// #include <stdlib.h>
 // #include "rocksdb/c.h"
+// #include "gorocksdb.h"
 import "C"
+import "unsafe"
 
 // BottommostLevelCompaction for level based compaction, we can configure if we want to skip/force
 // bottommost level compaction.
@@ -23,6 +26,9 @@ const (
 // CompactRangeOptions represent all of the available options for compact range.
 type CompactRangeOptions struct {
 	c *C.rocksdb_compactoptions_t
+
+	// Hold the full history timestamp, referenced by the options.
+	cFullHistoryTsLow *C.char
 }
 
 // NewCompactRangeOptions creates new compact range options.
@@ -36,6 +42,8 @@ func NewCompactRangeOptions() *CompactRangeOptions {
 func (opts *CompactRangeOptions) Destroy() {
 	C.rocksdb_compactoptions_destroy(opts.c)
 	opts.c = nil
+	C.free(unsafe.Pointer(opts.cFullHistoryTsLow))
+	opts.cFullHistoryTsLow = nil
 }
 
 // SetBottommostLevelCompaction sets bottommost level compaction.
@@ -44,3 +52,92 @@ func (opts *CompactRangeOptions) Destroy() {
 func (opts *CompactRangeOptions) SetBottommostLevelCompaction(value BottommostLevelCompaction) {
 	C.rocksdb_compactoptions_set_bottommost_level_compaction(opts.c, C.uchar(value))
 }
+
+// SetExclusiveManualCompaction sets whether the compaction waits for the
+// other compactions to finish and prevents new ones from starting.
+//
+// Default: true
+func (opts *CompactRangeOptions) SetExclusiveManualCompaction(value bool) {
+	C.rocksdb_compactoptions_set_exclusive_manual_compaction(opts.c, boolToChar(value))
+}
+
+// GetExclusiveManualCompaction returns whether the compaction is exclusive.
+func (opts *CompactRangeOptions) GetExclusiveManualCompaction() bool {
+	return charToBool(C.rocksdb_compactoptions_get_exclusive_manual_compaction(opts.c))
+}
+
+// SetChangeLevel sets whether the compacted files are moved to the minimum
+// level able to hold them, or to the target level if one is set.
+//
+// Default: false
+func (opts *CompactRangeOptions) SetChangeLevel(value bool) {
+	C.rocksdb_compactoptions_set_change_level(opts.c, boolToChar(value))
+}
+
+// GetChangeLevel returns whether the compacted files change level.
+func (opts *CompactRangeOptions) GetChangeLevel() bool {
+	return charToBool(C.rocksdb_compactoptions_get_change_level(opts.c))
+}
+
+// SetTargetLevel sets the level the compacted files are moved to when
+// change level is set. A negative level means the minimum level able to
+// hold them.
+//
+// Default: -1
+func (opts *CompactRangeOptions) SetTargetLevel(value int) {
+	C.rocksdb_compactoptions_set_target_level(opts.c, C.int(value))
+}
+
+// GetTargetLevel returns the level the compacted files are moved to.
+func (opts *CompactRangeOptions) GetTargetLevel() int {
+	return int(C.rocksdb_compactoptions_get_target_level(opts.c))
+}
+
+// SetTargetPathID sets the index in the DB paths of the directory the
+// compaction output is written to.
+//
+// Default: 0
+func (opts *CompactRangeOptions) SetTargetPathID(value uint32) {
+	C.gorocksdb_compactoptions_set_target_path_id(opts.c, C.uint32_t(value))
+}
+
+// GetTargetPathID returns the index of the DB path of the compaction output.
+func (opts *CompactRangeOptions) GetTargetPathID() uint32 {
+	return uint32(C.gorocksdb_compactoptions_get_target_path_id(opts.c))
+}
+
+// SetAllowWriteStall sets whether the compaction starts right away even if
+// it causes a write stall, instead of waiting for the flushes it requires.
+//
+// Default: false
+func (opts *CompactRangeOptions) SetAllowWriteStall(value bool) {
+	C.gorocksdb_compactoptions_set_allow_write_stall(opts.c, boolToChar(value))
+}
+
+// GetAllowWriteStall returns whether the compaction may cause a write stall.
+func (opts *CompactRangeOptions) GetAllowWriteStall() bool {
+	return charToBool(C.gorocksdb_compactoptions_get_allow_write_stall(opts.c))
+}
+
+// SetMaxSubcompactions sets the maximum number of subcompactions the
+// compaction is split into. 0 means the max_subcompactions of the DB options.
+//
+// Default: 0
+func (opts *CompactRangeOptions) SetMaxSubcompactions(value uint32) {
+	C.gorocksdb_compactoptions_set_max_subcompactions(opts.c, C.uint32_t(value))
+}
+
+// GetMaxSubcompactions returns the maximum number of subcompactions.
+func (opts *CompactRangeOptions) GetMaxSubcompactions() uint32 {
+	return uint32(C.gorocksdb_compactoptions_get_max_subcompactions(opts.c))
+}
+
+// SetFullHistoryTsLow sets the user-defined timestamp below which the
+// history of the keys may be collapsed by the compaction. It is only used
+// with column families having a timestamp-aware comparator.
+func (opts *CompactRangeOptions) SetFullHistoryTsLow(ts []byte) {
+	cTs := (*C.char)(C.CBytes(ts))
+	C.rocksdb_compactoptions_set_full_history_ts_low(opts.c, cTs, C.size_t(len(ts)))
+	C.free(unsafe.Pointer(opts.cFullHistoryTsLow))
+	opts.cFullHistoryTsLow = cTs
+}
@@ -0,0 +1,23 @@
+#include "gorocksdb_internal.h"
+
+using rocksdb::IngestExternalFileOptions;
+
+extern "C" {
+
+void gorocksdb_ingestexternalfileoptions_set_verify_checksums_before_ingest(
+    rocksdb_ingestexternalfileoptions_t* opt, unsigned char v) {
+  gorocksdb::RepValue<IngestExternalFileOptions>(opt)
+      .verify_checksums_before_ingest = v;
+}
+
+void gorocksdb_ingestexternalfileoptions_set_write_global_seqno(
+    rocksdb_ingestexternalfileoptions_t* opt, unsigned char v) {
+  gorocksdb::RepValue<IngestExternalFileOptions>(opt).write_global_seqno = v;
+}
+
+void gorocksdb_ingestexternalfileoptions_set_verify_file_checksum(
+    rocksdb_ingestexternalfileoptions_t* opt, unsigned char v) {
+  gorocksdb::RepValue<IngestExternalFileOptions>(opt).verify_file_checksum = v;
+}
+
+}  // extern "C"
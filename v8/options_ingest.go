@@ -1,6 +1,7 @@
 package gorocksdb
 
 // #include "rocksdb/c.h"
+// #include "gorocksdb.h"
 import "C"
 
 // IngestExternalFileOptions represents available options when ingesting external files.
@@ -58,6 +59,40 @@ func (opts *IngestExternalFileOptions) SetIngestionBehind(flag bool) {
 	C.rocksdb_ingestexternalfileoptions_set_ingest_behind(opts.c, boolToChar(flag))
 }
 
+// SetVerifyChecksumsBeforeIngest sets verify_checksums_before_ingest.
+// If set to true, the checksums of all the blocks of the files are verified
+// before they are ingested. This can be expensive for large files.
+// Default to false.
+func (opts *IngestExternalFileOptions) SetVerifyChecksumsBeforeIngest(flag bool) {
+	C.gorocksdb_ingestexternalfileoptions_set_verify_checksums_before_ingest(opts.c, boolToChar(flag))
+}
+
+// SetFailIfNotBottommostLevel sets fail_if_not_bottommost_level.
+// If set to true, ingestion fails if the files cannot be ingested into
+// the bottommost level.
+// Default to false.
+func (opts *IngestExternalFileOptions) SetFailIfNotBottommostLevel(flag bool) {
+	C.rocksdb_ingestexternalfileoptions_set_fail_if_not_bottommost_level(opts.c, boolToChar(flag))
+}
+
+// SetWriteGlobalSeqno sets write_global_seqno. If set to true and a global
+// sequence number has to be assigned to an ingested file, it is written into
+// the file itself, which makes the files incompatible with
+// RocksDB versions older than 5.16. If set to false, it is only stored in
+// the MANIFEST.
+// Default to false.
+func (opts *IngestExternalFileOptions) SetWriteGlobalSeqno(flag bool) {
+	C.gorocksdb_ingestexternalfileoptions_set_write_global_seqno(opts.c, boolToChar(flag))
+}
+
+// SetVerifyFileChecksum sets verify_file_checksum. If set to true and the
+// files come with a file checksum, the checksum is verified against the
+// one computed on ingestion.
+// Default to true.
+func (opts *IngestExternalFileOptions) SetVerifyFileChecksum(flag bool) {
+	C.gorocksdb_ingestexternalfileoptions_set_verify_file_checksum(opts.c, boolToChar(flag))
+}
+
 // Destroy deallocates the IngestExternalFileOptions object.
 func (opts *IngestExternalFileOptions) Destroy() {
 	C.rocksdb_ingestexternalfileoptions_destroy(opts.c)
@@ -27,6 +27,7 @@ type ReadOptions struct {
 	c          *C.rocksdb_readoptions_t
 	upperBound *C.char
 	lowerBound *C.char
+	timestamp  *C.char
 }
 
 // NewDefaultReadOptions creates a default ReadOptions object.
@@ -131,6 +132,19 @@ func (opts *ReadOptions) SetIterateLowerBound(key []byte) {
 	}
 }
 
+// SetTimestamp specifies "timestamp", the timestamp to read at for a DB
+// using a comparator with timestamps, e.g. NewBytewiseU64TsComparator.
+// Only the versions written at or before it are visible.
+// Default: nullptr
+func (opts *ReadOptions) SetTimestamp(ts []byte) {
+	oldTs := opts.timestamp
+	opts.timestamp = cByteSlice(ts)
+	C.rocksdb_readoptions_set_timestamp(opts.c, opts.timestamp, C.size_t(len(ts)))
+	if oldTs != nil {
+		C.free(unsafe.Pointer(oldTs))
+	}
+}
+
 // SetPinData specifies the value of "pin_data". If true, it keeps the blocks
 // loaded by the iterator pinned in memory as long as the iterator is not deleted,
 // If used when reading from tables created with
@@ -184,4 +198,8 @@ func (opts *ReadOptions) Destroy() {
 		C.free(unsafe.Pointer(opts.lowerBound))
 		opts.lowerBound = nil
 	}
+	if opts.timestamp != nil {
+		C.free(unsafe.Pointer(opts.timestamp))
+		opts.timestamp = nil
+	}
 }
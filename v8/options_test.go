@@ -130,3 +130,35 @@ func TestReadOptionsIterateBounds(t *testing.T) {
 		ensure.DeepEqual(t, actualKeys, [][]byte{[]byte("b")})
 	})
 }
+
+func TestCompactRangeOptions(t *testing.T) {
+	opts := NewCompactRangeOptions()
+	defer opts.Destroy()
+
+	assert.True(t, opts.GetExclusiveManualCompaction())
+	opts.SetExclusiveManualCompaction(false)
+	assert.False(t, opts.GetExclusiveManualCompaction())
+
+	assert.False(t, opts.GetChangeLevel())
+	opts.SetChangeLevel(true)
+	assert.True(t, opts.GetChangeLevel())
+
+	assert.Equal(t, -1, opts.GetTargetLevel())
+	opts.SetTargetLevel(3)
+	assert.Equal(t, 3, opts.GetTargetLevel())
+
+	assert.Equal(t, uint32(0), opts.GetTargetPathID())
+	opts.SetTargetPathID(1)
+	assert.Equal(t, uint32(1), opts.GetTargetPathID())
+
+	assert.False(t, opts.GetAllowWriteStall())
+	opts.SetAllowWriteStall(true)
+	assert.True(t, opts.GetAllowWriteStall())
+
+	assert.Equal(t, uint32(0), opts.GetMaxSubcompactions())
+	opts.SetMaxSubcompactions(4)
+	assert.Equal(t, uint32(4), opts.GetMaxSubcompactions())
+
+	opts.SetFullHistoryTsLow([]byte{0, 0, 0, 0, 0, 0, 0, 1})
+	opts.SetFullHistoryTsLow([]byte{0, 0, 0, 0, 0, 0, 0, 2})
+}
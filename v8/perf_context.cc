@@ -0,0 +1,37 @@
+#include "gorocksdb_internal.h"
+#include "rocksdb/iostats_context.h"
+#include "rocksdb/perf_level.h"
+
+using rocksdb::IOStatsContext;
+
+extern "C" {
+
+int gorocksdb_get_perf_level() {
+  return static_cast<int>(rocksdb::GetPerfLevel());
+}
+
+void gorocksdb_iostats_context_get(gorocksdb_iostats_t* stats) {
+  const IOStatsContext* ctx = rocksdb::get_iostats_context();
+  stats->thread_pool_id = ctx->thread_pool_id;
+  stats->bytes_written = ctx->bytes_written;
+  stats->bytes_read = ctx->bytes_read;
+  stats->open_nanos = ctx->open_nanos;
+  stats->allocate_nanos = ctx->allocate_nanos;
+  stats->write_nanos = ctx->write_nanos;
+  stats->read_nanos = ctx->read_nanos;
+  stats->range_sync_nanos = ctx->range_sync_nanos;
+  stats->fsync_nanos = ctx->fsync_nanos;
+  stats->prepare_write_nanos = ctx->prepare_write_nanos;
+  stats->logger_nanos = ctx->logger_nanos;
+  stats->cpu_write_nanos = ctx->cpu_write_nanos;
+  stats->cpu_read_nanos = ctx->cpu_read_nanos;
+}
+
+void gorocksdb_iostats_context_reset() { rocksdb::get_iostats_context()->Reset(); }
+
+char* gorocksdb_iostats_context_report(unsigned char exclude_zero_counters) {
+  return gorocksdb::CopyString(
+      rocksdb::get_iostats_context()->ToString(exclude_zero_counters));
+}
+
+}  // extern "C"
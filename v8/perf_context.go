@@ -4,6 +4,8 @@ package gorocksdb
 // #include "rocksdb/c.h"
 import "C"
 import (
+	"reflect"
+	"runtime"
 	"unsafe"
 )
 
@@ -133,3 +135,216 @@ func (ctx *PerfContext) Metric(id int) uint64 {
 	value := C.rocksdb_perfcontext_metric(ctx.c, C.int(id))
 	return uint64(value)
 }
+
+// PerfContextSnapshot holds the values of the PerfContext counters, named as
+// in rocksdb::PerfContext. Times are in nanoseconds.
+type PerfContextSnapshot struct {
+	UserKeyComparisonCount            uint64
+	BlockCacheHitCount                uint64
+	BlockReadCount                    uint64
+	BlockReadByte                     uint64
+	BlockReadTime                     uint64
+	BlockChecksumTime                 uint64
+	BlockDecompressTime               uint64
+	GetReadBytes                      uint64
+	MultigetReadBytes                 uint64
+	IterReadBytes                     uint64
+	InternalKeySkippedCount           uint64
+	InternalDeleteSkippedCount        uint64
+	InternalRecentSkippedCount        uint64
+	InternalMergeCount                uint64
+	GetSnapshotTime                   uint64
+	GetFromMemtableTime               uint64
+	GetFromMemtableCount              uint64
+	GetPostProcessTime                uint64
+	GetFromOutputFilesTime            uint64
+	SeekOnMemtableTime                uint64
+	SeekOnMemtableCount               uint64
+	NextOnMemtableCount               uint64
+	PrevOnMemtableCount               uint64
+	SeekChildSeekTime                 uint64
+	SeekChildSeekCount                uint64
+	SeekMinHeapTime                   uint64
+	SeekMaxHeapTime                   uint64
+	SeekInternalSeekTime              uint64
+	FindNextUserEntryTime             uint64
+	WriteWalTime                      uint64
+	WriteMemtableTime                 uint64
+	WriteDelayTime                    uint64
+	WritePreAndPostProcessTime        uint64
+	DBMutexLockNanos                  uint64
+	DBConditionWaitNanos              uint64
+	MergeOperatorTimeNanos            uint64
+	ReadIndexBlockNanos               uint64
+	ReadFilterBlockNanos              uint64
+	NewTableBlockIterNanos            uint64
+	NewTableIteratorNanos             uint64
+	BlockSeekNanos                    uint64
+	FindTableNanos                    uint64
+	BloomMemtableHitCount             uint64
+	BloomMemtableMissCount            uint64
+	BloomSstHitCount                  uint64
+	BloomSstMissCount                 uint64
+	KeyLockWaitTime                   uint64
+	KeyLockWaitCount                  uint64
+	EnvNewSequentialFileNanos         uint64
+	EnvNewRandomAccessFileNanos       uint64
+	EnvNewWritableFileNanos           uint64
+	EnvReuseWritableFileNanos         uint64
+	EnvNewRandomRWFileNanos           uint64
+	EnvNewDirectoryNanos              uint64
+	EnvFileExistsNanos                uint64
+	EnvGetChildrenNanos               uint64
+	EnvGetChildrenFileAttributesNanos uint64
+	EnvDeleteFileNanos                uint64
+	EnvCreateDirNanos                 uint64
+	EnvCreateDirIfMissingNanos        uint64
+	EnvDeleteDirNanos                 uint64
+	EnvGetFileSizeNanos               uint64
+	EnvGetFileModificationTimeNanos   uint64
+	EnvRenameFileNanos                uint64
+	EnvLinkFileNanos                  uint64
+	EnvLockFileNanos                  uint64
+	EnvUnlockFileNanos                uint64
+	EnvNewLoggerNanos                 uint64
+	NumberAsyncSeek                   uint64
+	BlobCacheHitCount                 uint64
+	BlobReadCount                     uint64
+	BlobReadByte                      uint64
+	BlobReadTime                      uint64
+	BlobChecksumTime                  uint64
+	BlobDecompressTime                uint64
+	InternalRangeDelReseekCount       uint64
+	BlockReadCPUTime                  uint64
+	InternalMergePointLookupCount     uint64
+	DataBlockReadByte                 uint64
+	IndexBlockReadByte                uint64
+	FilterBlockReadByte               uint64
+	CompressionDictBlockReadByte      uint64
+	MetadataBlockReadByte             uint64
+}
+
+// Snapshot returns the current values of all the counters.
+func (ctx *PerfContext) Snapshot() PerfContextSnapshot {
+	m := func(id C.int) uint64 { return uint64(C.rocksdb_perfcontext_metric(ctx.c, id)) }
+	return PerfContextSnapshot{
+		UserKeyComparisonCount:            m(C.rocksdb_user_key_comparison_count),
+		BlockCacheHitCount:                m(C.rocksdb_block_cache_hit_count),
+		BlockReadCount:                    m(C.rocksdb_block_read_count),
+		BlockReadByte:                     m(C.rocksdb_block_read_byte),
+		BlockReadTime:                     m(C.rocksdb_block_read_time),
+		BlockChecksumTime:                 m(C.rocksdb_block_checksum_time),
+		BlockDecompressTime:               m(C.rocksdb_block_decompress_time),
+		GetReadBytes:                      m(C.rocksdb_get_read_bytes),
+		MultigetReadBytes:                 m(C.rocksdb_multiget_read_bytes),
+		IterReadBytes:                     m(C.rocksdb_iter_read_bytes),
+		InternalKeySkippedCount:           m(C.rocksdb_internal_key_skipped_count),
+		InternalDeleteSkippedCount:        m(C.rocksdb_internal_delete_skipped_count),
+		InternalRecentSkippedCount:        m(C.rocksdb_internal_recent_skipped_count),
+		InternalMergeCount:                m(C.rocksdb_internal_merge_count),
+		GetSnapshotTime:                   m(C.rocksdb_get_snapshot_time),
+		GetFromMemtableTime:               m(C.rocksdb_get_from_memtable_time),
+		GetFromMemtableCount:              m(C.rocksdb_get_from_memtable_count),
+		GetPostProcessTime:                m(C.rocksdb_get_post_process_time),
+		GetFromOutputFilesTime:            m(C.rocksdb_get_from_output_files_time),
+		SeekOnMemtableTime:                m(C.rocksdb_seek_on_memtable_time),
+		SeekOnMemtableCount:               m(C.rocksdb_seek_on_memtable_count),
+		NextOnMemtableCount:               m(C.rocksdb_next_on_memtable_count),
+		PrevOnMemtableCount:               m(C.rocksdb_prev_on_memtable_count),
+		SeekChildSeekTime:                 m(C.rocksdb_seek_child_seek_time),
+		SeekChildSeekCount:                m(C.rocksdb_seek_child_seek_count),
+		SeekMinHeapTime:                   m(C.rocksdb_seek_min_heap_time),
+		SeekMaxHeapTime:                   m(C.rocksdb_seek_max_heap_time),
+		SeekInternalSeekTime:              m(C.rocksdb_seek_internal_seek_time),
+		FindNextUserEntryTime:             m(C.rocksdb_find_next_user_entry_time),
+		WriteWalTime:                      m(C.rocksdb_write_wal_time),
+		WriteMemtableTime:                 m(C.rocksdb_write_memtable_time),
+		WriteDelayTime:                    m(C.rocksdb_write_delay_time),
+		WritePreAndPostProcessTime:        m(C.rocksdb_write_pre_and_post_process_time),
+		DBMutexLockNanos:                  m(C.rocksdb_db_mutex_lock_nanos),
+		DBConditionWaitNanos:              m(C.rocksdb_db_condition_wait_nanos),
+		MergeOperatorTimeNanos:            m(C.rocksdb_merge_operator_time_nanos),
+		ReadIndexBlockNanos:               m(C.rocksdb_read_index_block_nanos),
+		ReadFilterBlockNanos:              m(C.rocksdb_read_filter_block_nanos),
+		NewTableBlockIterNanos:            m(C.rocksdb_new_table_block_iter_nanos),
+		NewTableIteratorNanos:             m(C.rocksdb_new_table_iterator_nanos),
+		BlockSeekNanos:                    m(C.rocksdb_block_seek_nanos),
+		FindTableNanos:                    m(C.rocksdb_find_table_nanos),
+		BloomMemtableHitCount:             m(C.rocksdb_bloom_memtable_hit_count),
+		BloomMemtableMissCount:            m(C.rocksdb_bloom_memtable_miss_count),
+		BloomSstHitCount:                  m(C.rocksdb_bloom_sst_hit_count),
+		BloomSstMissCount:                 m(C.rocksdb_bloom_sst_miss_count),
+		KeyLockWaitTime:                   m(C.rocksdb_key_lock_wait_time),
+		KeyLockWaitCount:                  m(C.rocksdb_key_lock_wait_count),
+		EnvNewSequentialFileNanos:         m(C.rocksdb_env_new_sequential_file_nanos),
+		EnvNewRandomAccessFileNanos:       m(C.rocksdb_env_new_random_access_file_nanos),
+		EnvNewWritableFileNanos:           m(C.rocksdb_env_new_writable_file_nanos),
+		EnvReuseWritableFileNanos:         m(C.rocksdb_env_reuse_writable_file_nanos),
+		EnvNewRandomRWFileNanos:           m(C.rocksdb_env_new_random_rw_file_nanos),
+		EnvNewDirectoryNanos:              m(C.rocksdb_env_new_directory_nanos),
+		EnvFileExistsNanos:                m(C.rocksdb_env_file_exists_nanos),
+		EnvGetChildrenNanos:               m(C.rocksdb_env_get_children_nanos),
+		EnvGetChildrenFileAttributesNanos: m(C.rocksdb_env_get_children_file_attributes_nanos),
+		EnvDeleteFileNanos:                m(C.rocksdb_env_delete_file_nanos),
+		EnvCreateDirNanos:                 m(C.rocksdb_env_create_dir_nanos),
+		EnvCreateDirIfMissingNanos:        m(C.rocksdb_env_create_dir_if_missing_nanos),
+		EnvDeleteDirNanos:                 m(C.rocksdb_env_delete_dir_nanos),
+		EnvGetFileSizeNanos:               m(C.rocksdb_env_get_file_size_nanos),
+		EnvGetFileModificationTimeNanos:   m(C.rocksdb_env_get_file_modification_time_nanos),
+		EnvRenameFileNanos:                m(C.rocksdb_env_rename_file_nanos),
+		EnvLinkFileNanos:                  m(C.rocksdb_env_link_file_nanos),
+		EnvLockFileNanos:                  m(C.rocksdb_env_lock_file_nanos),
+		EnvUnlockFileNanos:                m(C.rocksdb_env_unlock_file_nanos),
+		EnvNewLoggerNanos:                 m(C.rocksdb_env_new_logger_nanos),
+		NumberAsyncSeek:                   m(C.rocksdb_number_async_seek),
+		BlobCacheHitCount:                 m(C.rocksdb_blob_cache_hit_count),
+		BlobReadCount:                     m(C.rocksdb_blob_read_count),
+		BlobReadByte:                      m(C.rocksdb_blob_read_byte),
+		BlobReadTime:                      m(C.rocksdb_blob_read_time),
+		BlobChecksumTime:                  m(C.rocksdb_blob_checksum_time),
+		BlobDecompressTime:                m(C.rocksdb_blob_decompress_time),
+		InternalRangeDelReseekCount:       m(C.rocksdb_internal_range_del_reseek_count),
+		BlockReadCPUTime:                  m(C.rocksdb_block_read_cpu_time),
+		InternalMergePointLookupCount:     m(C.rocksdb_internal_merge_point_lookup_count),
+		DataBlockReadByte:                 m(C.rocksdb_data_block_read_byte),
+		IndexBlockReadByte:                m(C.rocksdb_index_block_read_byte),
+		FilterBlockReadByte:               m(C.rocksdb_filter_block_read_byte),
+		CompressionDictBlockReadByte:      m(C.rocksdb_compression_dict_block_read_byte),
+		MetadataBlockReadByte:             m(C.rocksdb_metadata_block_read_byte),
+	}
+}
+
+// Sub returns the counters accumulated since before was taken.
+func (s PerfContextSnapshot) Sub(before PerfContextSnapshot) PerfContextSnapshot {
+	return subCounters(s, before).(PerfContextSnapshot)
+}
+
+// subCounters subtracts the uint64 fields of the structs a and b.
+func subCounters(a, b interface{}) interface{} {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	result := reflect.New(va.Type()).Elem()
+	for i := 0; i < va.NumField(); i++ {
+		result.Field(i).SetUint(va.Field(i).Uint() - vb.Field(i).Uint())
+	}
+	return result.Interface()
+}
+
+// MeasurePerf runs fn with the perf level set to level and returns the perf
+// and IO stats counters accumulated by the RocksDB operations it runs.
+//
+// These contexts are thread-local, so the goroutine is locked to its OS
+// thread while fn runs; fn must run the operations to measure on the calling
+// goroutine. The previous perf level is restored afterwards.
+func MeasurePerf(level PerfLevel, fn func()) (PerfContextSnapshot, IOStatsSnapshot) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	defer SetPerfLevel(GetPerfLevel())
+	SetPerfLevel(level)
+	ctx := NewPerfContext()
+	defer ctx.Destroy()
+	ioStats := NewIOStatsContext()
+	perfBefore, ioBefore := ctx.Snapshot(), ioStats.Snapshot()
+	fn()
+	return ctx.Snapshot().Sub(perfBefore), ioStats.Snapshot().Sub(ioBefore)
+}
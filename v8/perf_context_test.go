@@ -2,6 +2,7 @@ package gorocksdb
 
 import (
 	"fmt"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -103,3 +104,64 @@ func TestPerfContextTimingMetrics(t *testing.T) {
 	ensure.True(t, strings.Contains(report, "write_wal_time"))
 	ensure.True(t, strings.Contains(report, "get_from_memtable_time"))
 }
+
+func TestMeasurePerf(t *testing.T) {
+	db := newTestDB(t, "TestMeasurePerf", nil)
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	ro := NewDefaultReadOptions()
+	defer ro.Destroy()
+	fo := NewDefaultFlushOptions()
+	defer fo.Destroy()
+	ensure.Nil(t, db.Put(wo, []byte("key"), []byte("value")))
+	ensure.Nil(t, db.Flush(fo))
+
+	// The perf level is per thread, so stay on this one to check that
+	// MeasurePerf restores it.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	defer SetPerfLevel(GetPerfLevel())
+	SetPerfLevel(KEnableCount)
+
+	perf, ioStats := MeasurePerf(KEnableTime, func() {
+		for i := 0; i < 10; i++ {
+			v, err := db.GetBytes(ro, []byte("key"))
+			ensure.Nil(t, err)
+			ensure.DeepEqual(t, v, []byte("value"))
+		}
+	})
+	ensure.True(t, perf.GetReadBytes >= 10*uint64(len("value")))
+	ensure.True(t, perf.GetFromOutputFilesTime > 0)
+	ensure.True(t, perf.BlockReadCount > 0)
+	ensure.True(t, perf.DataBlockReadByte > 0)
+	ensure.True(t, ioStats.BytesRead > 0)
+	ensure.DeepEqual(t, GetPerfLevel(), KEnableCount)
+
+	// The counters of the operations run outside of the measured region
+	// are excluded.
+	perf, _ = MeasurePerf(KEnableCount, func() {})
+	ensure.DeepEqual(t, perf, PerfContextSnapshot{})
+}
+
+func TestIOStatsContext(t *testing.T) {
+	db := newTestDB(t, "TestIOStatsContext", nil)
+	defer db.Close()
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	SetPerfLevel(KEnableTime)
+	defer SetPerfLevel(KDisable)
+
+	ctx := NewIOStatsContext()
+	ctx.Reset()
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	ensure.Nil(t, db.Put(wo, []byte("key"), []byte("value")))
+	ensure.True(t, ctx.Snapshot().BytesWritten > 0)
+	ensure.True(t, strings.Contains(ctx.Report(true), "bytes_written"))
+
+	ctx.Reset()
+	ensure.DeepEqual(t, ctx.Snapshot().BytesWritten, uint64(0))
+}
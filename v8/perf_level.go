@@ -1,6 +1,7 @@
 package gorocksdb
 
 // #include "rocksdb/c.h"
+// #include "gorocksdb.h"
 import "C"
 
 // PerfLevel indicates how much perf stats to collect. Affects perf_context and iostats_context.
@@ -30,3 +31,8 @@ const (
 func SetPerfLevel(level PerfLevel) {
 	C.rocksdb_set_perf_level(C.int(level))
 }
+
+// GetPerfLevel returns the perf stats level of the current thread.
+func GetPerfLevel() PerfLevel {
+	return PerfLevel(C.gorocksdb_get_perf_level())
+}
@@ -0,0 +1,50 @@
+#include "gorocksdb_internal.h"
+
+using rocksdb::Env;
+using rocksdb::RateLimiter;
+
+namespace {
+
+RateLimiter* Rep(rocksdb_ratelimiter_t* limiter) { return limiter->rep.get(); }
+
+}  // namespace
+
+extern "C" {
+
+rocksdb_ratelimiter_t* gorocksdb_ratelimiter_create_with_mode(
+    int64_t rate_bytes_per_sec, int64_t refill_period_us, int32_t fairness,
+    int mode, unsigned char auto_tuned) {
+  auto limiter = new rocksdb_ratelimiter_t;
+  limiter->rep.reset(rocksdb::NewGenericRateLimiter(
+      rate_bytes_per_sec, refill_period_us, fairness,
+      static_cast<RateLimiter::Mode>(mode), auto_tuned));
+  return limiter;
+}
+
+void gorocksdb_ratelimiter_set_bytes_per_second(rocksdb_ratelimiter_t* limiter,
+                                                int64_t bytes_per_second) {
+  Rep(limiter)->SetBytesPerSecond(bytes_per_second);
+}
+
+int64_t gorocksdb_ratelimiter_get_bytes_per_second(
+    rocksdb_ratelimiter_t* limiter) {
+  return Rep(limiter)->GetBytesPerSecond();
+}
+
+int64_t gorocksdb_ratelimiter_get_single_burst_bytes(
+    rocksdb_ratelimiter_t* limiter) {
+  return Rep(limiter)->GetSingleBurstBytes();
+}
+
+int64_t gorocksdb_ratelimiter_get_total_bytes_through(
+    rocksdb_ratelimiter_t* limiter, int priority) {
+  return Rep(limiter)->GetTotalBytesThrough(
+      static_cast<Env::IOPriority>(priority));
+}
+
+int64_t gorocksdb_ratelimiter_get_total_requests(rocksdb_ratelimiter_t* limiter,
+                                                 int priority) {
+  return Rep(limiter)->GetTotalRequests(static_cast<Env::IOPriority>(priority));
+}
+
+}  // extern "C"
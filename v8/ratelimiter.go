@@ -2,8 +2,32 @@ package gorocksdb
 
 // #include <stdlib.h>
 // #include "rocksdb/c.h"
+// #include "gorocksdb.h"
 import "C"
 
+// RateLimiterMode selects the IO that is rate limited.
+type RateLimiterMode int
+
+// Rate limiter modes.
+const (
+	RateLimiterReadsOnly  = RateLimiterMode(0)
+	RateLimiterWritesOnly = RateLimiterMode(1)
+	RateLimiterAllIo      = RateLimiterMode(2)
+)
+
+// IOPriority is the priority of an IO request.
+type IOPriority int
+
+// IO priorities.
+const (
+	IOPriorityLow  = IOPriority(0)
+	IOPriorityMid  = IOPriority(1)
+	IOPriorityHigh = IOPriority(2)
+	IOPriorityUser = IOPriority(3)
+	// IOPriorityTotal sums up all the priorities.
+	IOPriorityTotal = IOPriority(4)
+)
+
 // RateLimiter, is used to control write rate of flush and
 // compaction.
 type RateLimiter struct {
@@ -19,11 +43,63 @@ func NewRateLimiter(rate_bytes_per_sec, refill_period_us int64, fairness int32)
 	))
 }
 
+// NewAutoTunedRateLimiter creates a RateLimiter that adjusts its rate to the
+// demand, within [rate_bytes_per_sec / 20, rate_bytes_per_sec].
+func NewAutoTunedRateLimiter(rate_bytes_per_sec, refill_period_us int64, fairness int32) *RateLimiter {
+	return NewNativeRateLimiter(C.rocksdb_ratelimiter_create_auto_tuned(
+		C.int64_t(rate_bytes_per_sec),
+		C.int64_t(refill_period_us),
+		C.int32_t(fairness),
+	))
+}
+
+// NewRateLimiterWithMode creates a RateLimiter limiting the IO selected by
+// mode. If autoTuned is true, the rate adjusts to the demand like with
+// NewAutoTunedRateLimiter.
+func NewRateLimiterWithMode(rate_bytes_per_sec, refill_period_us int64, fairness int32, mode RateLimiterMode, autoTuned bool) *RateLimiter {
+	return NewNativeRateLimiter(C.gorocksdb_ratelimiter_create_with_mode(
+		C.int64_t(rate_bytes_per_sec),
+		C.int64_t(refill_period_us),
+		C.int32_t(fairness),
+		C.int(mode),
+		boolToChar(autoTuned),
+	))
+}
+
 // NewNativeRateLimiter creates a native RateLimiter object.
 func NewNativeRateLimiter(c *C.rocksdb_ratelimiter_t) *RateLimiter {
 	return &RateLimiter{c}
 }
 
+// SetBytesPerSecond changes the rate. It can be called while the RateLimiter
+// is in use. For an auto-tuned RateLimiter, it sets the upper bound of the rate.
+func (self *RateLimiter) SetBytesPerSecond(bytesPerSecond int64) {
+	C.gorocksdb_ratelimiter_set_bytes_per_second(self.c, C.int64_t(bytesPerSecond))
+}
+
+// GetBytesPerSecond returns the current rate.
+func (self *RateLimiter) GetBytesPerSecond() int64 {
+	return int64(C.gorocksdb_ratelimiter_get_bytes_per_second(self.c))
+}
+
+// GetSingleBurstBytes returns the maximum number of bytes that can be
+// granted in a single request.
+func (self *RateLimiter) GetSingleBurstBytes() int64 {
+	return int64(C.gorocksdb_ratelimiter_get_single_burst_bytes(self.c))
+}
+
+// GetTotalBytesThrough returns the total number of bytes that went through
+// the RateLimiter with the given priority.
+func (self *RateLimiter) GetTotalBytesThrough(pri IOPriority) int64 {
+	return int64(C.gorocksdb_ratelimiter_get_total_bytes_through(self.c, C.int(pri)))
+}
+
+// GetTotalRequests returns the total number of requests that went through
+// the RateLimiter with the given priority.
+func (self *RateLimiter) GetTotalRequests(pri IOPriority) int64 {
+	return int64(C.gorocksdb_ratelimiter_get_total_requests(self.c, C.int(pri)))
+}
+
 // Destroy deallocates the RateLimiter object.
 func (self *RateLimiter) Destroy() {
 	C.rocksdb_ratelimiter_destroy(self.c)
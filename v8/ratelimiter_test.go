@@ -0,0 +1,35 @@
+package gorocksdb
+
+import (
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter := NewRateLimiterWithMode(1<<20, 100*1000, 10, RateLimiterWritesOnly, false)
+	defer limiter.Destroy()
+	ensure.DeepEqual(t, limiter.GetBytesPerSecond(), int64(1<<20))
+	limiter.SetBytesPerSecond(1 << 30)
+	ensure.DeepEqual(t, limiter.GetBytesPerSecond(), int64(1<<30))
+
+	db := newTestDB(t, "TestRateLimiter", func(opts *Options) {
+		opts.SetRateLimiter(limiter)
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	ensure.Nil(t, db.Put(wo, []byte("key"), []byte("val")))
+	ensure.Nil(t, db.Flush(NewDefaultFlushOptions()))
+
+	ensure.True(t, limiter.GetTotalBytesThrough(IOPriorityTotal) > 0)
+	ensure.True(t, limiter.GetTotalRequests(IOPriorityTotal) > 0)
+	ensure.True(t, limiter.GetTotalRequests(IOPriorityTotal) >= limiter.GetTotalRequests(IOPriorityHigh))
+}
+
+func TestAutoTunedRateLimiter(t *testing.T) {
+	limiter := NewAutoTunedRateLimiter(1<<30, 100*1000, 10)
+	defer limiter.Destroy()
+	ensure.True(t, limiter.GetBytesPerSecond() <= 1<<30)
+	ensure.True(t, limiter.GetSingleBurstBytes() > 0)
+}
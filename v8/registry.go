@@ -0,0 +1,42 @@
+package gorocksdb
+
+import "sync"
+
+// registry holds references to Go objects handed to C by handle. Unlike
+// COWList, it is meant for short-lived objects: a handle is released once
+// C no longer needs the object. Handle 0 is never used.
+type registry struct {
+	mu    sync.RWMutex
+	next  uintptr
+	items map[uintptr]interface{}
+}
+
+// newRegistry creates a new registry.
+func newRegistry() *registry {
+	return &registry{items: make(map[uintptr]interface{})}
+}
+
+// register adds an item to the registry and returns its handle.
+func (r *registry) register(i interface{}) uintptr {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.next++
+	r.items[r.next] = i
+	return r.next
+}
+
+// get returns the item of handle h.
+func (r *registry) get(h uintptr) interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.items[h]
+}
+
+// release removes the item of handle h from the registry and returns it.
+func (r *registry) release(h uintptr) interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.items[h]
+	delete(r.items, h)
+	return i
+}
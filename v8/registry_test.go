@@ -0,0 +1,19 @@
+package gorocksdb
+
+import (
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestRegistry(t *testing.T) {
+	r := newRegistry()
+	h1 := r.register("a")
+	h2 := r.register("b")
+	ensure.True(t, h1 != 0)
+	ensure.True(t, h1 != h2)
+	ensure.DeepEqual(t, r.get(h1), "a")
+	ensure.DeepEqual(t, r.release(h1), "a")
+	ensure.True(t, r.get(h1) == nil)
+	ensure.DeepEqual(t, r.get(h2), "b")
+}
@@ -0,0 +1,394 @@
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+// #include "gorocksdb.h"
+import "C"
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// TraceRecordType is the type of an operation recorded in a trace.
+type TraceRecordType int
+
+// The values are taken from the TraceType enum of RocksDB.
+var (
+	TraceWrite               = TraceRecordType(C.gorocksdb_trace_type_write())
+	TraceGet                 = TraceRecordType(C.gorocksdb_trace_type_get())
+	TraceIteratorSeek        = TraceRecordType(C.gorocksdb_trace_type_iterator_seek())
+	TraceIteratorSeekForPrev = TraceRecordType(C.gorocksdb_trace_type_iterator_seek_for_prev())
+	TraceMultiGet            = TraceRecordType(C.gorocksdb_trace_type_multiget())
+)
+
+// String returns the name of the record type.
+func (t TraceRecordType) String() string {
+	switch t {
+	case TraceWrite:
+		return "Write"
+	case TraceGet:
+		return "Get"
+	case TraceIteratorSeek:
+		return "IteratorSeek"
+	case TraceIteratorSeekForPrev:
+		return "IteratorSeekForPrev"
+	case TraceMultiGet:
+		return "MultiGet"
+	}
+	return "Unknown"
+}
+
+// statusNotFound is the code of the NotFound RocksDB status.
+const statusNotFound = 1
+
+// ReplayOptions are the options of Replayer.Replay.
+type ReplayOptions struct {
+	// Speed scales the pace of the traced workload: 1 replays it at its
+	// original pace, 2 twice as fast. 0 replays it as fast as possible.
+	Speed float64
+	// Concurrency is the number of records executed at the same time. The
+	// records are executed in order only if it is 1.
+	Concurrency int
+	// OnMismatch, if not nil, is called for each read whose results differ
+	// between the DB and the reference DB.
+	OnMismatch func(mismatch *ReplayMismatch)
+}
+
+// NewDefaultReplayOptions returns the default replay options, which replay
+// the records one at a time at their original pace.
+func NewDefaultReplayOptions() ReplayOptions {
+	return ReplayOptions{Speed: 1, Concurrency: 1}
+}
+
+// ReplayOutput is an output of a replayed read: the value of a key read by
+// Get or MultiGet, or the entry an iterator is positioned at.
+type ReplayOutput struct {
+	Found bool
+	// Key is the key of the entry of an iterator.
+	Key   []byte
+	Value []byte
+	Err   error
+}
+
+func (o ReplayOutput) equal(other ReplayOutput) bool {
+	if (o.Err == nil) != (other.Err == nil) || o.Err != nil && o.Err.Error() != other.Err.Error() {
+		return false
+	}
+	return o.Found == other.Found && bytes.Equal(o.Key, other.Key) && bytes.Equal(o.Value, other.Value)
+}
+
+// ReplayMismatch is a read whose results differ between the DB and the
+// reference DB.
+type ReplayMismatch struct {
+	Type TraceRecordType
+	// Timestamp is the time of the traced operation, in microseconds.
+	Timestamp uint64
+	Keys      [][]byte
+	// Got are the outputs of the read on the DB, Want on the reference DB.
+	Got, Want []ReplayOutput
+}
+
+// ReplayOpStats are the statistics of the replayed operations of a type.
+type ReplayOpStats struct {
+	Count      int
+	Errors     int
+	Mismatches int
+
+	latencies []time.Duration
+	sorted    bool
+}
+
+// Latency returns the latency below which the given percentage of the
+// operations completed, e.g. 99 for the 99th percentile.
+func (s *ReplayOpStats) Latency(percentile float64) time.Duration {
+	if len(s.latencies) == 0 {
+		return 0
+	}
+	if !s.sorted {
+		sort.Slice(s.latencies, func(i, j int) bool { return s.latencies[i] < s.latencies[j] })
+		s.sorted = true
+	}
+	i := int(percentile / 100 * float64(len(s.latencies)))
+	if i >= len(s.latencies) {
+		i = len(s.latencies) - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+	return s.latencies[i]
+}
+
+// MeanLatency returns the mean latency of the operations.
+func (s *ReplayOpStats) MeanLatency() time.Duration {
+	if len(s.latencies) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, l := range s.latencies {
+		total += l
+	}
+	return total / time.Duration(len(s.latencies))
+}
+
+// ReplayReport is the outcome of a replay.
+type ReplayReport struct {
+	Records    int
+	Errors     int
+	Mismatches int
+	// FirstError is the first error returned by a replayed operation.
+	FirstError error
+	Duration   time.Duration
+	Ops        map[TraceRecordType]*ReplayOpStats
+}
+
+// Replayer replays the operations of a trace file recorded by
+// DB.StartTrace against a database, and reports their latency. When a
+// reference database is set, the reads are also replayed against it and
+// their results compared.
+type Replayer struct {
+	c    *C.gorocksdb_replayer_t
+	ref  *C.gorocksdb_replayer_t
+	path string
+}
+
+// NewReplayer creates a Replayer of the trace file at path against db.
+// The column families of the traced database are mapped by ID to cfs; a
+// nil cfs maps only the default column family.
+func NewReplayer(db *DB, cfs []*ColumnFamilyHandle, path string) (*Replayer, error) {
+	c, err := newNativeReplayer(db, cfs, path)
+	if err != nil {
+		return nil, err
+	}
+	return &Replayer{c: c, path: path}, nil
+}
+
+func newNativeReplayer(db *DB, cfs []*ColumnFamilyHandle, path string) (*C.gorocksdb_replayer_t, error) {
+	var (
+		cErr  *C.char
+		cPath = C.CString(path)
+		cCFs  = ColumnFamilyHandles(cfs).toCSlice()
+		cCF   **C.rocksdb_column_family_handle_t
+	)
+	defer C.free(unsafe.Pointer(cPath))
+	if len(cCFs) > 0 {
+		cCF = &cCFs[0]
+	}
+	c := C.gorocksdb_replayer_create(db.c, cCF, C.size_t(len(cCFs)), cPath, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return nil, errors.New(C.GoString(cErr))
+	}
+	return c, nil
+}
+
+// SetReference sets the database the results of the reads are compared
+// with. The writes of the trace are replayed against it too, so both
+// databases should start from the same state, e.g. two checkpoints of the
+// traced database. The results are only reliable with a Concurrency of 1
+// if the trace contains writes.
+func (r *Replayer) SetReference(db *DB, cfs []*ColumnFamilyHandle) error {
+	ref, err := newNativeReplayer(db, cfs, r.path)
+	if err != nil {
+		return err
+	}
+	if r.ref != nil {
+		C.gorocksdb_replayer_destroy(r.ref)
+	}
+	r.ref = ref
+	return nil
+}
+
+// Replay replays the trace from its beginning. It returns an error only if
+// the trace cannot be read; the errors of the replayed operations are
+// counted in the report.
+func (r *Replayer) Replay(opts ReplayOptions) (*ReplayReport, error) {
+	if err := prepareReplayer(r.c); err != nil {
+		return nil, err
+	}
+	if r.ref != nil {
+		if err := prepareReplayer(r.ref); err != nil {
+			return nil, err
+		}
+	}
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		report = &ReplayReport{Ops: make(map[TraceRecordType]*ReplayOpStats)}
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		sem    = make(chan struct{}, concurrency)
+		header = uint64(C.gorocksdb_replayer_header_timestamp(r.c))
+		start  = time.Now()
+	)
+	var err error
+	for {
+		var cErr *C.char
+		record := C.gorocksdb_replayer_next(r.c, &cErr)
+		if cErr != nil {
+			err = errors.New(C.GoString(cErr))
+			C.rocksdb_free(unsafe.Pointer(cErr))
+			break
+		}
+		if record == nil {
+			break
+		}
+		if ts := uint64(C.gorocksdb_trace_record_timestamp(record)); opts.Speed > 0 && ts > header {
+			due := time.Duration(float64(ts-header) * float64(time.Microsecond) / opts.Speed)
+			if wait := due - time.Since(start); wait > 0 {
+				time.Sleep(wait)
+			}
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				C.gorocksdb_trace_record_destroy(record)
+				<-sem
+				wg.Done()
+			}()
+			r.execute(record, opts, report, &mu)
+		}()
+	}
+	wg.Wait()
+	report.Duration = time.Since(start)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func prepareReplayer(c *C.gorocksdb_replayer_t) error {
+	var cErr *C.char
+	C.gorocksdb_replayer_prepare(c, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
+
+// execute replays a record and adds its outcome to report.
+func (r *Replayer) execute(record *C.gorocksdb_trace_record_t, opts ReplayOptions, report *ReplayReport, mu *sync.Mutex) {
+	typ := TraceRecordType(C.gorocksdb_trace_record_type(record))
+	start := time.Now()
+	got, err := executeRecord(r.c, record)
+	latency := time.Since(start)
+
+	var mismatch *ReplayMismatch
+	if r.ref != nil {
+		want, refErr := executeRecord(r.ref, record)
+		if typ != TraceWrite && !outputsEqual(got, want, err, refErr) {
+			mismatch = &ReplayMismatch{
+				Type:      typ,
+				Timestamp: uint64(C.gorocksdb_trace_record_timestamp(record)),
+				Keys:      recordKeys(record),
+				Got:       got,
+				Want:      want,
+			}
+		}
+	}
+	if err == nil {
+		for _, out := range got {
+			if out.Err != nil {
+				err = out.Err
+				break
+			}
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	stats := report.Ops[typ]
+	if stats == nil {
+		stats = &ReplayOpStats{}
+		report.Ops[typ] = stats
+	}
+	report.Records++
+	stats.Count++
+	stats.latencies = append(stats.latencies, latency)
+	stats.sorted = false
+	if err != nil {
+		report.Errors++
+		stats.Errors++
+		if report.FirstError == nil {
+			report.FirstError = err
+		}
+	}
+	if mismatch != nil {
+		report.Mismatches++
+		stats.Mismatches++
+		if opts.OnMismatch != nil {
+			opts.OnMismatch(mismatch)
+		}
+	}
+}
+
+// executeRecord executes a record and returns its outputs.
+func executeRecord(c *C.gorocksdb_replayer_t, record *C.gorocksdb_trace_record_t) ([]ReplayOutput, error) {
+	var cErr *C.char
+	result := C.gorocksdb_replayer_execute(c, record, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return nil, errors.New(C.GoString(cErr))
+	}
+	defer C.gorocksdb_trace_result_destroy(result)
+	outputs := make([]ReplayOutput, int(C.gorocksdb_trace_result_count(result)))
+	for i := range outputs {
+		var (
+			cKey, cValue       *C.char
+			cKeyLen, cValueLen C.size_t
+			cOutErr            *C.char
+		)
+		code := C.gorocksdb_trace_result_get(result, C.size_t(i), &cKey, &cKeyLen, &cValue, &cValueLen, &cOutErr)
+		if cOutErr != nil {
+			if code != statusNotFound {
+				outputs[i].Err = errors.New(C.GoString(cOutErr))
+			}
+			C.rocksdb_free(unsafe.Pointer(cOutErr))
+			continue
+		}
+		outputs[i].Found = true
+		outputs[i].Key = C.GoBytes(unsafe.Pointer(cKey), C.int(cKeyLen))
+		outputs[i].Value = C.GoBytes(unsafe.Pointer(cValue), C.int(cValueLen))
+	}
+	return outputs, nil
+}
+
+func outputsEqual(got, want []ReplayOutput, err, refErr error) bool {
+	if (err == nil) != (refErr == nil) || len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if !got[i].equal(want[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func recordKeys(record *C.gorocksdb_trace_record_t) [][]byte {
+	keys := make([][]byte, int(C.gorocksdb_trace_record_num_keys(record)))
+	for i := range keys {
+		var cLen C.size_t
+		cKey := C.gorocksdb_trace_record_key(record, C.size_t(i), &cLen)
+		keys[i] = C.GoBytes(unsafe.Pointer(cKey), C.int(cLen))
+	}
+	return keys
+}
+
+// Destroy deallocates the Replayer.
+func (r *Replayer) Destroy() {
+	C.gorocksdb_replayer_destroy(r.c)
+	r.c = nil
+	if r.ref != nil {
+		C.gorocksdb_replayer_destroy(r.ref)
+		r.ref = nil
+	}
+}
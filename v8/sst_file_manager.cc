@@ -0,0 +1,92 @@
+#include "gorocksdb_internal.h"
+#include "rocksdb/env.h"
+#include "rocksdb/sst_file_manager.h"
+
+using rocksdb::Env;
+using rocksdb::Options;
+using rocksdb::SstFileManager;
+
+// gorocksdb_sstfilemanager_t holds an SstFileManager shared by the options
+// it is set on.
+struct gorocksdb_sstfilemanager_t {
+  std::shared_ptr<SstFileManager> rep;
+};
+
+extern "C" {
+
+gorocksdb_sstfilemanager_t* gorocksdb_sstfilemanager_create(
+    rocksdb_env_t* env, int64_t delete_rate_bytes_per_sec,
+    unsigned char delete_existing_trash, double max_trash_db_ratio,
+    uint64_t bytes_max_delete_chunk, char** errptr) {
+  rocksdb::Status s;
+  std::shared_ptr<SstFileManager> sfm(rocksdb::NewSstFileManager(
+      env != nullptr ? gorocksdb::Rep<Env>(env) : Env::Default(), nullptr, "",
+      delete_rate_bytes_per_sec, delete_existing_trash, &s, max_trash_db_ratio,
+      bytes_max_delete_chunk));
+  if (gorocksdb::SaveError(errptr, s)) {
+    return nullptr;
+  }
+  return new gorocksdb_sstfilemanager_t{std::move(sfm)};
+}
+
+void gorocksdb_sstfilemanager_destroy(gorocksdb_sstfilemanager_t* sfm) {
+  delete sfm;
+}
+
+void gorocksdb_options_set_sst_file_manager(rocksdb_options_t* opts,
+                                            gorocksdb_sstfilemanager_t* sfm) {
+  gorocksdb::RepValue<Options>(opts).sst_file_manager = sfm->rep;
+}
+
+void gorocksdb_sstfilemanager_set_max_allowed_space_usage(
+    gorocksdb_sstfilemanager_t* sfm, uint64_t max_allowed_space) {
+  sfm->rep->SetMaxAllowedSpaceUsage(max_allowed_space);
+}
+
+void gorocksdb_sstfilemanager_set_compaction_buffer_size(
+    gorocksdb_sstfilemanager_t* sfm, uint64_t compaction_buffer_size) {
+  sfm->rep->SetCompactionBufferSize(compaction_buffer_size);
+}
+
+unsigned char gorocksdb_sstfilemanager_is_max_allowed_space_reached(
+    gorocksdb_sstfilemanager_t* sfm) {
+  return sfm->rep->IsMaxAllowedSpaceReached();
+}
+
+unsigned char
+gorocksdb_sstfilemanager_is_max_allowed_space_reached_including_compactions(
+    gorocksdb_sstfilemanager_t* sfm) {
+  return sfm->rep->IsMaxAllowedSpaceReachedIncludingCompactions();
+}
+
+uint64_t gorocksdb_sstfilemanager_get_total_size(
+    gorocksdb_sstfilemanager_t* sfm) {
+  return sfm->rep->GetTotalSize();
+}
+
+int64_t gorocksdb_sstfilemanager_get_delete_rate_bytes_per_second(
+    gorocksdb_sstfilemanager_t* sfm) {
+  return sfm->rep->GetDeleteRateBytesPerSecond();
+}
+
+void gorocksdb_sstfilemanager_set_delete_rate_bytes_per_second(
+    gorocksdb_sstfilemanager_t* sfm, int64_t delete_rate) {
+  sfm->rep->SetDeleteRateBytesPerSecond(delete_rate);
+}
+
+double gorocksdb_sstfilemanager_get_max_trash_db_ratio(
+    gorocksdb_sstfilemanager_t* sfm) {
+  return sfm->rep->GetMaxTrashDBRatio();
+}
+
+void gorocksdb_sstfilemanager_set_max_trash_db_ratio(
+    gorocksdb_sstfilemanager_t* sfm, double ratio) {
+  sfm->rep->SetMaxTrashDBRatio(ratio);
+}
+
+uint64_t gorocksdb_sstfilemanager_get_total_trash_size(
+    gorocksdb_sstfilemanager_t* sfm) {
+  return sfm->rep->GetTotalTrashSize();
+}
+
+}  // extern "C"
@@ -0,0 +1,151 @@
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+// #include "gorocksdb.h"
+import "C"
+
+import (
+	"errors"
+	"unsafe"
+)
+
+// SstFileManager tracks the SST files of the DBs it is set on. It limits
+// their total size and rate limits their deletion, so that dropping a large
+// column family does not stall the disk. It can be shared by many DBs.
+//
+// Rate limited deletions first rename the files with a ".trash" suffix, then
+// delete them in the background. Trash files left by a previous process are
+// deleted when the SstFileManager is created with DeleteExistingTrash.
+type SstFileManager struct {
+	c *C.gorocksdb_sstfilemanager_t
+}
+
+// SstFileManagerOptions are the options of an SstFileManager.
+type SstFileManagerOptions struct {
+	// Env is used to delete the files. Nil means the default Env.
+	Env *Env
+	// DeleteRateBytesPerSecond is the maximum rate of file deletions.
+	// 0 disables the rate limiting.
+	DeleteRateBytesPerSecond int64
+	// DeleteExistingTrash deletes the trash files found in the DB paths.
+	DeleteExistingTrash bool
+	// MaxTrashDBRatio is the ratio of trash to DB size above which files are
+	// deleted immediately, ignoring DeleteRateBytesPerSecond.
+	MaxTrashDBRatio float64
+	// BytesMaxDeleteChunk is the size of the chunks large files are
+	// truncated by while they are deleted. 0 deletes them at once.
+	BytesMaxDeleteChunk uint64
+}
+
+// NewDefaultSstFileManagerOptions returns the default SstFileManager options.
+func NewDefaultSstFileManagerOptions() SstFileManagerOptions {
+	return SstFileManagerOptions{
+		DeleteExistingTrash: true,
+		MaxTrashDBRatio:     0.25,
+		BytesMaxDeleteChunk: 64 << 20,
+	}
+}
+
+// NewSstFileManager creates an SstFileManager with the options given.
+func NewSstFileManager(opts SstFileManagerOptions) (*SstFileManager, error) {
+	var (
+		cErr *C.char
+		cEnv *C.rocksdb_env_t
+	)
+	if opts.Env != nil {
+		cEnv = opts.Env.c
+	}
+	c := C.gorocksdb_sstfilemanager_create(cEnv, C.int64_t(opts.DeleteRateBytesPerSecond),
+		boolToChar(opts.DeleteExistingTrash), C.double(opts.MaxTrashDBRatio),
+		C.uint64_t(opts.BytesMaxDeleteChunk), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return nil, errors.New(C.GoString(cErr))
+	}
+	return &SstFileManager{c}, nil
+}
+
+// SetMaxAllowedSpaceUsage limits the total size of the SST files. Once it is
+// reached, flushes and compactions fail and the DB stops accepting writes,
+// which fail with a *NoSpaceError. 0 disables the limit.
+func (m *SstFileManager) SetMaxAllowedSpaceUsage(maxAllowedSpace uint64) {
+	C.gorocksdb_sstfilemanager_set_max_allowed_space_usage(m.c, C.uint64_t(maxAllowedSpace))
+}
+
+// SetCompactionBufferSize sets the space reserved for compactions when
+// checking whether a compaction would exceed the maximum allowed space.
+func (m *SstFileManager) SetCompactionBufferSize(compactionBufferSize uint64) {
+	C.gorocksdb_sstfilemanager_set_compaction_buffer_size(m.c, C.uint64_t(compactionBufferSize))
+}
+
+// IsMaxAllowedSpaceReached returns true if the total size of the SST files
+// exceeds the maximum allowed space.
+func (m *SstFileManager) IsMaxAllowedSpaceReached() bool {
+	return charToBool(C.gorocksdb_sstfilemanager_is_max_allowed_space_reached(m.c))
+}
+
+// IsMaxAllowedSpaceReachedIncludingCompactions returns true if the total size
+// of the SST files, plus the space reserved for running compactions, exceeds
+// the maximum allowed space.
+func (m *SstFileManager) IsMaxAllowedSpaceReachedIncludingCompactions() bool {
+	return charToBool(C.gorocksdb_sstfilemanager_is_max_allowed_space_reached_including_compactions(m.c))
+}
+
+// GetTotalSize returns the total size of the tracked SST files.
+func (m *SstFileManager) GetTotalSize() uint64 {
+	return uint64(C.gorocksdb_sstfilemanager_get_total_size(m.c))
+}
+
+// GetDeleteRateBytesPerSecond returns the maximum rate of file deletions.
+func (m *SstFileManager) GetDeleteRateBytesPerSecond() int64 {
+	return int64(C.gorocksdb_sstfilemanager_get_delete_rate_bytes_per_second(m.c))
+}
+
+// SetDeleteRateBytesPerSecond changes the maximum rate of file deletions.
+// 0 disables the rate limiting.
+func (m *SstFileManager) SetDeleteRateBytesPerSecond(deleteRate int64) {
+	C.gorocksdb_sstfilemanager_set_delete_rate_bytes_per_second(m.c, C.int64_t(deleteRate))
+}
+
+// GetMaxTrashDBRatio returns the ratio of trash to DB size above which files
+// are deleted immediately.
+func (m *SstFileManager) GetMaxTrashDBRatio() float64 {
+	return float64(C.gorocksdb_sstfilemanager_get_max_trash_db_ratio(m.c))
+}
+
+// SetMaxTrashDBRatio changes the ratio of trash to DB size above which files
+// are deleted immediately.
+func (m *SstFileManager) SetMaxTrashDBRatio(ratio float64) {
+	C.gorocksdb_sstfilemanager_set_max_trash_db_ratio(m.c, C.double(ratio))
+}
+
+// GetTotalTrashSize returns the total size of the files waiting to be deleted.
+func (m *SstFileManager) GetTotalTrashSize() uint64 {
+	return uint64(C.gorocksdb_sstfilemanager_get_total_trash_size(m.c))
+}
+
+// Destroy deallocates the SstFileManager object. The DBs using it keep
+// their own reference.
+func (m *SstFileManager) Destroy() {
+	C.gorocksdb_sstfilemanager_destroy(m.c)
+	m.c = nil
+}
+
+// NoSpaceError is returned by writes failing because the disk is full or
+// because the maximum space allowed by the SstFileManager is reached.
+type NoSpaceError struct {
+	msg string
+}
+
+// Error implements the error interface.
+func (e *NoSpaceError) Error() string { return e.msg }
+
+// newWriteError returns the error of a failed write, a *NoSpaceError if
+// noSpace tells it failed because of the lack of space.
+func newWriteError(msg string, noSpace C.uchar) error {
+	if charToBool(noSpace) {
+		return &NoSpaceError{msg}
+	}
+	return errors.New(msg)
+}
@@ -0,0 +1,67 @@
+package gorocksdb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestSstFileManager(t *testing.T) {
+	sfm, err := NewSstFileManager(NewDefaultSstFileManagerOptions())
+	ensure.Nil(t, err)
+	defer sfm.Destroy()
+	sfm.SetDeleteRateBytesPerSecond(1 << 20)
+	ensure.DeepEqual(t, sfm.GetDeleteRateBytesPerSecond(), int64(1<<20))
+	sfm.SetMaxTrashDBRatio(0.5)
+	ensure.DeepEqual(t, sfm.GetMaxTrashDBRatio(), 0.5)
+
+	db := newTestDB(t, "TestSstFileManager", func(opts *Options) {
+		opts.SetSstFileManager(sfm)
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	ensure.Nil(t, db.Put(wo, []byte("key1"), []byte("value")))
+	ensure.Nil(t, db.Flush(NewDefaultFlushOptions()))
+	ensure.True(t, sfm.GetTotalSize() > 0)
+	ensure.False(t, sfm.IsMaxAllowedSpaceReached())
+
+	sfm.SetMaxAllowedSpaceUsage(1)
+	ensure.True(t, sfm.IsMaxAllowedSpaceReached())
+	ensure.Nil(t, db.Put(wo, []byte("key2"), []byte("value")))
+	ensure.NotNil(t, db.Flush(NewDefaultFlushOptions()))
+
+	err = db.Put(wo, []byte("key3"), []byte("value"))
+	var noSpace *NoSpaceError
+	ensure.True(t, errors.As(err, &noSpace))
+}
+
+func TestTransactionDBNoSpaceError(t *testing.T) {
+	sfm, err := NewSstFileManager(NewDefaultSstFileManagerOptions())
+	ensure.Nil(t, err)
+	defer sfm.Destroy()
+
+	db := newTestTransactionDB(t, "TestTransactionDBNoSpaceError", func(opts *Options, _ *TransactionDBOptions) {
+		opts.SetSstFileManager(sfm)
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	fo := NewDefaultFlushOptions()
+	defer fo.Destroy()
+	sfm.SetMaxAllowedSpaceUsage(1)
+	ensure.Nil(t, db.Put(wo, []byte("key1"), []byte("value")))
+	baseDB := db.GetBaseDB()
+	defer CloseBaseDBOfTransactionDB(baseDB)
+	ensure.NotNil(t, baseDB.Flush(fo))
+
+	var noSpace *NoSpaceError
+	ensure.True(t, errors.As(db.Put(wo, []byte("key2"), []byte("value")), &noSpace))
+	txn := db.TransactionBegin(wo, NewDefaultTransactionOptions(), nil)
+	defer txn.Destroy()
+	ensure.Nil(t, txn.Put([]byte("key3"), []byte("value")))
+	ensure.True(t, errors.As(txn.Commit(), &noSpace))
+}
@@ -0,0 +1,67 @@
+#include "gorocksdb_internal.h"
+#include "rocksdb/sst_file_reader.h"
+
+using rocksdb::Options;
+using rocksdb::ReadOptions;
+using rocksdb::SstFileReader;
+
+struct gorocksdb_sstfilereader_t {
+  SstFileReader* rep;
+  // SstFileReader dereferences its table reader without checking that a
+  // file was opened successfully.
+  bool opened;
+};
+
+extern "C" {
+
+gorocksdb_sstfilereader_t* gorocksdb_sstfilereader_create(
+    const rocksdb_options_t* options) {
+  auto reader = new gorocksdb_sstfilereader_t;
+  reader->rep = new SstFileReader(gorocksdb::RepValue<const Options>(options));
+  reader->opened = false;
+  return reader;
+}
+
+void gorocksdb_sstfilereader_open(gorocksdb_sstfilereader_t* reader,
+                                  const char* path, char** errptr) {
+  reader->opened =
+      !gorocksdb::SaveError(errptr, reader->rep->Open(std::string(path)));
+}
+
+rocksdb_iterator_t* gorocksdb_sstfilereader_new_iterator(
+    gorocksdb_sstfilereader_t* reader, const rocksdb_readoptions_t* options) {
+  auto iter = new rocksdb_iterator_t;
+  if (reader->opened) {
+    iter->rep = reader->rep->NewIterator(
+        gorocksdb::RepValue<const ReadOptions>(options));
+  } else {
+    iter->rep = rocksdb::NewErrorIterator(
+        rocksdb::Status::InvalidArgument("no file is open"));
+  }
+  return iter;
+}
+
+void gorocksdb_sstfilereader_verify_checksum(gorocksdb_sstfilereader_t* reader,
+                                             char** errptr) {
+  if (!reader->opened) {
+    gorocksdb::SaveError(errptr,
+                         rocksdb::Status::InvalidArgument("no file is open"));
+    return;
+  }
+  gorocksdb::SaveError(errptr, reader->rep->VerifyChecksum());
+}
+
+gorocksdb_tableproperties_t* gorocksdb_sstfilereader_get_table_properties(
+    gorocksdb_sstfilereader_t* reader) {
+  if (!reader->opened) {
+    return nullptr;
+  }
+  return gorocksdb::NewTableProperties(reader->rep->GetTableProperties());
+}
+
+void gorocksdb_sstfilereader_destroy(gorocksdb_sstfilereader_t* reader) {
+  delete reader->rep;
+  delete reader;
+}
+
+}  // extern "C"
@@ -0,0 +1,73 @@
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+// #include "gorocksdb.h"
+import "C"
+
+import (
+	"errors"
+	"unsafe"
+)
+
+// SSTFileReader is used to read sst files that are generated by a DB or
+// by SSTFileWriter, e.g. to validate them before they are ingested.
+type SSTFileReader struct {
+	c *C.gorocksdb_sstfilereader_t
+}
+
+// NewSSTFileReader creates an SSTFileReader object. The options must use the
+// same comparator as the one the file was written with.
+func NewSSTFileReader(opts *Options) *SSTFileReader {
+	return &SSTFileReader{c: C.gorocksdb_sstfilereader_create(opts.c)}
+}
+
+// Open prepares the SSTFileReader to read the file located at "path".
+func (r *SSTFileReader) Open(path string) error {
+	var (
+		cErr  *C.char
+		cPath = C.CString(path)
+	)
+	defer C.free(unsafe.Pointer(cPath))
+	C.gorocksdb_sstfilereader_open(r.c, cPath, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
+
+// NewIterator returns an Iterator over the opened file that uses the
+// ReadOptions given. The iterator must be closed before the reader is destroyed.
+func (r *SSTFileReader) NewIterator(opts *ReadOptions) *Iterator {
+	cIter := C.gorocksdb_sstfilereader_new_iterator(r.c, opts.c)
+	return NewNativeIterator(unsafe.Pointer(cIter))
+}
+
+// VerifyChecksum verifies the checksums of all the blocks of the opened file.
+func (r *SSTFileReader) VerifyChecksum() error {
+	var cErr *C.char
+	C.gorocksdb_sstfilereader_verify_checksum(r.c, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
+
+// GetTableProperties returns the properties of the opened file,
+// or nil if no file is open.
+func (r *SSTFileReader) GetTableProperties() *TableProperties {
+	cProps := C.gorocksdb_sstfilereader_get_table_properties(r.c)
+	if cProps == nil {
+		return nil
+	}
+	defer C.gorocksdb_tableproperties_destroy(cProps)
+	return newTablePropertiesFromNative(cProps)
+}
+
+// Destroy destroys the SSTFileReader object.
+func (r *SSTFileReader) Destroy() {
+	C.gorocksdb_sstfilereader_destroy(r.c)
+	r.c = nil
+}
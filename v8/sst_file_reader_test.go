@@ -0,0 +1,65 @@
+package gorocksdb
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestSSTFileReader(t *testing.T) {
+	envOpts := NewDefaultEnvOptions()
+	opts := NewDefaultOptions()
+	w := NewSSTFileWriter(envOpts, opts)
+	defer w.Destroy()
+
+	filePath, err := ioutil.TempFile("", "sst-file-test")
+	ensure.Nil(t, err)
+	defer os.Remove(filePath.Name())
+
+	givenKeys := [][]byte{[]byte("key1"), []byte("key2"), []byte("key3")}
+	ensure.Nil(t, w.Open(filePath.Name()))
+	for _, k := range givenKeys {
+		ensure.Nil(t, w.Put(k, []byte("val")))
+	}
+	_, err = w.Finish()
+	ensure.Nil(t, err)
+
+	r := NewSSTFileReader(opts)
+	defer r.Destroy()
+	ensure.Nil(t, r.Open(filePath.Name()))
+	ensure.Nil(t, r.VerifyChecksum())
+
+	props := r.GetTableProperties()
+	ensure.DeepEqual(t, props.NumEntries, uint64(3))
+	ensure.DeepEqual(t, props.RawKeySize, uint64(3*(4+8)))
+	ensure.DeepEqual(t, props.RawValueSize, uint64(3*3))
+	ensure.DeepEqual(t, props.ComparatorName, "leveldb.BytewiseComparator")
+
+	ro := NewDefaultReadOptions()
+	iter := r.NewIterator(ro)
+	defer iter.Close()
+	var actualKeys [][]byte
+	for iter.SeekToFirst(); iter.Valid(); iter.Next() {
+		actualKeys = append(actualKeys, iter.Key().Copy())
+		ensure.DeepEqual(t, iter.Value().Data(), []byte("val"))
+	}
+	ensure.Nil(t, iter.Err())
+	ensure.DeepEqual(t, actualKeys, givenKeys)
+}
+
+func TestSSTFileReaderOpenInvalidFile(t *testing.T) {
+	filePath, err := ioutil.TempFile("", "sst-file-test")
+	ensure.Nil(t, err)
+	defer os.Remove(filePath.Name())
+	_, err = filePath.WriteString("not a sst file")
+	ensure.Nil(t, err)
+	ensure.Nil(t, filePath.Close())
+
+	opts := NewDefaultOptions()
+	r := NewSSTFileReader(opts)
+	defer r.Destroy()
+	ensure.NotNil(t, r.Open(filePath.Name()))
+	ensure.True(t, r.GetTableProperties() == nil)
+}
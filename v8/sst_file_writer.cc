@@ -0,0 +1,27 @@
+#include "gorocksdb_internal.h"
+#include "rocksdb/sst_file_writer.h"
+
+using rocksdb::ColumnFamilyHandle;
+using rocksdb::EnvOptions;
+using rocksdb::Options;
+using rocksdb::SstFileWriter;
+
+// Redeclared exactly as in db/c.cc, to be destroyed by
+// rocksdb_sstfilewriter_destroy.
+struct rocksdb_sstfilewriter_t {
+  SstFileWriter* rep;
+};
+
+extern "C" {
+
+rocksdb_sstfilewriter_t* gorocksdb_sstfilewriter_create_cf(
+    const rocksdb_envoptions_t* env, const rocksdb_options_t* options,
+    rocksdb_column_family_handle_t* cf) {
+  auto writer = new rocksdb_sstfilewriter_t;
+  writer->rep = new SstFileWriter(gorocksdb::RepValue<const EnvOptions>(env),
+                                  gorocksdb::RepValue<const Options>(options),
+                                  gorocksdb::Rep<ColumnFamilyHandle>(cf));
+  return writer;
+}
+
+}  // extern "C"
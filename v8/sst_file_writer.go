@@ -2,23 +2,72 @@ package gorocksdb
 
 // #include <stdlib.h>
 // #include "rocksdb/c.h"
+// #include "gorocksdb.h"
 import "C"
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"unsafe"
 )
 
+// ExternalSstFileInfo describes a sst file created by SSTFileWriter.
+type ExternalSstFileInfo struct {
+	// FilePath is the path of the sst file.
+	FilePath string
+	// SmallestKey is the smallest point key in the file.
+	SmallestKey []byte
+	// LargestKey is the largest point key in the file.
+	LargestKey []byte
+	// SmallestRangeDelKey is the smallest begin key of the range deletions in
+	// the file. It is nil if the comparator cannot be evaluated from Go, e.g.
+	// a comparator created by NewNativeComparator.
+	SmallestRangeDelKey []byte
+	// LargestRangeDelKey is the largest end key of the range deletions in the
+	// file. It is nil if the comparator cannot be evaluated from Go.
+	LargestRangeDelKey []byte
+	// SequenceNumber is the sequence number of all the keys in the file,
+	// which is always 0 for files created by SSTFileWriter.
+	SequenceNumber uint64
+	// FileSize is the size of the file in bytes.
+	FileSize uint64
+	// NumEntries is the number of point entries (puts, merges and deletes) in the file.
+	NumEntries uint64
+	// NumRangeDelEntries is the number of range deletions in the file.
+	NumRangeDelEntries uint64
+}
+
 // SSTFileWriter is used to create sst files that can be added to database later.
 // All keys in files generated by SstFileWriter will have sequence number = 0.
 type SSTFileWriter struct {
-	c *C.rocksdb_sstfilewriter_t
+	c       *C.rocksdb_sstfilewriter_t
+	envOpts *EnvOptions
+
+	// compare is used to validate the key order before handing keys to
+	// RocksDB. It is nil when the comparator is only known natively.
+	compare func(a, b []byte) int
+	info    ExternalSstFileInfo
 }
 
 // NewSSTFileWriter creates an SSTFileWriter object.
+// Keys are ordered by the comparator of dbOpts.
 func NewSSTFileWriter(opts *EnvOptions, dbOpts *Options) *SSTFileWriter {
 	c := C.rocksdb_sstfilewriter_create(opts.c, dbOpts.c)
-	return &SSTFileWriter{c: c}
+	return &SSTFileWriter{c: c, envOpts: opts, compare: optionsCompare(dbOpts)}
+}
+
+// optionsCompare returns the Go comparison function matching the comparator
+// of opts, or nil if it cannot be evaluated from Go.
+func optionsCompare(opts *Options) func(a, b []byte) int {
+	switch cmp := opts.cmp.(type) {
+	case nil:
+		return bytes.Compare
+	case nativeComparator:
+		return cmp.compare
+	default:
+		return cmp.Compare
+	}
 }
 
 // Open prepares SstFileWriter to write into file located at "path".
@@ -33,34 +82,152 @@ func (w *SSTFileWriter) Open(path string) error {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
 		return errors.New(C.GoString(cErr))
 	}
+	w.info = ExternalSstFileInfo{FilePath: path}
 	return nil
 }
 
+// OpenForColumnFamily prepares SstFileWriter to write into file located at
+// "path" for the column family cf. The ID and name of cf are recorded in the
+// properties of the file. cfOpts must be the options cf was opened with, so
+// that keys are ordered by the comparator of that column family.
+func (w *SSTFileWriter) OpenForColumnFamily(path string, cf *ColumnFamilyHandle, cfOpts *Options) error {
+	C.rocksdb_sstfilewriter_destroy(w.c)
+	w.c = C.gorocksdb_sstfilewriter_create_cf(w.envOpts.c, cfOpts.c, cf.c)
+	w.compare = optionsCompare(cfOpts)
+	return w.Open(path)
+}
+
 // Add adds key, value to currently opened file.
 // REQUIRES: key is after any previously added key according to comparator.
+//
+// Deprecated: use Put.
 func (w *SSTFileWriter) Add(key, value []byte) error {
+	return w.Put(key, value)
+}
+
+// Put adds a Put key with value to currently opened file.
+// REQUIRES: key is after any previously added key according to comparator.
+func (w *SSTFileWriter) Put(key, value []byte) error {
+	if err := w.checkKeyOrder(key); err != nil {
+		return err
+	}
 	cKey := byteToChar(key)
 	cValue := byteToChar(value)
 	var cErr *C.char
-	C.rocksdb_sstfilewriter_add(w.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
+	C.rocksdb_sstfilewriter_put(w.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
 		return errors.New(C.GoString(cErr))
 	}
+	w.addKey(key)
 	return nil
 }
 
-// Finish finishes writing to sst file and close file.
-func (w *SSTFileWriter) Finish() error {
+// Merge adds a Merge key with value to currently opened file.
+// REQUIRES: key is after any previously added key according to comparator.
+func (w *SSTFileWriter) Merge(key, value []byte) error {
+	if err := w.checkKeyOrder(key); err != nil {
+		return err
+	}
+	cKey := byteToChar(key)
+	cValue := byteToChar(value)
 	var cErr *C.char
-	C.rocksdb_sstfilewriter_finish(w.c, &cErr)
+	C.rocksdb_sstfilewriter_merge(w.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	w.addKey(key)
+	return nil
+}
+
+// Delete adds a deletion key to currently opened file.
+// REQUIRES: key is after any previously added key according to comparator.
+func (w *SSTFileWriter) Delete(key []byte) error {
+	if err := w.checkKeyOrder(key); err != nil {
+		return err
+	}
+	cKey := byteToChar(key)
+	var cErr *C.char
+	C.rocksdb_sstfilewriter_delete(w.c, cKey, C.size_t(len(key)), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
 		return errors.New(C.GoString(cErr))
 	}
+	w.addKey(key)
 	return nil
 }
 
+// DeleteRange adds a range deletion tombstone for the keys in
+// [beginKey, endKey) to currently opened file. Range deletions do not need
+// to be ordered with respect to the point keys of the file.
+func (w *SSTFileWriter) DeleteRange(beginKey, endKey []byte) error {
+	if w.compare != nil && w.compare(beginKey, endKey) > 0 {
+		return fmt.Errorf("end key %q comes before start key %q", endKey, beginKey)
+	}
+	cBeginKey := byteToChar(beginKey)
+	cEndKey := byteToChar(endKey)
+	var cErr *C.char
+	C.rocksdb_sstfilewriter_delete_range(w.c, cBeginKey, C.size_t(len(beginKey)), cEndKey, C.size_t(len(endKey)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	if w.compare != nil {
+		if w.info.NumRangeDelEntries == 0 || w.compare(beginKey, w.info.SmallestRangeDelKey) < 0 {
+			w.info.SmallestRangeDelKey = append([]byte(nil), beginKey...)
+		}
+		if w.info.NumRangeDelEntries == 0 || w.compare(endKey, w.info.LargestRangeDelKey) > 0 {
+			w.info.LargestRangeDelKey = append([]byte(nil), endKey...)
+		}
+	}
+	w.info.NumRangeDelEntries++
+	return nil
+}
+
+// checkKeyOrder returns an error naming the offending key if key is not
+// strictly after the previously added point key.
+func (w *SSTFileWriter) checkKeyOrder(key []byte) error {
+	if w.compare == nil || w.info.NumEntries == 0 {
+		return nil
+	}
+	if w.compare(key, w.info.LargestKey) <= 0 {
+		return fmt.Errorf("keys must be added in strict ascending order: key %q is not after previous key %q", key, w.info.LargestKey)
+	}
+	return nil
+}
+
+// addKey records a point key that was successfully added to the file.
+func (w *SSTFileWriter) addKey(key []byte) {
+	if w.info.NumEntries == 0 {
+		w.info.SmallestKey = append([]byte(nil), key...)
+	}
+	w.info.LargestKey = append(w.info.LargestKey[:0], key...)
+	w.info.NumEntries++
+}
+
+// FileSize returns the current size of the file being written.
+func (w *SSTFileWriter) FileSize() uint64 {
+	var size C.uint64_t
+	C.rocksdb_sstfilewriter_file_size(w.c, &size)
+	return uint64(size)
+}
+
+// Finish finishes writing to sst file and close file.
+// It returns the information about the created file.
+func (w *SSTFileWriter) Finish() (*ExternalSstFileInfo, error) {
+	var cErr *C.char
+	C.rocksdb_sstfilewriter_finish(w.c, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return nil, errors.New(C.GoString(cErr))
+	}
+	info := w.info
+	info.FileSize = w.FileSize()
+	w.info = ExternalSstFileInfo{}
+	return &info, nil
+}
+
 // Destroy destroys the SSTFileWriter object.
 func (w *SSTFileWriter) Destroy() {
 	C.rocksdb_sstfilewriter_destroy(w.c)
@@ -0,0 +1,102 @@
+#include "gorocksdb_internal.h"
+#include "rocksdb/sst_partitioner.h"
+
+extern "C" {
+#include "_cgo_export.h"
+}
+
+using rocksdb::Options;
+using rocksdb::PartitionerRequest;
+using rocksdb::PartitionerResult;
+using rocksdb::Slice;
+using rocksdb::SstPartitioner;
+using rocksdb::SstPartitionerFactory;
+
+namespace {
+
+// GoSstPartitioner calls into a Go SstPartitioner registered by handle.
+// The handle is released when the partitioner is destroyed.
+class GoSstPartitioner : public SstPartitioner {
+ public:
+  GoSstPartitioner(uintptr_t handle, std::string name)
+      : handle_(handle), name_(std::move(name)) {}
+
+  ~GoSstPartitioner() override { gorocksdb_sstpartitioner_release(handle_); }
+
+  const char* Name() const override { return name_.c_str(); }
+
+  PartitionerResult ShouldPartition(
+      const PartitionerRequest& request) override {
+    bool required = gorocksdb_sstpartitioner_should_partition(
+        handle_, const_cast<char*>(request.prev_user_key->data()),
+        request.prev_user_key->size(),
+        const_cast<char*>(request.current_user_key->data()),
+        request.current_user_key->size(), request.current_output_file_size);
+    return required ? rocksdb::kRequired : rocksdb::kNotRequired;
+  }
+
+  bool CanDoTrivialMove(const Slice& smallest_user_key,
+                        const Slice& largest_user_key) override {
+    return gorocksdb_sstpartitioner_can_do_trivial_move(
+        handle_, const_cast<char*>(smallest_user_key.data()),
+        smallest_user_key.size(), const_cast<char*>(largest_user_key.data()),
+        largest_user_key.size());
+  }
+
+ private:
+  uintptr_t handle_;
+  std::string name_;
+};
+
+// GoSstPartitionerFactory calls into a Go SstPartitionerFactory.
+class GoSstPartitionerFactory : public SstPartitionerFactory {
+ public:
+  explicit GoSstPartitionerFactory(uintptr_t idx) : idx_(idx) {
+    char* name = gorocksdb_sstpartitionerfactory_name(idx);
+    name_ = name;
+    free(name);
+  }
+
+  std::unique_ptr<SstPartitioner> CreatePartitioner(
+      const SstPartitioner::Context& context) const override {
+    char* name = nullptr;
+    uintptr_t handle = gorocksdb_sstpartitionerfactory_create_partitioner(
+        idx_, context.is_full_compaction, context.is_manual_compaction,
+        context.output_level,
+        const_cast<char*>(context.smallest_user_key.data()),
+        context.smallest_user_key.size(),
+        const_cast<char*>(context.largest_user_key.data()),
+        context.largest_user_key.size(), &name);
+    if (handle == 0) {
+      return nullptr;
+    }
+    std::unique_ptr<SstPartitioner> partitioner(
+        new GoSstPartitioner(handle, name));
+    free(name);
+    return partitioner;
+  }
+
+  const char* Name() const override { return name_.c_str(); }
+
+ private:
+  uintptr_t idx_;
+  std::string name_;
+};
+
+}  // namespace
+
+extern "C" {
+
+void gorocksdb_options_set_sst_partitioner_factory(rocksdb_options_t* opts,
+                                                   uintptr_t idx) {
+  gorocksdb::RepValue<Options>(opts).sst_partitioner_factory =
+      std::make_shared<GoSstPartitionerFactory>(idx);
+}
+
+void gorocksdb_options_set_sst_partitioner_fixed_prefix_factory(
+    rocksdb_options_t* opts, size_t prefix_len) {
+  gorocksdb::RepValue<Options>(opts).sst_partitioner_factory =
+      rocksdb::NewSstPartitionerFixedPrefixFactory(prefix_len);
+}
+
+}  // extern "C"
@@ -0,0 +1,149 @@
+package gorocksdb
+
+// #include "rocksdb/c.h"
+import "C"
+
+import (
+	"bytes"
+	"unsafe"
+)
+
+// SstPartitionerContext describes the compaction a SstPartitioner is
+// created for.
+type SstPartitionerContext struct {
+	// IsFullCompaction is true if the compaction includes all the files.
+	IsFullCompaction bool
+	// IsManualCompaction is true if the compaction was requested by the
+	// application, e.g. through CompactRange.
+	IsManualCompaction bool
+	// OutputLevel is the level the compaction writes to.
+	OutputLevel int
+	// SmallestUserKey is the smallest key of the compaction.
+	SmallestUserKey []byte
+	// LargestUserKey is the largest key of the compaction.
+	LargestUserKey []byte
+}
+
+// A SstPartitioner decides where the output of a compaction is split into
+// several sst files, e.g. so that no file spans two tenants and
+// DeleteFileInRangeCF can drop the files of a tenant entirely.
+//
+// If the partitioner has a Destroy() method, it is called once the
+// compaction is done with it.
+type SstPartitioner interface {
+	// ShouldPartition returns true if a new file must be started before
+	// currentUserKey. The application must not keep references to the keys
+	// after the call.
+	ShouldPartition(prevUserKey, currentUserKey []byte, currentOutputFileSize uint64) bool
+
+	// CanDoTrivialMove returns true if a file with the given key range can be
+	// moved to the next level without being rewritten, i.e. if the file would
+	// not be split by the partitioner.
+	CanDoTrivialMove(smallestUserKey, largestUserKey []byte) bool
+
+	// The name of the partitioner, for logging
+	Name() string
+}
+
+// A SstPartitionerFactory creates a SstPartitioner for every compaction.
+type SstPartitionerFactory interface {
+	// CreatePartitioner returns the partitioner for the compaction described
+	// by ctx, or nil if its output does not need to be partitioned.
+	CreatePartitioner(ctx SstPartitionerContext) SstPartitioner
+
+	// The name of the partitioner factory, for logging
+	Name() string
+}
+
+// NewFixedPrefixSstPartitionerFactory creates a SstPartitionerFactory, which
+// is implemented natively, splitting the compaction output files where the
+// first prefixLen bytes of the keys change.
+func NewFixedPrefixSstPartitionerFactory(prefixLen int) SstPartitionerFactory {
+	return fixedPrefixSstPartitionerFactory{prefixLen}
+}
+
+type fixedPrefixSstPartitionerFactory struct {
+	prefixLen int
+}
+
+func (f fixedPrefixSstPartitionerFactory) CreatePartitioner(ctx SstPartitionerContext) SstPartitioner {
+	return fixedPrefixSstPartitioner(f)
+}
+func (f fixedPrefixSstPartitionerFactory) Name() string { return "SstPartitionerFixedPrefixFactory" }
+
+// fixedPrefixSstPartitioner implements in Go the partitioner created natively
+// by fixedPrefixSstPartitionerFactory.
+type fixedPrefixSstPartitioner struct {
+	prefixLen int
+}
+
+func (p fixedPrefixSstPartitioner) prefix(key []byte) []byte {
+	if len(key) < p.prefixLen {
+		return key
+	}
+	return key[:p.prefixLen]
+}
+
+func (p fixedPrefixSstPartitioner) ShouldPartition(prevUserKey, currentUserKey []byte, currentOutputFileSize uint64) bool {
+	return !bytes.Equal(p.prefix(prevUserKey), p.prefix(currentUserKey))
+}
+func (p fixedPrefixSstPartitioner) CanDoTrivialMove(smallestUserKey, largestUserKey []byte) bool {
+	return !p.ShouldPartition(smallestUserKey, largestUserKey, 0)
+}
+func (p fixedPrefixSstPartitioner) Name() string { return "SstPartitionerFixedPrefix" }
+
+// Hold references to sst partitioner factories.
+var sstPartitionerFactories = NewCOWList()
+
+// Hold references to the sst partitioners for the duration of their compaction.
+var sstPartitioners = newRegistry()
+
+func registerSstPartitionerFactory(factory SstPartitionerFactory) int {
+	return sstPartitionerFactories.Append(factory)
+}
+
+//export gorocksdb_sstpartitionerfactory_create_partitioner
+func gorocksdb_sstpartitionerfactory_create_partitioner(idx int, cIsFull, cIsManual C.uchar, cOutputLevel C.int, cSmallest *C.char, cSmallestLen C.size_t, cLargest *C.char, cLargestLen C.size_t, cName **C.char) C.uintptr_t {
+	ctx := SstPartitionerContext{
+		IsFullCompaction:   cIsFull != 0,
+		IsManualCompaction: cIsManual != 0,
+		OutputLevel:        int(cOutputLevel),
+		SmallestUserKey:    C.GoBytes(unsafe.Pointer(cSmallest), C.int(cSmallestLen)),
+		LargestUserKey:     C.GoBytes(unsafe.Pointer(cLargest), C.int(cLargestLen)),
+	}
+	partitioner := sstPartitionerFactories.Get(idx).(SstPartitionerFactory).CreatePartitioner(ctx)
+	if partitioner == nil {
+		return 0
+	}
+	*cName = C.CString(partitioner.Name())
+	return C.uintptr_t(sstPartitioners.register(partitioner))
+}
+
+//export gorocksdb_sstpartitionerfactory_name
+func gorocksdb_sstpartitionerfactory_name(idx int) *C.char {
+	return C.CString(sstPartitionerFactories.Get(idx).(SstPartitionerFactory).Name())
+}
+
+//export gorocksdb_sstpartitioner_should_partition
+func gorocksdb_sstpartitioner_should_partition(handle C.uintptr_t, cPrev *C.char, cPrevLen C.size_t, cCurrent *C.char, cCurrentLen C.size_t, cFileSize C.uint64_t) C.uchar {
+	prev := charToByte(cPrev, cPrevLen)
+	current := charToByte(cCurrent, cCurrentLen)
+	partitioner := sstPartitioners.get(uintptr(handle)).(SstPartitioner)
+	return boolToChar(partitioner.ShouldPartition(prev, current, uint64(cFileSize)))
+}
+
+//export gorocksdb_sstpartitioner_can_do_trivial_move
+func gorocksdb_sstpartitioner_can_do_trivial_move(handle C.uintptr_t, cSmallest *C.char, cSmallestLen C.size_t, cLargest *C.char, cLargestLen C.size_t) C.uchar {
+	smallest := charToByte(cSmallest, cSmallestLen)
+	largest := charToByte(cLargest, cLargestLen)
+	partitioner := sstPartitioners.get(uintptr(handle)).(SstPartitioner)
+	return boolToChar(partitioner.CanDoTrivialMove(smallest, largest))
+}
+
+//export gorocksdb_sstpartitioner_release
+func gorocksdb_sstpartitioner_release(handle C.uintptr_t) {
+	partitioner := sstPartitioners.release(uintptr(handle))
+	if d, ok := partitioner.(interface{ Destroy() }); ok {
+		d.Destroy()
+	}
+}
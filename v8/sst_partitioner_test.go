@@ -0,0 +1,81 @@
+package gorocksdb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestSstPartitionerFactory(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		factory SstPartitionerFactory
+	}{
+		{"FixedPrefix", NewFixedPrefixSstPartitionerFactory(3)},
+		{"Go", &mockSstPartitionerFactory{}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			db := newTestDB(t, "TestSstPartitionerFactory"+c.name, func(opts *Options) {
+				opts.SetSstPartitionerFactory(c.factory)
+			})
+			defer db.Close()
+
+			wo := NewDefaultWriteOptions()
+			for _, k := range []string{"t1/a", "t1/b", "t2/a", "t2/b", "t3/a"} {
+				ensure.Nil(t, db.Put(wo, []byte(k), []byte("val")))
+			}
+			db.CompactRange(Range{nil, nil})
+
+			// every file holds a single prefix
+			files := db.GetLiveFilesMetaData()
+			ensure.DeepEqual(t, len(files), 3)
+			for _, f := range files {
+				ensure.DeepEqual(t, f.SmallestKey[:3], f.LargestKey[:3])
+			}
+
+			// so the files of a prefix can be dropped entirely
+			ensure.Nil(t, db.DeleteFileInRange(Range{Start: []byte("t2/"), Limit: []byte("t2/\xff")}))
+			ensure.DeepEqual(t, len(db.GetLiveFilesMetaData()), 2)
+		})
+	}
+}
+
+func TestSstPartitionerFactoryNil(t *testing.T) {
+	db := newTestDB(t, "TestSstPartitionerFactoryNil", func(opts *Options) {
+		opts.SetSstPartitionerFactory(&mockSstPartitionerFactory{skip: true})
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	for _, k := range []string{"t1/a", "t2/a", "t3/a"} {
+		ensure.Nil(t, db.Put(wo, []byte(k), []byte("val")))
+	}
+	db.CompactRange(Range{nil, nil})
+
+	// the output is not partitioned
+	ensure.DeepEqual(t, len(db.GetLiveFilesMetaData()), 1)
+}
+
+type mockSstPartitionerFactory struct {
+	skip bool
+}
+
+func (f *mockSstPartitionerFactory) Name() string { return "gorocksdb.test" }
+func (f *mockSstPartitionerFactory) CreatePartitioner(ctx SstPartitionerContext) SstPartitioner {
+	if f.skip {
+		return nil
+	}
+	return &mockSstPartitioner{}
+}
+
+type mockSstPartitioner struct{}
+
+func (p *mockSstPartitioner) Name() string { return "gorocksdb.test" }
+func (p *mockSstPartitioner) ShouldPartition(prevUserKey, currentUserKey []byte, currentOutputFileSize uint64) bool {
+	return !bytes.Equal(prevUserKey[:3], currentUserKey[:3])
+}
+func (p *mockSstPartitioner) CanDoTrivialMove(smallestUserKey, largestUserKey []byte) bool {
+	return bytes.Equal(smallestUserKey[:3], largestUserKey[:3])
+}
@@ -0,0 +1,177 @@
+#include <utility>
+#include <vector>
+
+#include "gorocksdb_internal.h"
+
+using rocksdb::TableProperties;
+using rocksdb::TablePropertiesCollection;
+
+struct gorocksdb_tableproperties_t {
+  std::shared_ptr<const TableProperties> rep;
+  std::vector<const std::pair<const std::string, std::string>*> user;
+};
+
+struct gorocksdb_tablepropertiescollection_t {
+  std::vector<std::string> files;
+  std::vector<gorocksdb_tableproperties_t*> props;
+};
+
+namespace gorocksdb {
+
+gorocksdb_tablepropertiescollection_t* NewTablePropertiesCollection(
+    const TablePropertiesCollection& collection) {
+  auto result = new gorocksdb_tablepropertiescollection_t;
+  for (const auto& kv : collection) {
+    // Files whose properties could not be loaded are skipped.
+    if (kv.second == nullptr) {
+      continue;
+    }
+    result->files.push_back(kv.first);
+    result->props.push_back(NewTableProperties(kv.second));
+  }
+  return result;
+}
+
+gorocksdb_tableproperties_t* NewTableProperties(
+    std::shared_ptr<const TableProperties> props) {
+  if (props == nullptr) {
+    return nullptr;
+  }
+  auto result = new gorocksdb_tableproperties_t;
+  result->rep = std::move(props);
+  for (const auto& kv : result->rep->user_collected_properties) {
+    result->user.push_back(&kv);
+  }
+  return result;
+}
+
+}  // namespace gorocksdb
+
+extern "C" {
+
+uint64_t gorocksdb_tableproperties_get_uint64(
+    const gorocksdb_tableproperties_t* props, int property) {
+  const TableProperties& p = *props->rep;
+  switch (property) {
+    case gorocksdb_tableproperties_data_size:
+      return p.data_size;
+    case gorocksdb_tableproperties_index_size:
+      return p.index_size;
+    case gorocksdb_tableproperties_filter_size:
+      return p.filter_size;
+    case gorocksdb_tableproperties_raw_key_size:
+      return p.raw_key_size;
+    case gorocksdb_tableproperties_raw_value_size:
+      return p.raw_value_size;
+    case gorocksdb_tableproperties_num_data_blocks:
+      return p.num_data_blocks;
+    case gorocksdb_tableproperties_num_entries:
+      return p.num_entries;
+    case gorocksdb_tableproperties_num_deletions:
+      return p.num_deletions;
+    case gorocksdb_tableproperties_num_merge_operands:
+      return p.num_merge_operands;
+    case gorocksdb_tableproperties_num_range_deletions:
+      return p.num_range_deletions;
+    case gorocksdb_tableproperties_format_version:
+      return p.format_version;
+    case gorocksdb_tableproperties_fixed_key_len:
+      return p.fixed_key_len;
+    case gorocksdb_tableproperties_column_family_id:
+      return p.column_family_id;
+    case gorocksdb_tableproperties_creation_time:
+      return p.creation_time;
+    case gorocksdb_tableproperties_oldest_key_time:
+      return p.oldest_key_time;
+    case gorocksdb_tableproperties_file_creation_time:
+      return p.file_creation_time;
+    default:
+      return 0;
+  }
+}
+
+const char* gorocksdb_tableproperties_get_string(
+    const gorocksdb_tableproperties_t* props, int property, size_t* len) {
+  const TableProperties& p = *props->rep;
+  const std::string* s;
+  switch (property) {
+    case gorocksdb_tableproperties_column_family_name:
+      s = &p.column_family_name;
+      break;
+    case gorocksdb_tableproperties_filter_policy_name:
+      s = &p.filter_policy_name;
+      break;
+    case gorocksdb_tableproperties_comparator_name:
+      s = &p.comparator_name;
+      break;
+    case gorocksdb_tableproperties_merge_operator_name:
+      s = &p.merge_operator_name;
+      break;
+    case gorocksdb_tableproperties_prefix_extractor_name:
+      s = &p.prefix_extractor_name;
+      break;
+    case gorocksdb_tableproperties_property_collectors_names:
+      s = &p.property_collectors_names;
+      break;
+    case gorocksdb_tableproperties_compression_name:
+      s = &p.compression_name;
+      break;
+    case gorocksdb_tableproperties_compression_options:
+      s = &p.compression_options;
+      break;
+    default:
+      *len = 0;
+      return nullptr;
+  }
+  *len = s->size();
+  return s->data();
+}
+
+size_t gorocksdb_tableproperties_user_count(
+    const gorocksdb_tableproperties_t* props) {
+  return props->user.size();
+}
+
+const char* gorocksdb_tableproperties_user_key(
+    const gorocksdb_tableproperties_t* props, size_t i, size_t* len) {
+  const std::string& key = props->user[i]->first;
+  *len = key.size();
+  return key.data();
+}
+
+const char* gorocksdb_tableproperties_user_value(
+    const gorocksdb_tableproperties_t* props, size_t i, size_t* len) {
+  const std::string& value = props->user[i]->second;
+  *len = value.size();
+  return value.data();
+}
+
+void gorocksdb_tableproperties_destroy(gorocksdb_tableproperties_t* props) {
+  delete props;
+}
+
+size_t gorocksdb_tablepropertiescollection_count(
+    const gorocksdb_tablepropertiescollection_t* c) {
+  return c->files.size();
+}
+
+const char* gorocksdb_tablepropertiescollection_file(
+    const gorocksdb_tablepropertiescollection_t* c, size_t i, size_t* len) {
+  *len = c->files[i].size();
+  return c->files[i].data();
+}
+
+gorocksdb_tableproperties_t* gorocksdb_tablepropertiescollection_properties(
+    const gorocksdb_tablepropertiescollection_t* c, size_t i) {
+  return c->props[i];
+}
+
+void gorocksdb_tablepropertiescollection_destroy(
+    gorocksdb_tablepropertiescollection_t* c) {
+  for (auto props : c->props) {
+    delete props;
+  }
+  delete c;
+}
+
+}  // extern "C"
@@ -0,0 +1,126 @@
+package gorocksdb
+
+// #include "rocksdb/c.h"
+// #include "gorocksdb.h"
+import "C"
+
+// TableProperties contains the properties of a sst file.
+type TableProperties struct {
+	// DataSize is the total size of all data blocks.
+	DataSize uint64
+	// IndexSize is the size of the index block.
+	IndexSize uint64
+	// FilterSize is the size of the filter block.
+	FilterSize uint64
+	// RawKeySize is the total raw key size.
+	RawKeySize uint64
+	// RawValueSize is the total raw value size.
+	RawValueSize uint64
+	// NumDataBlocks is the number of data blocks in the file.
+	NumDataBlocks uint64
+	// NumEntries is the number of entries in the file.
+	NumEntries uint64
+	// NumDeletions is the number of deletions in the file.
+	NumDeletions uint64
+	// NumMergeOperands is the number of merge operands in the file.
+	NumMergeOperands uint64
+	// NumRangeDeletions is the number of range deletions in the file.
+	NumRangeDeletions uint64
+	// FormatVersion is the format version of the table.
+	FormatVersion uint64
+	// FixedKeyLen is the length of the keys if they all have the same length, 0 otherwise.
+	FixedKeyLen uint64
+	// ColumnFamilyID is the ID of the column family the file belongs to.
+	ColumnFamilyID uint64
+	// CreationTime is the time (in seconds since epoch) the oldest data of the
+	// file was written, 0 if unknown.
+	CreationTime uint64
+	// OldestKeyTime is the timestamp of the earliest key, 0 if unknown.
+	OldestKeyTime uint64
+	// FileCreationTime is the time (in seconds since epoch) the file was created.
+	FileCreationTime uint64
+
+	// ColumnFamilyName is the name of the column family the file belongs to.
+	ColumnFamilyName string
+	// FilterPolicyName is the name of the filter policy, empty if none was used.
+	FilterPolicyName string
+	// ComparatorName is the name of the comparator.
+	ComparatorName string
+	// MergeOperatorName is the name of the merge operator.
+	MergeOperatorName string
+	// PrefixExtractorName is the name of the prefix extractor.
+	PrefixExtractorName string
+	// PropertyCollectorsNames are the names of the table properties collectors.
+	PropertyCollectorsNames string
+	// CompressionName is the name of the compression algorithm.
+	CompressionName string
+	// CompressionOptions are the compression options used.
+	CompressionOptions string
+
+	// UserCollectedProperties are the properties added by table properties collectors.
+	UserCollectedProperties map[string]string
+}
+
+// newTablePropertiesFromNative copies the properties of c into a TableProperties.
+func newTablePropertiesFromNative(c *C.gorocksdb_tableproperties_t) *TableProperties {
+	u := func(property C.int) uint64 {
+		return uint64(C.gorocksdb_tableproperties_get_uint64(c, property))
+	}
+	s := func(property C.int) string {
+		var cLen C.size_t
+		cValue := C.gorocksdb_tableproperties_get_string(c, property, &cLen)
+		return string(charToByte(cValue, cLen))
+	}
+
+	props := &TableProperties{
+		DataSize:          u(C.gorocksdb_tableproperties_data_size),
+		IndexSize:         u(C.gorocksdb_tableproperties_index_size),
+		FilterSize:        u(C.gorocksdb_tableproperties_filter_size),
+		RawKeySize:        u(C.gorocksdb_tableproperties_raw_key_size),
+		RawValueSize:      u(C.gorocksdb_tableproperties_raw_value_size),
+		NumDataBlocks:     u(C.gorocksdb_tableproperties_num_data_blocks),
+		NumEntries:        u(C.gorocksdb_tableproperties_num_entries),
+		NumDeletions:      u(C.gorocksdb_tableproperties_num_deletions),
+		NumMergeOperands:  u(C.gorocksdb_tableproperties_num_merge_operands),
+		NumRangeDeletions: u(C.gorocksdb_tableproperties_num_range_deletions),
+		FormatVersion:     u(C.gorocksdb_tableproperties_format_version),
+		FixedKeyLen:       u(C.gorocksdb_tableproperties_fixed_key_len),
+		ColumnFamilyID:    u(C.gorocksdb_tableproperties_column_family_id),
+		CreationTime:      u(C.gorocksdb_tableproperties_creation_time),
+		OldestKeyTime:     u(C.gorocksdb_tableproperties_oldest_key_time),
+		FileCreationTime:  u(C.gorocksdb_tableproperties_file_creation_time),
+
+		ColumnFamilyName:        s(C.gorocksdb_tableproperties_column_family_name),
+		FilterPolicyName:        s(C.gorocksdb_tableproperties_filter_policy_name),
+		ComparatorName:          s(C.gorocksdb_tableproperties_comparator_name),
+		MergeOperatorName:       s(C.gorocksdb_tableproperties_merge_operator_name),
+		PrefixExtractorName:     s(C.gorocksdb_tableproperties_prefix_extractor_name),
+		PropertyCollectorsNames: s(C.gorocksdb_tableproperties_property_collectors_names),
+		CompressionName:         s(C.gorocksdb_tableproperties_compression_name),
+		CompressionOptions:      s(C.gorocksdb_tableproperties_compression_options),
+	}
+
+	n := int(C.gorocksdb_tableproperties_user_count(c))
+	props.UserCollectedProperties = make(map[string]string, n)
+	for i := 0; i < n; i++ {
+		var cKeyLen, cValLen C.size_t
+		cKey := C.gorocksdb_tableproperties_user_key(c, C.size_t(i), &cKeyLen)
+		cVal := C.gorocksdb_tableproperties_user_value(c, C.size_t(i), &cValLen)
+		props.UserCollectedProperties[string(charToByte(cKey, cKeyLen))] = string(charToByte(cVal, cValLen))
+	}
+	return props
+}
+
+// newTablePropertiesCollectionFromNative copies the properties of c into a
+// map keyed by file path.
+func newTablePropertiesCollectionFromNative(c *C.gorocksdb_tablepropertiescollection_t) map[string]*TableProperties {
+	n := int(C.gorocksdb_tablepropertiescollection_count(c))
+	collection := make(map[string]*TableProperties, n)
+	for i := 0; i < n; i++ {
+		var cLen C.size_t
+		cFile := C.gorocksdb_tablepropertiescollection_file(c, C.size_t(i), &cLen)
+		cProps := C.gorocksdb_tablepropertiescollection_properties(c, C.size_t(i))
+		collection[string(charToByte(cFile, cLen))] = newTablePropertiesFromNative(cProps)
+	}
+	return collection
+}
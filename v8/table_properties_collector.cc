@@ -0,0 +1,118 @@
+#include "gorocksdb_internal.h"
+#include "rocksdb/table_properties.h"
+
+extern "C" {
+#include "_cgo_export.h"
+}
+
+using rocksdb::EntryType;
+using rocksdb::Options;
+using rocksdb::SequenceNumber;
+using rocksdb::Slice;
+using rocksdb::Status;
+using rocksdb::TablePropertiesCollector;
+using rocksdb::TablePropertiesCollectorFactory;
+using rocksdb::UserCollectedProperties;
+
+namespace {
+
+// GoTablePropertiesCollector calls into a Go TablePropertiesCollector
+// registered by handle. The handle is released when the collector is
+// destroyed.
+class GoTablePropertiesCollector : public TablePropertiesCollector {
+ public:
+  GoTablePropertiesCollector(uintptr_t handle, std::string name)
+      : handle_(handle), name_(std::move(name)) {}
+
+  ~GoTablePropertiesCollector() override {
+    gorocksdb_tablepropertiescollector_release(handle_);
+  }
+
+  Status AddUserKey(const Slice& key, const Slice& value, EntryType type,
+                    SequenceNumber seq, uint64_t file_size) override {
+    char* err = gorocksdb_tablepropertiescollector_add_user_key(
+        handle_, const_cast<char*>(key.data()), key.size(),
+        const_cast<char*>(value.data()), value.size(), static_cast<int>(type),
+        seq, file_size);
+    return ToStatus(err);
+  }
+
+  Status Finish(UserCollectedProperties* properties) override {
+    char* err = gorocksdb_tablepropertiescollector_finish(handle_, properties);
+    if (err == nullptr) {
+      properties_ = *properties;
+    }
+    return ToStatus(err);
+  }
+
+  UserCollectedProperties GetReadableProperties() const override {
+    return properties_;
+  }
+
+  const char* Name() const override { return name_.c_str(); }
+
+ private:
+  static Status ToStatus(char* err) {
+    if (err == nullptr) {
+      return Status::OK();
+    }
+    Status s = Status::Aborted(err);
+    free(err);
+    return s;
+  }
+
+  uintptr_t handle_;
+  std::string name_;
+  UserCollectedProperties properties_;
+};
+
+// GoTablePropertiesCollectorFactory calls into a Go
+// TablePropertiesCollectorFactory.
+class GoTablePropertiesCollectorFactory
+    : public TablePropertiesCollectorFactory {
+ public:
+  explicit GoTablePropertiesCollectorFactory(uintptr_t idx) : idx_(idx) {
+    char* name = gorocksdb_tablepropertiescollectorfactory_name(idx);
+    name_ = name;
+    free(name);
+  }
+
+  TablePropertiesCollector* CreateTablePropertiesCollector(
+      TablePropertiesCollectorFactory::Context context) override {
+    char* name = nullptr;
+    uintptr_t handle = gorocksdb_tablepropertiescollectorfactory_create(
+        idx_, context.column_family_id, &name);
+    if (handle == 0) {
+      return nullptr;
+    }
+    auto collector = new GoTablePropertiesCollector(handle, name);
+    free(name);
+    return collector;
+  }
+
+  const char* Name() const override { return name_.c_str(); }
+
+ private:
+  uintptr_t idx_;
+  std::string name_;
+};
+
+}  // namespace
+
+extern "C" {
+
+void gorocksdb_options_add_table_properties_collector_factory(
+    rocksdb_options_t* opts, uintptr_t idx) {
+  gorocksdb::RepValue<Options>(opts)
+      .table_properties_collector_factories.push_back(
+          std::make_shared<GoTablePropertiesCollectorFactory>(idx));
+}
+
+void gorocksdb_usercollectedproperties_add(void* properties, const char* key,
+                                           size_t key_len, const char* value,
+                                           size_t value_len) {
+  (*static_cast<UserCollectedProperties*>(properties))[std::string(
+      key, key_len)] = std::string(value, value_len);
+}
+
+}  // extern "C"
@@ -0,0 +1,114 @@
+package gorocksdb
+
+// #include "rocksdb/c.h"
+// #include "gorocksdb.h"
+import "C"
+
+import "unsafe"
+
+// EntryType is the type of an entry added to a sst file.
+type EntryType int
+
+// Entry types.
+const (
+	EntryPut                 = EntryType(0)
+	EntryDelete              = EntryType(1)
+	EntrySingleDelete        = EntryType(2)
+	EntryMerge               = EntryType(3)
+	EntryRangeDeletion       = EntryType(4)
+	EntryBlobIndex           = EntryType(5)
+	EntryDeleteWithTimestamp = EntryType(6)
+	EntryWideColumnEntity    = EntryType(7)
+	EntryOther               = EntryType(8)
+)
+
+// A TablePropertiesCollector collects user properties of a sst file while it
+// is written, e.g. the range of the event times of its entries. A new
+// collector is created for every file, so it does not need to be thread-safe.
+//
+// If the collector has a Destroy() method, it is called once the file is
+// written.
+type TablePropertiesCollector interface {
+	// AddUserKey is called for every entry added to the file. fileSize is
+	// the current size of the file. The application must not keep references
+	// to key and value after the call. A returned error is only logged by
+	// RocksDB: the file is still written.
+	AddUserKey(key, value []byte, entryType EntryType, seq, fileSize uint64) error
+
+	// Finish is called when the file is complete and returns the properties
+	// to store in it. A returned error is only logged by RocksDB: the file is
+	// still written, without the properties of this collector.
+	Finish() (map[string]string, error)
+
+	// The name of the collector, for logging
+	Name() string
+}
+
+// A TablePropertiesCollectorFactory creates a TablePropertiesCollector for
+// every sst file written.
+type TablePropertiesCollectorFactory interface {
+	// CreateTablePropertiesCollector returns the collector for a file of the
+	// column family with ID columnFamilyID, or nil if no properties need to
+	// be collected for it.
+	CreateTablePropertiesCollector(columnFamilyID uint32) TablePropertiesCollector
+
+	// The name of the factory, for logging
+	Name() string
+}
+
+// Hold references to table properties collector factories.
+var tablePropertiesCollectorFactories = NewCOWList()
+
+// Hold references to the table properties collectors while their file is written.
+var tablePropertiesCollectors = newRegistry()
+
+func registerTablePropertiesCollectorFactory(factory TablePropertiesCollectorFactory) int {
+	return tablePropertiesCollectorFactories.Append(factory)
+}
+
+//export gorocksdb_tablepropertiescollectorfactory_create
+func gorocksdb_tablepropertiescollectorfactory_create(idx int, cCFID C.uint32_t, cName **C.char) C.uintptr_t {
+	collector := tablePropertiesCollectorFactories.Get(idx).(TablePropertiesCollectorFactory).CreateTablePropertiesCollector(uint32(cCFID))
+	if collector == nil {
+		return 0
+	}
+	*cName = C.CString(collector.Name())
+	return C.uintptr_t(tablePropertiesCollectors.register(collector))
+}
+
+//export gorocksdb_tablepropertiescollectorfactory_name
+func gorocksdb_tablepropertiescollectorfactory_name(idx int) *C.char {
+	return C.CString(tablePropertiesCollectorFactories.Get(idx).(TablePropertiesCollectorFactory).Name())
+}
+
+//export gorocksdb_tablepropertiescollector_add_user_key
+func gorocksdb_tablepropertiescollector_add_user_key(handle C.uintptr_t, cKey *C.char, cKeyLen C.size_t, cVal *C.char, cValLen C.size_t, cType C.int, cSeq C.uint64_t, cFileSize C.uint64_t) *C.char {
+	key := charToByte(cKey, cKeyLen)
+	val := charToByte(cVal, cValLen)
+	collector := tablePropertiesCollectors.get(uintptr(handle)).(TablePropertiesCollector)
+	if err := collector.AddUserKey(key, val, EntryType(cType), uint64(cSeq), uint64(cFileSize)); err != nil {
+		return C.CString(err.Error())
+	}
+	return nil
+}
+
+//export gorocksdb_tablepropertiescollector_finish
+func gorocksdb_tablepropertiescollector_finish(handle C.uintptr_t, cProps unsafe.Pointer) *C.char {
+	collector := tablePropertiesCollectors.get(uintptr(handle)).(TablePropertiesCollector)
+	props, err := collector.Finish()
+	if err != nil {
+		return C.CString(err.Error())
+	}
+	for k, v := range props {
+		C.gorocksdb_usercollectedproperties_add(cProps, stringToChar(k), C.size_t(len(k)), stringToChar(v), C.size_t(len(v)))
+	}
+	return nil
+}
+
+//export gorocksdb_tablepropertiescollector_release
+func gorocksdb_tablepropertiescollector_release(handle C.uintptr_t) {
+	collector := tablePropertiesCollectors.release(uintptr(handle))
+	if d, ok := collector.(interface{ Destroy() }); ok {
+		d.Destroy()
+	}
+}
@@ -0,0 +1,101 @@
+package gorocksdb
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestTablePropertiesCollector(t *testing.T) {
+	db := newTestDB(t, "TestTablePropertiesCollector", func(opts *Options) {
+		opts.AddTablePropertiesCollectorFactory(&mockTablePropertiesCollectorFactory{})
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	ensure.Nil(t, db.Put(wo, []byte("a"), []byte("1")))
+	ensure.Nil(t, db.Put(wo, []byte("b"), []byte("2")))
+	ensure.Nil(t, db.Delete(wo, []byte("c")))
+	ensure.Nil(t, db.Flush(NewDefaultFlushOptions()))
+	ensure.Nil(t, db.Put(wo, []byte("x"), []byte("3")))
+	ensure.Nil(t, db.Flush(NewDefaultFlushOptions()))
+
+	all, err := db.GetPropertiesOfAllTables(nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(all), 2)
+	var puts int
+	for _, props := range all {
+		ensure.DeepEqual(t, props.ColumnFamilyName, "default")
+		n, err := strconv.Atoi(props.UserCollectedProperties["gorocksdb.test.puts"])
+		ensure.Nil(t, err)
+		puts += n
+	}
+	ensure.DeepEqual(t, puts, 3)
+
+	inRange, err := db.GetPropertiesOfTablesInRange(nil, []Range{{Start: []byte("a"), Limit: []byte("c")}})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(inRange), 1)
+	for _, props := range inRange {
+		ensure.DeepEqual(t, props.NumEntries, uint64(3))
+		ensure.DeepEqual(t, props.NumDeletions, uint64(1))
+		ensure.DeepEqual(t, props.UserCollectedProperties["gorocksdb.test.puts"], "2")
+		ensure.DeepEqual(t, props.UserCollectedProperties["gorocksdb.test.min-key"], "a")
+	}
+}
+
+func TestTablePropertiesCollectorNil(t *testing.T) {
+	db := newTestDB(t, "TestTablePropertiesCollectorNil", func(opts *Options) {
+		opts.AddTablePropertiesCollectorFactory(&mockTablePropertiesCollectorFactory{skip: true})
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	fo := NewDefaultFlushOptions()
+	defer fo.Destroy()
+	ensure.Nil(t, db.Put(wo, []byte("a"), []byte("1")))
+	ensure.Nil(t, db.Flush(fo))
+
+	all, err := db.GetPropertiesOfAllTables(nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(all), 1)
+	for _, props := range all {
+		_, ok := props.UserCollectedProperties["gorocksdb.test.puts"]
+		ensure.False(t, ok)
+	}
+}
+
+type mockTablePropertiesCollectorFactory struct {
+	skip bool
+}
+
+func (f *mockTablePropertiesCollectorFactory) Name() string { return "gorocksdb.test" }
+func (f *mockTablePropertiesCollectorFactory) CreateTablePropertiesCollector(columnFamilyID uint32) TablePropertiesCollector {
+	if f.skip {
+		return nil
+	}
+	return &mockTablePropertiesCollector{}
+}
+
+type mockTablePropertiesCollector struct {
+	puts   int
+	minKey []byte
+}
+
+func (c *mockTablePropertiesCollector) Name() string { return "gorocksdb.test" }
+func (c *mockTablePropertiesCollector) AddUserKey(key, value []byte, entryType EntryType, seq, fileSize uint64) error {
+	if entryType == EntryPut {
+		c.puts++
+	}
+	if c.minKey == nil {
+		c.minKey = append([]byte(nil), key...)
+	}
+	return nil
+}
+func (c *mockTablePropertiesCollector) Finish() (map[string]string, error) {
+	return map[string]string{
+		"gorocksdb.test.puts":    strconv.Itoa(c.puts),
+		"gorocksdb.test.min-key": string(c.minKey),
+	}, nil
+}
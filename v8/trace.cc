@@ -0,0 +1,273 @@
+#include "gorocksdb_internal.h"
+#include "rocksdb/trace_reader_writer.h"
+#include "rocksdb/trace_record.h"
+#include "rocksdb/trace_record_result.h"
+#include "rocksdb/utilities/replayer.h"
+
+using rocksdb::ColumnFamilyHandle;
+using rocksdb::DB;
+using rocksdb::EnvOptions;
+using rocksdb::Replayer;
+using rocksdb::Slice;
+using rocksdb::Status;
+using rocksdb::TraceOptions;
+using rocksdb::TraceReader;
+using rocksdb::TraceRecord;
+using rocksdb::TraceRecordResult;
+using rocksdb::TraceWriter;
+
+struct gorocksdb_replayer_t {
+  std::unique_ptr<Replayer> rep;
+};
+
+// gorocksdb_trace_record_t holds a decoded trace record and the keys it
+// reads, which point into the record.
+struct gorocksdb_trace_record_t {
+  std::unique_ptr<TraceRecord> rep;
+  std::vector<Slice> keys;
+};
+
+// gorocksdb_trace_result_t holds the outcome of an executed trace record,
+// flattened into one entry per key read.
+struct gorocksdb_trace_result_t {
+  std::unique_ptr<TraceRecordResult> rep;
+  std::vector<Status> statuses;
+  std::vector<Slice> keys;
+  std::vector<Slice> values;
+};
+
+namespace {
+
+TraceOptions NewTraceOptions(uint64_t max_trace_file_size,
+                             uint64_t sampling_frequency, uint64_t filter,
+                             unsigned char preserve_write_order) {
+  TraceOptions opts;
+  opts.max_trace_file_size = max_trace_file_size;
+  opts.sampling_frequency = sampling_frequency;
+  opts.filter = filter;
+  opts.preserve_write_order = preserve_write_order;
+  return opts;
+}
+
+std::vector<Slice> RecordKeys(const TraceRecord& record) {
+  switch (record.GetTraceType()) {
+    case rocksdb::kTraceGet:
+      return {static_cast<const rocksdb::GetQueryTraceRecord&>(record).GetKey()};
+    case rocksdb::kTraceIteratorSeek:
+    case rocksdb::kTraceIteratorSeekForPrev:
+      return {static_cast<const rocksdb::IteratorSeekQueryTraceRecord&>(record)
+                  .GetKey()};
+    case rocksdb::kTraceMultiGet:
+      return static_cast<const rocksdb::MultiGetQueryTraceRecord&>(record)
+          .GetKeys();
+    default:
+      return {};
+  }
+}
+
+// FlattenResult fills the entries of result from its TraceRecordResult. The
+// result classes are told apart by their trace type, as RocksDB may be built
+// without RTTI.
+void FlattenResult(gorocksdb_trace_result_t* result) {
+  const TraceRecordResult& rep = *result->rep;
+  switch (rep.GetTraceType()) {
+    case rocksdb::kTraceGet: {
+      const auto& r =
+          static_cast<const rocksdb::SingleValueTraceExecutionResult&>(rep);
+      result->statuses.push_back(r.GetStatus());
+      result->keys.emplace_back();
+      result->values.emplace_back(r.GetValue());
+      break;
+    }
+    case rocksdb::kTraceMultiGet: {
+      const auto& r =
+          static_cast<const rocksdb::MultiValuesTraceExecutionResult&>(rep);
+      result->statuses = r.GetMultiStatus();
+      for (const std::string& value : r.GetValues()) {
+        result->keys.emplace_back();
+        result->values.emplace_back(value);
+      }
+      break;
+    }
+    case rocksdb::kTraceIteratorSeek:
+    case rocksdb::kTraceIteratorSeekForPrev: {
+      const auto& r =
+          static_cast<const rocksdb::IteratorTraceExecutionResult&>(rep);
+      result->statuses.push_back(r.GetValid() || !r.GetStatus().ok()
+                                     ? r.GetStatus()
+                                     : Status::NotFound());
+      result->keys.push_back(r.GetKey());
+      result->values.push_back(r.GetValue());
+      break;
+    }
+    default: {
+      const auto& r =
+          static_cast<const rocksdb::StatusOnlyTraceExecutionResult&>(rep);
+      result->statuses.push_back(r.GetStatus());
+      result->keys.emplace_back();
+      result->values.emplace_back();
+      break;
+    }
+  }
+}
+
+}  // namespace
+
+extern "C" {
+
+void gorocksdb_start_trace(rocksdb_t* db, uint64_t max_trace_file_size,
+                           uint64_t sampling_frequency, uint64_t filter,
+                           unsigned char preserve_write_order,
+                           unsigned char block_cache, const char* path,
+                           char** errptr) {
+  DB* rep = gorocksdb::Rep<DB>(db);
+  std::unique_ptr<TraceWriter> writer;
+  Status s = rocksdb::NewFileTraceWriter(rep->GetEnv(), EnvOptions(), path,
+                                         &writer);
+  if (gorocksdb::SaveError(errptr, s)) {
+    return;
+  }
+  TraceOptions opts = NewTraceOptions(max_trace_file_size, sampling_frequency,
+                                      filter, preserve_write_order);
+  if (block_cache) {
+    s = rep->StartBlockCacheTrace(opts, std::move(writer));
+  } else {
+    s = rep->StartTrace(opts, std::move(writer));
+  }
+  gorocksdb::SaveError(errptr, s);
+}
+
+void gorocksdb_end_trace(rocksdb_t* db, unsigned char block_cache,
+                         char** errptr) {
+  DB* rep = gorocksdb::Rep<DB>(db);
+  gorocksdb::SaveError(errptr, block_cache ? rep->EndBlockCacheTrace()
+                                           : rep->EndTrace());
+}
+
+gorocksdb_replayer_t* gorocksdb_replayer_create(
+    rocksdb_t* db, rocksdb_column_family_handle_t** cfs, size_t num_cfs,
+    const char* path, char** errptr) {
+  DB* rep = gorocksdb::Rep<DB>(db);
+  std::vector<ColumnFamilyHandle*> handles;
+  for (size_t i = 0; i < num_cfs; i++) {
+    handles.push_back(gorocksdb::Rep<ColumnFamilyHandle>(cfs[i]));
+  }
+  if (handles.empty()) {
+    handles.push_back(rep->DefaultColumnFamily());
+  }
+  std::unique_ptr<TraceReader> reader;
+  Status s = rocksdb::NewFileTraceReader(rep->GetEnv(), EnvOptions(), path,
+                                         &reader);
+  if (gorocksdb::SaveError(errptr, s)) {
+    return nullptr;
+  }
+  std::unique_ptr<Replayer> replayer;
+  s = rep->NewDefaultReplayer(handles, std::move(reader), &replayer);
+  if (gorocksdb::SaveError(errptr, s)) {
+    return nullptr;
+  }
+  return new gorocksdb_replayer_t{std::move(replayer)};
+}
+
+void gorocksdb_replayer_prepare(gorocksdb_replayer_t* replayer,
+                                char** errptr) {
+  gorocksdb::SaveError(errptr, replayer->rep->Prepare());
+}
+
+uint64_t gorocksdb_replayer_header_timestamp(gorocksdb_replayer_t* replayer) {
+  return replayer->rep->GetHeaderTimestamp();
+}
+
+gorocksdb_trace_record_t* gorocksdb_replayer_next(
+    gorocksdb_replayer_t* replayer, char** errptr) {
+  std::unique_ptr<TraceRecord> record;
+  Status s;
+  do {
+    s = replayer->rep->Next(&record);
+    // Records of types the replayer cannot execute are skipped.
+  } while (s.code() == Status::kNotSupported);
+  if (s.IsIncomplete()) {
+    return nullptr;
+  }
+  if (gorocksdb::SaveError(errptr, s)) {
+    return nullptr;
+  }
+  std::vector<Slice> keys = RecordKeys(*record);
+  return new gorocksdb_trace_record_t{std::move(record), std::move(keys)};
+}
+
+gorocksdb_trace_result_t* gorocksdb_replayer_execute(
+    gorocksdb_replayer_t* replayer, gorocksdb_trace_record_t* record,
+    char** errptr) {
+  std::unique_ptr<TraceRecordResult> rep;
+  Status s = replayer->rep->Execute(record->rep, &rep);
+  if (gorocksdb::SaveError(errptr, s)) {
+    return nullptr;
+  }
+  auto* result = new gorocksdb_trace_result_t;
+  result->rep = std::move(rep);
+  if (result->rep != nullptr) {
+    FlattenResult(result);
+  }
+  return result;
+}
+
+void gorocksdb_replayer_destroy(gorocksdb_replayer_t* replayer) {
+  delete replayer;
+}
+
+int gorocksdb_trace_type_write() { return rocksdb::kTraceWrite; }
+
+int gorocksdb_trace_type_get() { return rocksdb::kTraceGet; }
+
+int gorocksdb_trace_type_iterator_seek() { return rocksdb::kTraceIteratorSeek; }
+
+int gorocksdb_trace_type_iterator_seek_for_prev() {
+  return rocksdb::kTraceIteratorSeekForPrev;
+}
+
+int gorocksdb_trace_type_multiget() { return rocksdb::kTraceMultiGet; }
+
+int gorocksdb_trace_record_type(gorocksdb_trace_record_t* record) {
+  return record->rep->GetTraceType();
+}
+
+uint64_t gorocksdb_trace_record_timestamp(gorocksdb_trace_record_t* record) {
+  return record->rep->GetTimestamp();
+}
+
+size_t gorocksdb_trace_record_num_keys(gorocksdb_trace_record_t* record) {
+  return record->keys.size();
+}
+
+const char* gorocksdb_trace_record_key(gorocksdb_trace_record_t* record,
+                                       size_t i, size_t* len) {
+  *len = record->keys[i].size();
+  return record->keys[i].data();
+}
+
+void gorocksdb_trace_record_destroy(gorocksdb_trace_record_t* record) {
+  delete record;
+}
+
+size_t gorocksdb_trace_result_count(gorocksdb_trace_result_t* result) {
+  return result->statuses.size();
+}
+
+int gorocksdb_trace_result_get(gorocksdb_trace_result_t* result, size_t i,
+                               const char** key, size_t* key_len,
+                               const char** value, size_t* value_len,
+                               char** errptr) {
+  *key = result->keys[i].data();
+  *key_len = result->keys[i].size();
+  *value = result->values[i].data();
+  *value_len = result->values[i].size();
+  gorocksdb::SaveError(errptr, result->statuses[i]);
+  return result->statuses[i].code();
+}
+
+void gorocksdb_trace_result_destroy(gorocksdb_trace_result_t* result) {
+  delete result;
+}
+
+}  // extern "C"
@@ -0,0 +1,98 @@
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+// #include "gorocksdb.h"
+import "C"
+import (
+	"errors"
+	"unsafe"
+)
+
+// TraceFilter selects the operations left out of a trace.
+type TraceFilter uint64
+
+const (
+	// TraceFilterNone traces all the operations.
+	TraceFilterNone TraceFilter = 0
+	// TraceFilterGet leaves out Get operations.
+	TraceFilterGet TraceFilter = 1 << 0
+	// TraceFilterWrite leaves out writes.
+	TraceFilterWrite TraceFilter = 1 << 1
+	// TraceFilterIteratorSeek leaves out iterator Seek operations.
+	TraceFilterIteratorSeek TraceFilter = 1 << 2
+	// TraceFilterIteratorSeekForPrev leaves out iterator SeekForPrev operations.
+	TraceFilterIteratorSeekForPrev TraceFilter = 1 << 3
+	// TraceFilterMultiGet leaves out MultiGet operations.
+	TraceFilterMultiGet TraceFilter = 1 << 4
+)
+
+// TraceOptions are the options of a trace.
+type TraceOptions struct {
+	// MaxTraceFileSize is the size in bytes at which tracing stops.
+	MaxTraceFileSize uint64
+	// SamplingFrequency traces one of every SamplingFrequency operations.
+	SamplingFrequency uint64
+	// Filter is the set of operations not traced.
+	Filter TraceFilter
+	// PreserveWriteOrder records the writes in the order they are applied
+	// rather than in the order they are submitted, at some throughput cost.
+	PreserveWriteOrder bool
+}
+
+// NewDefaultTraceOptions returns the default trace options, which trace all
+// the operations into a file of up to 64GB.
+func NewDefaultTraceOptions() TraceOptions {
+	return TraceOptions{
+		MaxTraceFileSize:  64 << 30,
+		SamplingFrequency: 1,
+	}
+}
+
+// StartTrace starts recording the operations of the database into the trace
+// file at path, which can then be replayed with a Replayer.
+func (db *DB) StartTrace(opts TraceOptions, path string) error {
+	return db.startTrace(opts, path, false)
+}
+
+// EndTrace stops the trace started by StartTrace.
+func (db *DB) EndTrace() error {
+	return db.endTrace(false)
+}
+
+// StartBlockCacheTrace starts recording the block cache accesses of the
+// database into the trace file at path, to be analyzed with the
+// block_cache_trace_analyzer tool of RocksDB.
+func (db *DB) StartBlockCacheTrace(opts TraceOptions, path string) error {
+	return db.startTrace(opts, path, true)
+}
+
+// EndBlockCacheTrace stops the trace started by StartBlockCacheTrace.
+func (db *DB) EndBlockCacheTrace() error {
+	return db.endTrace(true)
+}
+
+func (db *DB) startTrace(opts TraceOptions, path string, blockCache bool) error {
+	var (
+		cErr  *C.char
+		cPath = C.CString(path)
+	)
+	defer C.free(unsafe.Pointer(cPath))
+	C.gorocksdb_start_trace(db.c, C.uint64_t(opts.MaxTraceFileSize), C.uint64_t(opts.SamplingFrequency),
+		C.uint64_t(opts.Filter), boolToChar(opts.PreserveWriteOrder), boolToChar(blockCache), cPath, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
+
+func (db *DB) endTrace(blockCache bool) error {
+	var cErr *C.char
+	C.gorocksdb_end_trace(db.c, boolToChar(blockCache), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package gorocksdb
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestTraceReplay(t *testing.T) {
+	db := newTestDB(t, "TestTraceReplay", nil)
+	defer db.Close()
+
+	dir, err := ioutil.TempDir("", "gorocksdb-TestTraceReplay-trace")
+	ensure.Nil(t, err)
+	defer os.RemoveAll(dir)
+	tracePath := filepath.Join(dir, "trace")
+
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	ro := NewDefaultReadOptions()
+	defer ro.Destroy()
+
+	ensure.Nil(t, db.StartTrace(NewDefaultTraceOptions(), tracePath))
+	for i := 0; i < 10; i++ {
+		ensure.Nil(t, db.Put(wo, []byte(fmt.Sprintf("key-%02d", i)), []byte("value")))
+	}
+	for i := 0; i < 5; i++ {
+		v, err := db.Get(ro, []byte(fmt.Sprintf("key-%02d", i)))
+		ensure.Nil(t, err)
+		v.Free()
+	}
+	v, err := db.Get(ro, []byte("missing"))
+	ensure.Nil(t, err)
+	v.Free()
+	values, err := db.MultiGet(ro, []byte("key-00"), []byte("key-01"))
+	ensure.Nil(t, err)
+	values.Destroy()
+	ensure.Nil(t, db.EndTrace())
+
+	target := newTestDB(t, "TestTraceReplayTarget", nil)
+	defer target.Close()
+	reference := newTestDB(t, "TestTraceReplayReference", nil)
+	defer reference.Close()
+	ensure.Nil(t, reference.Put(wo, []byte("missing"), []byte("found")))
+
+	replayer, err := NewReplayer(target, nil, tracePath)
+	ensure.Nil(t, err)
+	defer replayer.Destroy()
+
+	// without a reference DB
+	opts := NewDefaultReplayOptions()
+	opts.Speed = 0
+	opts.Concurrency = 4
+	report, err := replayer.Replay(opts)
+	ensure.Nil(t, err)
+	ensure.Nil(t, report.FirstError)
+	ensure.DeepEqual(t, report.Errors, 0)
+	ensure.DeepEqual(t, report.Mismatches, 0)
+	ensure.DeepEqual(t, report.Ops[TraceWrite].Count, 10)
+	ensure.DeepEqual(t, report.Ops[TraceGet].Count, 6)
+	ensure.DeepEqual(t, report.Ops[TraceMultiGet].Count, 1)
+	ensure.DeepEqual(t, TraceMultiGet.String(), "MultiGet")
+	ensure.True(t, report.Ops[TraceGet].Latency(99) >= report.Ops[TraceGet].Latency(50))
+	ensure.True(t, report.Ops[TraceGet].MeanLatency() > 0)
+
+	v, err = target.Get(ro, []byte("key-09"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v.Data(), []byte("value"))
+	v.Free()
+
+	// with a reference DB holding a key the traced DB did not have
+	ensure.Nil(t, replayer.SetReference(reference, nil))
+	var mismatches []*ReplayMismatch
+	opts.Concurrency = 1
+	opts.OnMismatch = func(m *ReplayMismatch) {
+		mismatches = append(mismatches, m)
+	}
+	report, err = replayer.Replay(opts)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, report.Records, 17)
+	ensure.DeepEqual(t, report.Mismatches, 1)
+	ensure.DeepEqual(t, report.Ops[TraceGet].Mismatches, 1)
+	ensure.DeepEqual(t, len(mismatches), 1)
+	ensure.DeepEqual(t, mismatches[0].Type, TraceGet)
+	ensure.DeepEqual(t, mismatches[0].Keys, [][]byte{[]byte("missing")})
+	ensure.False(t, mismatches[0].Got[0].Found)
+	ensure.True(t, mismatches[0].Want[0].Found)
+	ensure.DeepEqual(t, mismatches[0].Want[0].Value, []byte("found"))
+}
+
+func TestBlockCacheTrace(t *testing.T) {
+	db := newTestDB(t, "TestBlockCacheTrace", nil)
+	defer db.Close()
+
+	dir, err := ioutil.TempDir("", "gorocksdb-TestBlockCacheTrace-trace")
+	ensure.Nil(t, err)
+	defer os.RemoveAll(dir)
+	tracePath := filepath.Join(dir, "block_cache_trace")
+
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	ro := NewDefaultReadOptions()
+	defer ro.Destroy()
+	fo := NewDefaultFlushOptions()
+	defer fo.Destroy()
+
+	ensure.Nil(t, db.Put(wo, []byte("key"), []byte("value")))
+	ensure.Nil(t, db.Flush(fo))
+
+	ensure.Nil(t, db.StartBlockCacheTrace(NewDefaultTraceOptions(), tracePath))
+	v, err := db.Get(ro, []byte("key"))
+	ensure.Nil(t, err)
+	v.Free()
+	ensure.Nil(t, db.EndBlockCacheTrace())
+
+	info, err := os.Stat(tracePath)
+	ensure.Nil(t, err)
+	ensure.True(t, info.Size() > 0)
+}
@@ -2,6 +2,7 @@ package gorocksdb
 
 // #include <stdlib.h>
 // #include "rocksdb/c.h"
+// #include "gorocksdb.h"
 import "C"
 
 import (
@@ -11,23 +12,27 @@ import (
 
 // Transaction is used with TransactionDB for transaction support.
 type Transaction struct {
-	c *C.rocksdb_transaction_t
+	c     *C.rocksdb_transaction_t
+	hooks *hookSet
 }
 
 // NewNativeTransaction creates a Transaction object.
 func NewNativeTransaction(c *C.rocksdb_transaction_t) *Transaction {
-	return &Transaction{c}
+	return &Transaction{c: c}
 }
 
 // Commit commits the transaction to the database.
-func (transaction *Transaction) Commit() error {
+func (transaction *Transaction) Commit() (err error) {
+	if call := transaction.hooks.beginOp(OpCommit, nil, 0, 0, 0); call != nil {
+		defer call.end(&err)
+	}
 	var (
 		cErr *C.char
 	)
-	C.rocksdb_transaction_commit(transaction.c, &cErr)
+	noSpace := C.gorocksdb_transaction_commit(transaction.c, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newWriteError(C.GoString(cErr), noSpace)
 	}
 	return nil
 }
@@ -47,7 +52,10 @@ func (transaction *Transaction) Rollback() error {
 }
 
 // Get returns the data associated with the key from the database given this transaction.
-func (transaction *Transaction) Get(opts *ReadOptions, key []byte) (*Slice, error) {
+func (transaction *Transaction) Get(opts *ReadOptions, key []byte) (_ *Slice, err error) {
+	if call := transaction.hooks.beginOp(OpGet, nil, len(key), 0, 1); call != nil {
+		defer call.end(&err)
+	}
 	var (
 		cErr    *C.char
 		cValLen C.size_t
@@ -64,7 +72,10 @@ func (transaction *Transaction) Get(opts *ReadOptions, key []byte) (*Slice, erro
 }
 
 // GetCF returns the data associated with the key in a given column family from the database given this transaction.
-func (transaction *Transaction) GetCF(opts *ReadOptions, cf *ColumnFamilyHandle, key []byte) (*Slice, error) {
+func (transaction *Transaction) GetCF(opts *ReadOptions, cf *ColumnFamilyHandle, key []byte) (_ *Slice, err error) {
+	if call := transaction.hooks.beginOp(OpGet, cf, len(key), 0, 1); call != nil {
+		defer call.end(&err)
+	}
 	var (
 		cErr    *C.char
 		cValLen C.size_t
@@ -81,7 +92,10 @@ func (transaction *Transaction) GetCF(opts *ReadOptions, cf *ColumnFamilyHandle,
 }
 
 // GetForUpdate queries the data associated with the key and puts an exclusive lock on the key from the database given this transaction.
-func (transaction *Transaction) GetForUpdate(opts *ReadOptions, key []byte) (*Slice, error) {
+func (transaction *Transaction) GetForUpdate(opts *ReadOptions, key []byte) (_ *Slice, err error) {
+	if call := transaction.hooks.beginOp(OpGetForUpdate, nil, len(key), 0, 1); call != nil {
+		defer call.end(&err)
+	}
 	var (
 		cErr    *C.char
 		cValLen C.size_t
@@ -99,7 +113,10 @@ func (transaction *Transaction) GetForUpdate(opts *ReadOptions, key []byte) (*Sl
 
 // GetForUpdateCF queries the data associated with the key in a given column family
 // and puts an exclusive lock on the key from the database given this transaction.
-func (transaction *Transaction) GetForUpdateCF(opts *ReadOptions, cf *ColumnFamilyHandle, key []byte) (*Slice, error) {
+func (transaction *Transaction) GetForUpdateCF(opts *ReadOptions, cf *ColumnFamilyHandle, key []byte) (_ *Slice, err error) {
+	if call := transaction.hooks.beginOp(OpGetForUpdate, cf, len(key), 0, 1); call != nil {
+		defer call.end(&err)
+	}
 	var (
 		cErr    *C.char
 		cValLen C.size_t
@@ -118,7 +135,10 @@ func (transaction *Transaction) GetForUpdateCF(opts *ReadOptions, cf *ColumnFami
 // GetPinnedForUpdateCF queries the data associated with the key in a given column family
 // and puts an exclusive lock on the key from the database given this transaction.
 // It uses a pinnable slice to improve performance by avoiding a memcpy.
-func (transaction *Transaction) GetPinnedForUpdateCF(opts *ReadOptions, cf *ColumnFamilyHandle, key []byte) (*PinnableSliceHandle, error) {
+func (transaction *Transaction) GetPinnedForUpdateCF(opts *ReadOptions, cf *ColumnFamilyHandle, key []byte) (_ *PinnableSliceHandle, err error) {
+	if call := transaction.hooks.beginOp(OpGetForUpdate, cf, len(key), 0, 1); call != nil {
+		defer call.end(&err)
+	}
 	var (
 		cErr *C.char
 		cKey = byteToChar(key)
@@ -135,63 +155,75 @@ func (transaction *Transaction) GetPinnedForUpdateCF(opts *ReadOptions, cf *Colu
 }
 
 // Put writes data associated with a key to the transaction.
-func (transaction *Transaction) Put(key, value []byte) error {
+func (transaction *Transaction) Put(key, value []byte) (err error) {
+	if call := transaction.hooks.beginOp(OpPut, nil, len(key), len(value), 1); call != nil {
+		defer call.end(&err)
+	}
 	var (
 		cErr   *C.char
 		cKey   = byteToChar(key)
 		cValue = byteToChar(value)
 	)
-	C.rocksdb_transaction_put(
+	noSpace := C.gorocksdb_transaction_put(
 		transaction.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr,
 	)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newWriteError(C.GoString(cErr), noSpace)
 	}
 	return nil
 }
 
 // PutCF writes data associated with a key in a given family to the transaction.
-func (transaction *Transaction) PutCF(cf *ColumnFamilyHandle, key, value []byte) error {
+func (transaction *Transaction) PutCF(cf *ColumnFamilyHandle, key, value []byte) (err error) {
+	if call := transaction.hooks.beginOp(OpPut, cf, len(key), len(value), 1); call != nil {
+		defer call.end(&err)
+	}
 	var (
 		cErr   *C.char
 		cKey   = byteToChar(key)
 		cValue = byteToChar(value)
 	)
-	C.rocksdb_transaction_put_cf(
+	noSpace := C.gorocksdb_transaction_put_cf(
 		transaction.c, cf.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr,
 	)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newWriteError(C.GoString(cErr), noSpace)
 	}
 	return nil
 }
 
 // Delete removes the data associated with the key from the transaction.
-func (transaction *Transaction) Delete(key []byte) error {
+func (transaction *Transaction) Delete(key []byte) (err error) {
+	if call := transaction.hooks.beginOp(OpDelete, nil, len(key), 0, 1); call != nil {
+		defer call.end(&err)
+	}
 	var (
 		cErr *C.char
 		cKey = byteToChar(key)
 	)
-	C.rocksdb_transaction_delete(transaction.c, cKey, C.size_t(len(key)), &cErr)
+	noSpace := C.gorocksdb_transaction_delete(transaction.c, cKey, C.size_t(len(key)), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newWriteError(C.GoString(cErr), noSpace)
 	}
 	return nil
 }
 
 // DeleteCF removes the data in a given column family associated with the key from the transaction.
-func (transaction *Transaction) DeleteCF(cf *ColumnFamilyHandle, key []byte) error {
+func (transaction *Transaction) DeleteCF(cf *ColumnFamilyHandle, key []byte) (err error) {
+	if call := transaction.hooks.beginOp(OpDelete, cf, len(key), 0, 1); call != nil {
+		defer call.end(&err)
+	}
 	var (
 		cErr *C.char
 		cKey = byteToChar(key)
 	)
-	C.rocksdb_transaction_delete_cf(transaction.c, cf.c, cKey, C.size_t(len(key)), &cErr)
+	noSpace := C.gorocksdb_transaction_delete_cf(transaction.c, cf.c, cKey, C.size_t(len(key)), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newWriteError(C.GoString(cErr), noSpace)
 	}
 	return nil
 }
@@ -199,13 +231,17 @@ func (transaction *Transaction) DeleteCF(cf *ColumnFamilyHandle, key []byte) err
 // NewIterator returns an Iterator over the database that uses the
 // ReadOptions given.
 func (transaction *Transaction) NewIterator(opts *ReadOptions) *Iterator {
-	return NewNativeIterator(unsafe.Pointer(C.rocksdb_transaction_create_iterator(transaction.c, opts.c)))
+	iter := NewNativeIterator(unsafe.Pointer(C.rocksdb_transaction_create_iterator(transaction.c, opts.c)))
+	iter.hooks = transaction.hooks
+	return iter
 }
 
 // NewIteratorCF returns an Iterator over the column family that uses the
 // ReadOptions given.
 func (transaction *Transaction) NewIteratorCF(opts *ReadOptions, cf *ColumnFamilyHandle) *Iterator {
-	return NewNativeIterator(unsafe.Pointer(C.rocksdb_transaction_create_iterator_cf(transaction.c, opts.c, cf.c)))
+	iter := NewNativeIterator(unsafe.Pointer(C.rocksdb_transaction_create_iterator_cf(transaction.c, opts.c, cf.c)))
+	iter.hooks, iter.cf = transaction.hooks, cf
+	return iter
 }
 
 // Destroy deallocates the transaction object.
@@ -2,6 +2,7 @@ package gorocksdb
 
 // #include <stdlib.h>
 // #include "rocksdb/c.h"
+// #include "gorocksdb.h"
 import "C"
 import (
 	"errors"
@@ -14,6 +15,8 @@ type TransactionDB struct {
 	name              string
 	opts              *Options
 	transactionDBOpts *TransactionDBOptions
+	hooks             hookList
+	errorListener     *errorListener
 }
 
 // OpenTransactionDb opens a database with the specified options.
@@ -27,10 +30,13 @@ func OpenTransactionDb(
 		cName = C.CString(name)
 	)
 	defer C.free(unsafe.Pointer(cName))
+	cDBOpts, errorListener, freeOpts := opts.openOptions()
+	defer freeOpts()
 	db := C.rocksdb_transactiondb_open(
-		opts.c, transactionDBOpts.c, cName, &cErr)
+		cDBOpts, transactionDBOpts.c, cName, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		errorListener.release()
 		return nil, errors.New(C.GoString(cErr))
 	}
 	return &TransactionDB{
@@ -38,6 +44,7 @@ func OpenTransactionDb(
 		c:                 db,
 		opts:              opts,
 		transactionDBOpts: transactionDBOpts,
+		errorListener:     errorListener,
 	}, nil
 }
 
@@ -75,8 +82,10 @@ func OpenTransactionDbColumnFamilies(
 	cHandles := make([]*C.rocksdb_column_family_handle_t, numColumnFamilies)
 
 	var cErr *C.char
+	cDBOpts, errorListener, freeOpts := opts.openOptions()
+	defer freeOpts()
 	db := C.rocksdb_transactiondb_open_column_families(
-		opts.c,
+		cDBOpts,
 		transactionDBOpts.c,
 		cName,
 		C.int(numColumnFamilies),
@@ -87,6 +96,7 @@ func OpenTransactionDbColumnFamilies(
 	)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		errorListener.release()
 		return nil, nil, errors.New(C.GoString(cErr))
 	}
 
@@ -96,9 +106,10 @@ func OpenTransactionDbColumnFamilies(
 	}
 
 	return &TransactionDB{
-		name: name,
-		c:    db,
-		opts: opts,
+		name:          name,
+		c:             db,
+		opts:          opts,
+		errorListener: errorListener,
 	}, cfHandles, nil
 }
 
@@ -148,21 +159,27 @@ func (db *TransactionDB) TransactionBegin(
 	transactionOpts *TransactionOptions,
 	oldTransaction *Transaction,
 ) *Transaction {
+	var transaction *Transaction
 	if oldTransaction != nil {
-		return NewNativeTransaction(C.rocksdb_transaction_begin(
+		transaction = NewNativeTransaction(C.rocksdb_transaction_begin(
 			db.c,
 			opts.c,
 			transactionOpts.c,
 			oldTransaction.c,
 		))
+	} else {
+		transaction = NewNativeTransaction(C.rocksdb_transaction_begin(
+			db.c, opts.c, transactionOpts.c, nil))
 	}
-
-	return NewNativeTransaction(C.rocksdb_transaction_begin(
-		db.c, opts.c, transactionOpts.c, nil))
+	transaction.hooks = db.hooks.load()
+	return transaction
 }
 
 // Get returns the data associated with the key from the database.
-func (db *TransactionDB) Get(opts *ReadOptions, key []byte) (*Slice, error) {
+func (db *TransactionDB) Get(opts *ReadOptions, key []byte) (_ *Slice, err error) {
+	if call := beginOp(&db.hooks, OpGet, nil, len(key), 0, 1); call != nil {
+		defer call.end(&err)
+	}
 	var (
 		cErr    *C.char
 		cValLen C.size_t
@@ -179,7 +196,10 @@ func (db *TransactionDB) Get(opts *ReadOptions, key []byte) (*Slice, error) {
 }
 
 // GetCF returns the data associated with the key in a given column family from the database.
-func (db *TransactionDB) GetCF(opts *ReadOptions, cf *ColumnFamilyHandle, key []byte) (*Slice, error) {
+func (db *TransactionDB) GetCF(opts *ReadOptions, cf *ColumnFamilyHandle, key []byte) (_ *Slice, err error) {
+	if call := beginOp(&db.hooks, OpGet, cf, len(key), 0, 1); call != nil {
+		defer call.end(&err)
+	}
 	var (
 		cErr    *C.char
 		cValLen C.size_t
@@ -195,72 +215,89 @@ func (db *TransactionDB) GetCF(opts *ReadOptions, cf *ColumnFamilyHandle, key []
 }
 
 // Put writes data associated with a key to the database.
-func (db *TransactionDB) Put(opts *WriteOptions, key, value []byte) error {
+func (db *TransactionDB) Put(opts *WriteOptions, key, value []byte) (err error) {
+	if call := beginOp(&db.hooks, OpPut, nil, len(key), len(value), 1); call != nil {
+		defer call.end(&err)
+	}
 	var (
 		cErr   *C.char
 		cKey   = byteToChar(key)
 		cValue = byteToChar(value)
 	)
-	C.rocksdb_transactiondb_put(
+	noSpace := C.gorocksdb_transactiondb_put(
 		db.c, opts.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr,
 	)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newWriteError(C.GoString(cErr), noSpace)
 	}
 	return nil
 }
 
 // PutCF writes data associated with a key to the database and column family.
-func (db *TransactionDB) PutCF(opts *WriteOptions, cf *ColumnFamilyHandle, key, value []byte) error {
+func (db *TransactionDB) PutCF(opts *WriteOptions, cf *ColumnFamilyHandle, key, value []byte) (err error) {
+	if call := beginOp(&db.hooks, OpPut, cf, len(key), len(value), 1); call != nil {
+		defer call.end(&err)
+	}
 	var (
 		cErr   *C.char
 		cKey   = byteToChar(key)
 		cValue = byteToChar(value)
 	)
-	C.rocksdb_transactiondb_put_cf(db.c, opts.c, cf.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
+	noSpace := C.gorocksdb_transactiondb_put_cf(db.c, opts.c, cf.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newWriteError(C.GoString(cErr), noSpace)
 	}
 	return nil
 }
 
 // Write writes a WriteBatch to the database
-func (db *TransactionDB) Write(opts *WriteOptions, batch *WriteBatch) error {
+func (db *TransactionDB) Write(opts *WriteOptions, batch *WriteBatch) (err error) {
+	if set := db.hooks.load(); set != nil {
+		if call := set.beginOp(OpWrite, nil, 0, len(batch.Data()), batch.Count()); call != nil {
+			defer call.end(&err)
+		}
+	}
 	var cErr *C.char
-	C.rocksdb_transactiondb_write(db.c, opts.c, batch.c, &cErr)
+	noSpace := C.gorocksdb_transactiondb_write(db.c, opts.c, batch.c, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newWriteError(C.GoString(cErr), noSpace)
 	}
 	return nil
 }
 
 // Delete removes the data associated with the key from the database.
-func (db *TransactionDB) Delete(opts *WriteOptions, key []byte) error {
+func (db *TransactionDB) Delete(opts *WriteOptions, key []byte) (err error) {
+	if call := beginOp(&db.hooks, OpDelete, nil, len(key), 0, 1); call != nil {
+		defer call.end(&err)
+	}
 	var (
 		cErr *C.char
 		cKey = byteToChar(key)
 	)
-	C.rocksdb_transactiondb_delete(db.c, opts.c, cKey, C.size_t(len(key)), &cErr)
+	noSpace := C.gorocksdb_transactiondb_delete(db.c, opts.c, cKey, C.size_t(len(key)), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newWriteError(C.GoString(cErr), noSpace)
 	}
 	return nil
 }
 
 // DeleteCF removes the data associated with the key from the database and column family.
-func (db *TransactionDB) DeleteCF(opts *WriteOptions, cf *ColumnFamilyHandle, key []byte) error {
+func (db *TransactionDB) DeleteCF(opts *WriteOptions, cf *ColumnFamilyHandle, key []byte) (err error) {
+	if call := beginOp(&db.hooks, OpDelete, cf, len(key), 0, 1); call != nil {
+		defer call.end(&err)
+	}
 	var (
 		cErr *C.char
 		cKey = byteToChar(key)
 	)
-	C.rocksdb_transactiondb_delete_cf(db.c, opts.c, cf.c, cKey, C.size_t(len(key)), &cErr)
+	noSpace := C.gorocksdb_transactiondb_delete_cf(db.c, opts.c, cf.c, cKey, C.size_t(len(key)), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newWriteError(C.GoString(cErr), noSpace)
 	}
 	return nil
 }
@@ -284,13 +321,17 @@ func (db *TransactionDB) NewCheckpoint() (*Checkpoint, error) {
 // NewIterator returns an Iterator over the database that uses the
 // ReadOptions given.
 func (db *TransactionDB) NewIterator(opts *ReadOptions) *Iterator {
-	return NewNativeIterator(unsafe.Pointer(C.rocksdb_transactiondb_create_iterator(db.c, opts.c)))
+	iter := NewNativeIterator(unsafe.Pointer(C.rocksdb_transactiondb_create_iterator(db.c, opts.c)))
+	iter.hooks = db.hooks.load()
+	return iter
 }
 
 // NewIteratorCF returns an Iterator over the column family that uses the
 // ReadOptions given.
 func (db *TransactionDB) NewIteratorCF(opts *ReadOptions, cf *ColumnFamilyHandle) *Iterator {
-	return NewNativeIterator(unsafe.Pointer(C.rocksdb_transactiondb_create_iterator_cf(db.c, opts.c, cf.c)))
+	iter := NewNativeIterator(unsafe.Pointer(C.rocksdb_transactiondb_create_iterator_cf(db.c, opts.c, cf.c)))
+	iter.hooks, iter.cf = db.hooks.load(), cf
+	return iter
 }
 
 // UnsafeGetDB returns the underlying c rocksdb instance.
@@ -302,4 +343,5 @@ func (db *TransactionDB) UnsafeGetDB() unsafe.Pointer {
 func (db *TransactionDB) Close() {
 	C.rocksdb_transactiondb_close(db.c)
 	db.c = nil
+	db.errorListener.release()
 }
@@ -0,0 +1,123 @@
+package gorocksdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// ttlMagic marks the values encoded by a TTLCodec. It follows the expiry.
+const ttlMagic = "\xfeTTL"
+
+// ttlSuffixLen is the length of the expiry timestamp and of the marker stored
+// after the value.
+const ttlSuffixLen = 8 + 4
+
+// ErrInvalidTTLValue is returned when decoding a value that was not
+// encoded by a TTLCodec.
+var ErrInvalidTTLValue = errors.New("value not encoded by a TTLCodec")
+
+// TTLCodec stores an expiry timestamp along with each value, so that every
+// key can have its own TTL, unlike OpenDbWithTTL which applies a single TTL to
+// the whole DB. The expiry is stored after the value as a big-endian unix
+// timestamp in milliseconds, 0 meaning that the value never expires, followed
+// by a 4 bytes marker.
+//
+// Expired values are hidden by GetWithTTL and physically removed by the
+// compaction filter returned by CompactionFilter. Values without the marker
+// never expire, but a plain value may end with the marker by chance, so the
+// filter should only be installed on column families holding nothing but
+// values encoded by a TTLCodec.
+type TTLCodec struct {
+	// Now returns the current time. It can be replaced for tests.
+	Now func() time.Time
+}
+
+// NewTTLCodec creates a TTLCodec using the system clock.
+func NewTTLCodec() *TTLCodec {
+	return &TTLCodec{Now: time.Now}
+}
+
+// Encode returns value followed by its expiry, which is ttl from now.
+// A ttl <= 0 means that the value never expires.
+func (c *TTLCodec) Encode(value []byte, ttl time.Duration) []byte {
+	var expiry uint64
+	if ttl > 0 {
+		expiry = uint64(c.Now().Add(ttl).UnixMilli())
+	}
+	data := make([]byte, len(value)+ttlSuffixLen)
+	copy(data, value)
+	binary.BigEndian.PutUint64(data[len(value):], expiry)
+	copy(data[len(value)+8:], ttlMagic)
+	return data
+}
+
+// Decode splits data into the value and its expiry. The expiry is the zero
+// time if the value never expires. The returned value references data.
+func (c *TTLCodec) Decode(data []byte) (value []byte, expiry time.Time, err error) {
+	if len(data) < ttlSuffixLen || !bytes.HasSuffix(data, []byte(ttlMagic)) {
+		return nil, time.Time{}, ErrInvalidTTLValue
+	}
+	n := len(data) - ttlSuffixLen
+	if ts := binary.BigEndian.Uint64(data[n:]); ts != 0 {
+		expiry = time.UnixMilli(int64(ts))
+	}
+	return data[:n], expiry, nil
+}
+
+// IsExpired returns true if data was encoded with an expiry that has passed.
+// Values without the marker of a TTLCodec never expire.
+func (c *TTLCodec) IsExpired(data []byte) bool {
+	_, expiry, err := c.Decode(data)
+	return err == nil && !expiry.IsZero() && !c.Now().Before(expiry)
+}
+
+// CompactionFilter returns a compaction filter that removes the expired values.
+// It must only be installed on column families holding nothing but values
+// encoded by a TTLCodec, see TTLCodec.
+func (c *TTLCodec) CompactionFilter() CompactionFilter {
+	return ttlCompactionFilter{c}
+}
+
+type ttlCompactionFilter struct {
+	codec *TTLCodec
+}
+
+func (f ttlCompactionFilter) Filter(level int, key, val []byte) (remove bool, newVal []byte) {
+	return f.codec.IsExpired(val), nil
+}
+
+func (f ttlCompactionFilter) Name() string { return "gorocksdb.ttl" }
+
+// PutWithTTL writes data associated with a key to the database, expiring
+// after ttl. A ttl <= 0 means that the value never expires.
+func (db *DB) PutWithTTL(opts *WriteOptions, codec *TTLCodec, key, value []byte, ttl time.Duration) error {
+	return db.Put(opts, key, codec.Encode(value, ttl))
+}
+
+// GetWithTTL returns the data associated with a key written by PutWithTTL.
+// Expired values are reported as missing, even if they are not removed yet.
+func (db *DB) GetWithTTL(opts *ReadOptions, codec *TTLCodec, key []byte) (*Slice, error) {
+	slice, err := db.Get(opts, key)
+	if err != nil || !slice.Exists() {
+		return slice, err
+	}
+	data := slice.Data()
+	if _, _, err := codec.Decode(data); err != nil {
+		slice.Free()
+		return nil, err
+	}
+	if codec.IsExpired(data) {
+		slice.Free()
+		return NewSlice(nil, 0), nil
+	}
+	slice.size -= ttlSuffixLen
+	return slice, nil
+}
+
+// PutWithTTL queues a key-value pair expiring after ttl.
+// A ttl <= 0 means that the value never expires.
+func (wb *WriteBatch) PutWithTTL(codec *TTLCodec, key, value []byte, ttl time.Duration) {
+	wb.Put(key, codec.Encode(value, ttl))
+}
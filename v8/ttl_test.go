@@ -0,0 +1,97 @@
+package gorocksdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestTTLCodec(t *testing.T) {
+	now := time.Unix(1000, 0)
+	codec := &TTLCodec{Now: func() time.Time { return now }}
+
+	data := codec.Encode([]byte("foo"), time.Minute)
+	value, expiry, err := codec.Decode(data)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, value, []byte("foo"))
+	ensure.True(t, expiry.Equal(now.Add(time.Minute)))
+	ensure.False(t, codec.IsExpired(data))
+
+	now = now.Add(time.Minute)
+	ensure.True(t, codec.IsExpired(data))
+
+	// values without TTL never expire
+	data = codec.Encode([]byte("bar"), 0)
+	_, expiry, err = codec.Decode(data)
+	ensure.Nil(t, err)
+	ensure.True(t, expiry.IsZero())
+	ensure.False(t, codec.IsExpired(data))
+
+	// sub-second TTLs are kept
+	data = codec.Encode([]byte("baz"), 250*time.Millisecond)
+	_, expiry, err = codec.Decode(data)
+	ensure.Nil(t, err)
+	ensure.True(t, expiry.Equal(now.Add(250*time.Millisecond)))
+	ensure.False(t, codec.IsExpired(data))
+	now = now.Add(250 * time.Millisecond)
+	ensure.True(t, codec.IsExpired(data))
+
+	_, _, err = codec.Decode([]byte("short"))
+	ensure.DeepEqual(t, err, ErrInvalidTTLValue)
+
+	// plain values never expire, whatever their last bytes
+	plain := []byte("value\x00\x00\x00\x00\x00\x00\x00\x01")
+	_, _, err = codec.Decode(plain)
+	ensure.DeepEqual(t, err, ErrInvalidTTLValue)
+	ensure.False(t, codec.IsExpired(plain))
+}
+
+func TestDBWithPerKeyTTL(t *testing.T) {
+	now := time.Unix(1000, 0)
+	codec := &TTLCodec{Now: func() time.Time { return now }}
+	db := newTestDB(t, "TestDBWithPerKeyTTL", func(opts *Options) {
+		opts.SetCompactionFilter(codec.CompactionFilter())
+	})
+	defer db.Close()
+
+	var (
+		wo = NewDefaultWriteOptions()
+		ro = NewDefaultReadOptions()
+	)
+	ensure.Nil(t, db.PutWithTTL(wo, codec, []byte("short"), []byte("a"), time.Second))
+	wb := NewWriteBatch()
+	defer wb.Destroy()
+	wb.PutWithTTL(codec, []byte("long"), []byte("b"), time.Hour)
+	wb.PutWithTTL(codec, []byte("forever"), []byte("c"), 0)
+	ensure.Nil(t, db.Write(wo, wb))
+
+	v, err := db.GetWithTTL(ro, codec, []byte("short"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v.Data(), []byte("a"))
+	v.Free()
+
+	now = now.Add(time.Minute)
+
+	// the expired value is hidden before it is compacted
+	v, err = db.GetWithTTL(ro, codec, []byte("short"))
+	ensure.Nil(t, err)
+	ensure.False(t, v.Exists())
+	v, err = db.Get(ro, []byte("short"))
+	ensure.Nil(t, err)
+	ensure.True(t, v.Exists())
+	v.Free()
+
+	// and removed by the compaction
+	db.CompactRange(Range{nil, nil})
+	v, err = db.Get(ro, []byte("short"))
+	ensure.Nil(t, err)
+	ensure.False(t, v.Exists())
+
+	for key, value := range map[string]string{"long": "b", "forever": "c"} {
+		v, err := db.GetWithTTL(ro, codec, []byte(key))
+		ensure.Nil(t, err)
+		ensure.DeepEqual(t, v.Data(), []byte(value))
+		v.Free()
+	}
+}
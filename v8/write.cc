@@ -0,0 +1,222 @@
+#include "gorocksdb_internal.h"
+#include "rocksdb/utilities/optimistic_transaction_db.h"
+#include "rocksdb/utilities/transaction.h"
+#include "rocksdb/utilities/transaction_db.h"
+
+// The writes of the RocksDB C API, returning whether they failed because of
+// the lack of space, which their error message does not reliably tell.
+
+using rocksdb::ColumnFamilyHandle;
+using rocksdb::DB;
+using rocksdb::FlushOptions;
+using rocksdb::OptimisticTransactionDB;
+using rocksdb::Slice;
+using rocksdb::Transaction;
+using rocksdb::TransactionDB;
+using rocksdb::WriteBatch;
+using rocksdb::WriteOptions;
+
+extern "C" {
+
+unsigned char gorocksdb_put(rocksdb_t* db, rocksdb_writeoptions_t* options,
+                            const char* key, size_t keylen, const char* val,
+                            size_t vallen, char** errptr) {
+  return gorocksdb::SaveWriteError(
+      errptr, gorocksdb::Rep<DB>(db)->Put(
+                  gorocksdb::RepValue<WriteOptions>(options),
+                  Slice(key, keylen), Slice(val, vallen)));
+}
+
+unsigned char gorocksdb_put_with_ts(rocksdb_t* db,
+                                    rocksdb_writeoptions_t* options,
+                                    const char* key, size_t keylen,
+                                    const char* ts, size_t tslen,
+                                    const char* val, size_t vallen,
+                                    char** errptr) {
+  return gorocksdb::SaveWriteError(
+      errptr, gorocksdb::Rep<DB>(db)->Put(
+                  gorocksdb::RepValue<WriteOptions>(options),
+                  Slice(key, keylen), Slice(ts, tslen), Slice(val, vallen)));
+}
+
+unsigned char gorocksdb_put_cf(rocksdb_t* db, rocksdb_writeoptions_t* options,
+                               rocksdb_column_family_handle_t* cf,
+                               const char* key, size_t keylen, const char* val,
+                               size_t vallen, char** errptr) {
+  return gorocksdb::SaveWriteError(
+      errptr, gorocksdb::Rep<DB>(db)->Put(
+                  gorocksdb::RepValue<WriteOptions>(options),
+                  gorocksdb::Rep<ColumnFamilyHandle>(cf), Slice(key, keylen),
+                  Slice(val, vallen)));
+}
+
+unsigned char gorocksdb_delete(rocksdb_t* db, rocksdb_writeoptions_t* options,
+                               const char* key, size_t keylen, char** errptr) {
+  return gorocksdb::SaveWriteError(
+      errptr,
+      gorocksdb::Rep<DB>(db)->Delete(gorocksdb::RepValue<WriteOptions>(options),
+                                     Slice(key, keylen)));
+}
+
+unsigned char gorocksdb_delete_cf(rocksdb_t* db,
+                                  rocksdb_writeoptions_t* options,
+                                  rocksdb_column_family_handle_t* cf,
+                                  const char* key, size_t keylen,
+                                  char** errptr) {
+  return gorocksdb::SaveWriteError(
+      errptr,
+      gorocksdb::Rep<DB>(db)->Delete(gorocksdb::RepValue<WriteOptions>(options),
+                                     gorocksdb::Rep<ColumnFamilyHandle>(cf),
+                                     Slice(key, keylen)));
+}
+
+unsigned char gorocksdb_merge(rocksdb_t* db, rocksdb_writeoptions_t* options,
+                              const char* key, size_t keylen, const char* val,
+                              size_t vallen, char** errptr) {
+  return gorocksdb::SaveWriteError(
+      errptr, gorocksdb::Rep<DB>(db)->Merge(
+                  gorocksdb::RepValue<WriteOptions>(options),
+                  Slice(key, keylen), Slice(val, vallen)));
+}
+
+unsigned char gorocksdb_merge_cf(rocksdb_t* db,
+                                 rocksdb_writeoptions_t* options,
+                                 rocksdb_column_family_handle_t* cf,
+                                 const char* key, size_t keylen,
+                                 const char* val, size_t vallen,
+                                 char** errptr) {
+  return gorocksdb::SaveWriteError(
+      errptr, gorocksdb::Rep<DB>(db)->Merge(
+                  gorocksdb::RepValue<WriteOptions>(options),
+                  gorocksdb::Rep<ColumnFamilyHandle>(cf), Slice(key, keylen),
+                  Slice(val, vallen)));
+}
+
+unsigned char gorocksdb_write(rocksdb_t* db, rocksdb_writeoptions_t* options,
+                              rocksdb_writebatch_t* batch, char** errptr) {
+  return gorocksdb::SaveWriteError(
+      errptr,
+      gorocksdb::Rep<DB>(db)->Write(gorocksdb::RepValue<WriteOptions>(options),
+                                    &gorocksdb::RepValue<WriteBatch>(batch)));
+}
+
+unsigned char gorocksdb_flush(rocksdb_t* db, rocksdb_flushoptions_t* options,
+                              char** errptr) {
+  return gorocksdb::SaveWriteError(
+      errptr,
+      gorocksdb::Rep<DB>(db)->Flush(gorocksdb::RepValue<FlushOptions>(options)));
+}
+
+unsigned char gorocksdb_flush_cf(rocksdb_t* db,
+                                 rocksdb_flushoptions_t* options,
+                                 rocksdb_column_family_handle_t* cf,
+                                 char** errptr) {
+  return gorocksdb::SaveWriteError(
+      errptr,
+      gorocksdb::Rep<DB>(db)->Flush(gorocksdb::RepValue<FlushOptions>(options),
+                                    gorocksdb::Rep<ColumnFamilyHandle>(cf)));
+}
+
+unsigned char gorocksdb_transactiondb_put(rocksdb_transactiondb_t* db,
+                                          rocksdb_writeoptions_t* options,
+                                          const char* key, size_t keylen,
+                                          const char* val, size_t vallen,
+                                          char** errptr) {
+  return gorocksdb::SaveWriteError(
+      errptr, gorocksdb::Rep<TransactionDB>(db)->Put(
+                  gorocksdb::RepValue<WriteOptions>(options),
+                  Slice(key, keylen), Slice(val, vallen)));
+}
+
+unsigned char gorocksdb_transactiondb_put_cf(
+    rocksdb_transactiondb_t* db, rocksdb_writeoptions_t* options,
+    rocksdb_column_family_handle_t* cf, const char* key, size_t keylen,
+    const char* val, size_t vallen, char** errptr) {
+  return gorocksdb::SaveWriteError(
+      errptr, gorocksdb::Rep<TransactionDB>(db)->Put(
+                  gorocksdb::RepValue<WriteOptions>(options),
+                  gorocksdb::Rep<ColumnFamilyHandle>(cf), Slice(key, keylen),
+                  Slice(val, vallen)));
+}
+
+unsigned char gorocksdb_transactiondb_write(rocksdb_transactiondb_t* db,
+                                            rocksdb_writeoptions_t* options,
+                                            rocksdb_writebatch_t* batch,
+                                            char** errptr) {
+  return gorocksdb::SaveWriteError(
+      errptr, gorocksdb::Rep<TransactionDB>(db)->Write(
+                  gorocksdb::RepValue<WriteOptions>(options),
+                  &gorocksdb::RepValue<WriteBatch>(batch)));
+}
+
+unsigned char gorocksdb_transactiondb_delete(rocksdb_transactiondb_t* db,
+                                             rocksdb_writeoptions_t* options,
+                                             const char* key, size_t keylen,
+                                             char** errptr) {
+  return gorocksdb::SaveWriteError(
+      errptr, gorocksdb::Rep<TransactionDB>(db)->Delete(
+                  gorocksdb::RepValue<WriteOptions>(options),
+                  Slice(key, keylen)));
+}
+
+unsigned char gorocksdb_transactiondb_delete_cf(
+    rocksdb_transactiondb_t* db, rocksdb_writeoptions_t* options,
+    rocksdb_column_family_handle_t* cf, const char* key, size_t keylen,
+    char** errptr) {
+  return gorocksdb::SaveWriteError(
+      errptr, gorocksdb::Rep<TransactionDB>(db)->Delete(
+                  gorocksdb::RepValue<WriteOptions>(options),
+                  gorocksdb::Rep<ColumnFamilyHandle>(cf), Slice(key, keylen)));
+}
+
+unsigned char gorocksdb_optimistictransactiondb_write(
+    rocksdb_optimistictransactiondb_t* db, rocksdb_writeoptions_t* options,
+    rocksdb_writebatch_t* batch, char** errptr) {
+  return gorocksdb::SaveWriteError(
+      errptr, gorocksdb::Rep<OptimisticTransactionDB>(db)->Write(
+                  gorocksdb::RepValue<WriteOptions>(options),
+                  &gorocksdb::RepValue<WriteBatch>(batch)));
+}
+
+unsigned char gorocksdb_transaction_commit(rocksdb_transaction_t* txn,
+                                           char** errptr) {
+  return gorocksdb::SaveWriteError(errptr,
+                                   gorocksdb::Rep<Transaction>(txn)->Commit());
+}
+
+unsigned char gorocksdb_transaction_put(rocksdb_transaction_t* txn,
+                                        const char* key, size_t keylen,
+                                        const char* val, size_t vallen,
+                                        char** errptr) {
+  return gorocksdb::SaveWriteError(
+      errptr, gorocksdb::Rep<Transaction>(txn)->Put(Slice(key, keylen),
+                                                    Slice(val, vallen)));
+}
+
+unsigned char gorocksdb_transaction_put_cf(rocksdb_transaction_t* txn,
+                                           rocksdb_column_family_handle_t* cf,
+                                           const char* key, size_t keylen,
+                                           const char* val, size_t vallen,
+                                           char** errptr) {
+  return gorocksdb::SaveWriteError(
+      errptr, gorocksdb::Rep<Transaction>(txn)->Put(
+                  gorocksdb::Rep<ColumnFamilyHandle>(cf), Slice(key, keylen),
+                  Slice(val, vallen)));
+}
+
+unsigned char gorocksdb_transaction_delete(rocksdb_transaction_t* txn,
+                                           const char* key, size_t keylen,
+                                           char** errptr) {
+  return gorocksdb::SaveWriteError(
+      errptr, gorocksdb::Rep<Transaction>(txn)->Delete(Slice(key, keylen)));
+}
+
+unsigned char gorocksdb_transaction_delete_cf(
+    rocksdb_transaction_t* txn, rocksdb_column_family_handle_t* cf,
+    const char* key, size_t keylen, char** errptr) {
+  return gorocksdb::SaveWriteError(
+      errptr, gorocksdb::Rep<Transaction>(txn)->Delete(
+                  gorocksdb::Rep<ColumnFamilyHandle>(cf), Slice(key, keylen)));
+}
+
+}  // extern "C"
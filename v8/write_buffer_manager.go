@@ -0,0 +1,75 @@
+package gorocksdb
+
+// #include "rocksdb/c.h"
+import "C"
+
+// WriteBufferManager caps the memory used by memtables. It can be shared by
+// the Options of many DBs and column families to enforce a process-wide
+// budget, unlike SetDbWriteBufferSize which applies to a single DB.
+type WriteBufferManager struct {
+	c *C.rocksdb_write_buffer_manager_t
+}
+
+// NewWriteBufferManager creates a WriteBufferManager flushing memtables once
+// their memory usage exceeds bufferSize. A bufferSize of 0 disables the
+// limit but still tracks the usage. If allowStall is true, writes are
+// stalled when the usage exceeds bufferSize until flushes bring it down.
+func NewWriteBufferManager(bufferSize uint64, allowStall bool) *WriteBufferManager {
+	return &WriteBufferManager{C.rocksdb_write_buffer_manager_create(C.size_t(bufferSize), C.bool(allowStall))}
+}
+
+// NewWriteBufferManagerWithCache creates a WriteBufferManager which also
+// charges the memtable memory to cache, so that the block cache and the
+// memtables share a single memory budget.
+func NewWriteBufferManagerWithCache(bufferSize uint64, cache *Cache, allowStall bool) *WriteBufferManager {
+	return &WriteBufferManager{C.rocksdb_write_buffer_manager_create_with_cache(C.size_t(bufferSize), cache.c, C.bool(allowStall))}
+}
+
+// Enabled returns true if the memtable memory is limited.
+func (wbm *WriteBufferManager) Enabled() bool {
+	return bool(C.rocksdb_write_buffer_manager_enabled(wbm.c))
+}
+
+// CostToCache returns true if the memtable memory is charged to a Cache.
+func (wbm *WriteBufferManager) CostToCache() bool {
+	return bool(C.rocksdb_write_buffer_manager_cost_to_cache(wbm.c))
+}
+
+// GetMemoryUsage returns the memory used by all the memtables.
+func (wbm *WriteBufferManager) GetMemoryUsage() uint64 {
+	return uint64(C.rocksdb_write_buffer_manager_memory_usage(wbm.c))
+}
+
+// GetMutableMemtableMemoryUsage returns the memory used by the memtables
+// that are not being flushed.
+func (wbm *WriteBufferManager) GetMutableMemtableMemoryUsage() uint64 {
+	return uint64(C.rocksdb_write_buffer_manager_mutable_memtable_memory_usage(wbm.c))
+}
+
+// GetDummyEntriesInCacheUsage returns the memory charged to the Cache.
+func (wbm *WriteBufferManager) GetDummyEntriesInCacheUsage() uint64 {
+	return uint64(C.rocksdb_write_buffer_manager_dummy_entries_in_cache_usage(wbm.c))
+}
+
+// GetBufferSize returns the memtable memory limit.
+func (wbm *WriteBufferManager) GetBufferSize() uint64 {
+	return uint64(C.rocksdb_write_buffer_manager_buffer_size(wbm.c))
+}
+
+// SetBufferSize changes the memtable memory limit.
+func (wbm *WriteBufferManager) SetBufferSize(bufferSize uint64) {
+	C.rocksdb_write_buffer_manager_set_buffer_size(wbm.c, C.size_t(bufferSize))
+}
+
+// SetAllowStall changes whether writes are stalled when the memtable memory
+// usage exceeds the limit.
+func (wbm *WriteBufferManager) SetAllowStall(allowStall bool) {
+	C.rocksdb_write_buffer_manager_set_allow_stall(wbm.c, C.bool(allowStall))
+}
+
+// Destroy deallocates the WriteBufferManager object. The DBs using it keep
+// their own reference.
+func (wbm *WriteBufferManager) Destroy() {
+	C.rocksdb_write_buffer_manager_destroy(wbm.c)
+	wbm.c = nil
+}
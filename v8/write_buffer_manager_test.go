@@ -0,0 +1,41 @@
+package gorocksdb
+
+import (
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestWriteBufferManager(t *testing.T) {
+	cache := NewLRUCache(64 << 20)
+	defer cache.Destroy()
+	wbm := NewWriteBufferManagerWithCache(16<<20, cache, false)
+	defer wbm.Destroy()
+	ensure.True(t, wbm.Enabled())
+	ensure.True(t, wbm.CostToCache())
+	ensure.DeepEqual(t, wbm.GetBufferSize(), uint64(16<<20))
+
+	applyOpts := func(opts *Options) {
+		opts.SetWriteBufferManager(wbm)
+	}
+	db1 := newTestDB(t, "TestWriteBufferManager1", applyOpts)
+	defer db1.Close()
+	db2 := newTestDB(t, "TestWriteBufferManager2", applyOpts)
+	defer db2.Close()
+
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	before := wbm.GetMemoryUsage()
+	value := make([]byte, 4096)
+	for _, db := range []*DB{db1, db2} {
+		for i := 0; i < 100; i++ {
+			ensure.Nil(t, db.Put(wo, []byte{byte(i)}, value))
+		}
+	}
+	ensure.True(t, wbm.GetMemoryUsage() > before)
+	ensure.True(t, wbm.GetMutableMemtableMemoryUsage() > 0)
+
+	wbm.SetBufferSize(32 << 20)
+	ensure.DeepEqual(t, wbm.GetBufferSize(), uint64(32<<20))
+	wbm.SetAllowStall(true)
+}